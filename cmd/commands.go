@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"infotecstechtask/init/migrations"
+	"infotecstechtask/internal/config"
+	"infotecstechtask/pkg/database"
+	"infotecstechtask/pkg/logger"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	audrepo "infotecstechtask/internal/audit/repository"
+	audservice "infotecstechtask/internal/audit/service"
+)
+
+const usage = `Usage:
+  infotecs-tech-task [flags]         start http server, see --help for configuration flags
+  infotecs-tech-task audit verify    verify audit log hash chain
+  infotecs-tech-task migrate up      apply all pending migrations
+  infotecs-tech-task migrate down [N]
+                                     roll back N last migrations, 1 by default
+  infotecs-tech-task migrate status  print schema version and pending migrations
+  infotecs-tech-task migrate force V mark schema as clean at version V without running migrations`
+
+// Функция для выполнения служебных команд, возвращает код завершения процесса
+func runCommand(args []string) int {
+	switch {
+	case len(args) == 2 && args[0] == "audit" && args[1] == "verify":
+		return auditVerify()
+	case len(args) >= 2 && args[0] == "migrate":
+		return migrate(args[1:])
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+}
+
+// Функция создает клиент БД по конфигурации из файла CONFIG_FILE и переменных окружения
+// Служебные команды не принимают флаги конфигурации. При ошибке возвращается код завершения процесса
+func connect(ctx context.Context) (*database.Client, int) {
+	cfg, _, err := config.Load(nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		return nil, 2
+	}
+	logger.Setup(os.Stderr, cfg.Log)
+
+	dbClient, err := database.NewClient(ctx, cfg.Database)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create DB client: %v\n", err)
+		return nil, 1
+	}
+
+	return dbClient, 0
+}
+
+// Команда проходит по всей цепочке журнала аудита и выводит результат проверки в формате JSON
+// Возвращает 1, если цепочка нарушена
+func auditVerify() int {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	dbClient, code := connect(ctx)
+	if dbClient == nil {
+		return code
+	}
+	defer dbClient.Close()
+
+	auditService := audservice.NewAuditService(audrepo.NewAuditRepository(dbClient))
+
+	result, err := auditService.VerifyChain(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to verify audit log: %v\n", err)
+		return 1
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(result)
+
+	if !result.Valid {
+		return 1
+	}
+
+	return 0
+}
+
+// Команда применяет, откатывает или показывает состояние встроенных миграций
+// Результат выводится в формате JSON
+func migrate(args []string) int {
+	steps := 1
+	var version int64
+	var err error
+	switch {
+	case args[0] == "up" && len(args) == 1, args[0] == "status" && len(args) == 1:
+	case args[0] == "down" && len(args) <= 2:
+		if len(args) == 2 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				fmt.Fprintln(os.Stderr, "Number of migrations to roll back must be a positive integer")
+				return 2
+			}
+		}
+	case args[0] == "force" && len(args) == 2:
+		version, err = strconv.ParseInt(args[1], 10, 64)
+		if err != nil || version < 0 {
+			fmt.Fprintln(os.Stderr, "Version must be a non-negative integer")
+			return 2
+		}
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	dbClient, code := connect(ctx)
+	if dbClient == nil {
+		return code
+	}
+	defer dbClient.Close()
+
+	migrator, err := database.NewMigrator(dbClient, migrations.FS)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load migrations: %v\n", err)
+		return 1
+	}
+
+	var result any
+	switch args[0] {
+	case "up":
+		var applied []int64
+		applied, err = migrator.Up(ctx)
+		result = map[string][]int64{"applied": nonNil(applied)}
+	case "down":
+		var reverted []int64
+		reverted, err = migrator.Down(ctx, steps)
+		result = map[string][]int64{"reverted": nonNil(reverted)}
+	case "force":
+		err = migrator.Force(ctx, version)
+		result = map[string]int64{"version": version}
+	case "status":
+		result, err = migrator.Status(ctx)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to run migrate %s: %v\n", args[0], err)
+		return 1
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(result)
+
+	return 0
+}
+
+func nonNil(versions []int64) []int64 {
+	if versions == nil {
+		return []int64{}
+	}
+	return versions
+}
@@ -1,17 +1,48 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"infotecstechtask/internal/config"
 	"infotecstechtask/internal/server"
-	"log"
+	"infotecstechtask/pkg/logger"
 	"os"
+	"strings"
 )
 
+// Без аргументов или с флагами конфигурации запускается http сервер, иначе выполняется одна из служебных команд
+// Логгер настраивается после загрузки конфигурации, ошибки конфигурации выводятся в stderr как есть
 func main() {
-	port := os.Getenv("APP_PORT")
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		os.Exit(runCommand(os.Args[1:]))
+	}
 
-	app := server.NewApp()
+	os.Exit(run(os.Args[1:]))
+}
 
-	if err := app.Run(port); err != nil {
-		log.Fatalf("%s", err.Error())
+// Функция загружает конфигурацию и запускает сервер, возвращает код завершения процесса
+// С флагом --print-config сервер не запускается, выводится итоговая конфигурация
+func run(args []string) int {
+	cfg, options, err := config.Load(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
 	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid configuration:\n%v\n", err)
+		return 2
+	}
+
+	if options.PrintConfig {
+		if err := config.Print(os.Stdout, cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to print configuration: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	logger.Setup(os.Stderr, cfg.Log)
+	app := server.NewApp(cfg)
+
+	return app.Run()
 }
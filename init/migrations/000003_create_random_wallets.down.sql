@@ -0,0 +1,2 @@
+-- Кошельки, созданные миграцией, не отличаются от остальных и могут участвовать в транзакциях,
+-- поэтому откат данные не изменяет. Расширение pgcrypto также не удаляется
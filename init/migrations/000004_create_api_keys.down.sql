@@ -0,0 +1,7 @@
+DROP INDEX tr_to_address_idx;
+DROP INDEX tr_from_address_idx;
+DROP INDEX wl_owner_id_idx;
+
+ALTER TABLE wallets DROP COLUMN owner_id;
+
+DROP TABLE api_keys;
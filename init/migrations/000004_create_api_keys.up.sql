@@ -0,0 +1,20 @@
+CREATE TABLE api_keys
+(
+    key_hash    VARCHAR(64) PRIMARY KEY,
+    owner_id    VARCHAR(64) NOT NULL,
+    role        VARCHAR NOT NULL CHECK (role IN ('client', 'viewer', 'operator', 'admin')),
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE wallets ADD COLUMN owner_id VARCHAR(64);
+
+CREATE INDEX wl_owner_id_idx ON wallets (owner_id);
+CREATE INDEX tr_from_address_idx ON transactions (from_address);
+CREATE INDEX tr_to_address_idx ON transactions (to_address);
+
+COMMENT ON TABLE api_keys IS 'Таблица для хранения API ключей';
+COMMENT ON COLUMN api_keys.key_hash IS 'SHA-256 хеш ключа';
+COMMENT ON COLUMN api_keys.owner_id IS 'Идентификатор владельца ключа';
+COMMENT ON COLUMN api_keys.role IS 'Роль владельца ключа';
+COMMENT ON COLUMN api_keys.created_at IS 'Время создания ключа';
+COMMENT ON COLUMN wallets.owner_id IS 'Идентификатор владельца кошелька';
@@ -0,0 +1,9 @@
+DROP TABLE adjustments;
+
+-- Транзакции корректировок не укладываются в прежнюю схему с обязательными отправителем и получателем
+DELETE FROM transactions WHERE type = 'adjustment';
+
+ALTER TABLE transactions DROP CONSTRAINT tr_addresses_check;
+ALTER TABLE transactions ALTER COLUMN from_address SET NOT NULL;
+ALTER TABLE transactions ALTER COLUMN to_address SET NOT NULL;
+ALTER TABLE transactions DROP COLUMN type;
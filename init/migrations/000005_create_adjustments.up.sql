@@ -0,0 +1,45 @@
+ALTER TABLE transactions ADD COLUMN type VARCHAR NOT NULL DEFAULT 'transfer' CHECK (type IN ('transfer', 'adjustment'));
+ALTER TABLE transactions ALTER COLUMN from_address DROP NOT NULL;
+ALTER TABLE transactions ALTER COLUMN to_address DROP NOT NULL;
+ALTER TABLE transactions ADD CONSTRAINT tr_addresses_check CHECK (
+    (type = 'transfer' AND from_address IS NOT NULL AND to_address IS NOT NULL)
+    OR (type = 'adjustment' AND (from_address IS NULL) <> (to_address IS NULL))
+);
+
+COMMENT ON COLUMN transactions.type IS 'Тип транзакции: перевод или корректировка';
+
+CREATE TABLE adjustments
+(
+    id              VARCHAR(64) PRIMARY KEY,
+    wallet_id       VARCHAR(64) NOT NULL,
+    direction       VARCHAR NOT NULL CHECK (direction IN ('credit', 'debit')),
+    amount          INTEGER NOT NULL CHECK (amount > 0),
+    reason_code     VARCHAR NOT NULL,
+    comment         VARCHAR NOT NULL DEFAULT '',
+    status          VARCHAR NOT NULL CHECK (status IN ('pending', 'approved', 'rejected')),
+    created_by      VARCHAR(64) NOT NULL,
+    reviewed_by     VARCHAR(64),
+    transaction_id  VARCHAR(64),
+    created_at      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reviewed_at     TIMESTAMP,
+
+    FOREIGN KEY (wallet_id) REFERENCES wallets(id),
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id),
+    CHECK (reviewed_by IS NULL OR reviewed_by <> created_by)
+);
+
+CREATE INDEX adj_status_created_at_idx ON adjustments (status, created_at DESC);
+
+COMMENT ON TABLE adjustments IS 'Таблица для хранения заявок на ручную корректировку баланса';
+COMMENT ON COLUMN adjustments.id IS 'Идентификатор заявки';
+COMMENT ON COLUMN adjustments.wallet_id IS 'Идентификатор кошелька';
+COMMENT ON COLUMN adjustments.direction IS 'Направление корректировки: зачисление или списание';
+COMMENT ON COLUMN adjustments.amount IS 'Сумма корректировки (в копейках)';
+COMMENT ON COLUMN adjustments.reason_code IS 'Код причины корректировки';
+COMMENT ON COLUMN adjustments.comment IS 'Комментарий';
+COMMENT ON COLUMN adjustments.status IS 'Статус заявки';
+COMMENT ON COLUMN adjustments.created_by IS 'Оператор, создавший заявку';
+COMMENT ON COLUMN adjustments.reviewed_by IS 'Оператор, рассмотревший заявку';
+COMMENT ON COLUMN adjustments.transaction_id IS 'Транзакция, созданная при одобрении заявки';
+COMMENT ON COLUMN adjustments.created_at IS 'Время создания заявки';
+COMMENT ON COLUMN adjustments.reviewed_at IS 'Время рассмотрения заявки';
@@ -0,0 +1,53 @@
+CREATE TABLE audit_log
+(
+    id              BIGINT PRIMARY KEY,
+    actor_id        VARCHAR(64),
+    actor_role      VARCHAR NOT NULL DEFAULT '',
+    ip              VARCHAR NOT NULL DEFAULT '',
+    request_id      VARCHAR NOT NULL DEFAULT '',
+    action          VARCHAR NOT NULL,
+    entity_type     VARCHAR NOT NULL,
+    entity_id       VARCHAR(64) NOT NULL,
+    before_state    JSON NOT NULL,
+    after_state     JSON NOT NULL,
+    created_at      TIMESTAMP NOT NULL,
+    prev_hash       VARCHAR(64) NOT NULL,
+    hash            VARCHAR(64) NOT NULL
+);
+
+CREATE INDEX al_entity_idx ON audit_log (entity_type, entity_id);
+
+CREATE TABLE audit_chain_head
+(
+    id          INTEGER PRIMARY KEY CHECK (id = 1),
+    last_id     BIGINT NOT NULL,
+    last_hash   VARCHAR(64) NOT NULL
+);
+
+INSERT INTO audit_chain_head (id, last_id, last_hash) VALUES (1, 0, repeat('0', 64));
+
+CREATE FUNCTION audit_log_forbid_change() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_forbid_change();
+
+COMMENT ON TABLE audit_log IS 'Журнал аудита изменений, доступен только для добавления';
+COMMENT ON COLUMN audit_log.id IS 'Порядковый номер записи, идёт без пропусков';
+COMMENT ON COLUMN audit_log.actor_id IS 'Идентификатор владельца API ключа, выполнившего действие';
+COMMENT ON COLUMN audit_log.actor_role IS 'Роль владельца API ключа';
+COMMENT ON COLUMN audit_log.ip IS 'IP адрес клиента';
+COMMENT ON COLUMN audit_log.request_id IS 'Идентификатор запроса';
+COMMENT ON COLUMN audit_log.action IS 'Действие';
+COMMENT ON COLUMN audit_log.entity_type IS 'Тип изменённой сущности';
+COMMENT ON COLUMN audit_log.entity_id IS 'Идентификатор изменённой сущности';
+COMMENT ON COLUMN audit_log.before_state IS 'Состояние до изменения';
+COMMENT ON COLUMN audit_log.after_state IS 'Состояние после изменения';
+COMMENT ON COLUMN audit_log.created_at IS 'Время изменения';
+COMMENT ON COLUMN audit_log.prev_hash IS 'Хеш предыдущей записи';
+COMMENT ON COLUMN audit_log.hash IS 'SHA-256 хеш записи вместе с хешем предыдущей';
+COMMENT ON TABLE audit_chain_head IS 'Последняя запись цепочки журнала аудита';
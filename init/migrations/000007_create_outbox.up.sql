@@ -0,0 +1,25 @@
+CREATE TABLE outbox
+(
+    id              BIGSERIAL PRIMARY KEY,
+    wallet_id       VARCHAR(64) NOT NULL,
+    event_type      VARCHAR NOT NULL,
+    payload         JSON NOT NULL,
+    created_at      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error      VARCHAR NOT NULL DEFAULT '',
+    published_at    TIMESTAMP
+);
+
+CREATE INDEX ob_unpublished_idx ON outbox (id) WHERE published_at IS NULL;
+
+COMMENT ON TABLE outbox IS 'События, ожидающие публикации, записываются в той же транзакции, что и изменение';
+COMMENT ON COLUMN outbox.id IS 'Порядковый номер события';
+COMMENT ON COLUMN outbox.wallet_id IS 'Кошелёк, в рамках которого соблюдается порядок доставки';
+COMMENT ON COLUMN outbox.event_type IS 'Тип события';
+COMMENT ON COLUMN outbox.payload IS 'Данные события';
+COMMENT ON COLUMN outbox.created_at IS 'Время создания события';
+COMMENT ON COLUMN outbox.attempts IS 'Количество попыток публикации';
+COMMENT ON COLUMN outbox.next_attempt_at IS 'Время, раньше которого событие не будет опубликовано повторно';
+COMMENT ON COLUMN outbox.last_error IS 'Ошибка последней неудачной попытки публикации';
+COMMENT ON COLUMN outbox.published_at IS 'Время успешной публикации';
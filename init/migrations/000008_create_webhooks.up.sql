@@ -0,0 +1,54 @@
+CREATE TABLE webhook_subscriptions
+(
+    id          VARCHAR(64) PRIMARY KEY,
+    owner_id    VARCHAR(64) NOT NULL,
+    url         VARCHAR(2048) NOT NULL,
+    secret      VARCHAR(64) NOT NULL,
+    event_types VARCHAR[] NOT NULL,
+    wallet_ids  VARCHAR(64)[] NOT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX ws_owner_id_idx ON webhook_subscriptions (owner_id);
+CREATE INDEX ws_wallet_ids_idx ON webhook_subscriptions USING GIN (wallet_ids);
+
+CREATE TABLE webhook_deliveries
+(
+    id               VARCHAR(64) PRIMARY KEY,
+    subscription_id  VARCHAR(64) NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_id         BIGINT NOT NULL,
+    event_type       VARCHAR NOT NULL,
+    payload          JSON NOT NULL,
+    status           VARCHAR NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts         INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_status_code INTEGER NOT NULL DEFAULT 0,
+    last_error       VARCHAR NOT NULL DEFAULT '',
+    created_at       TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at     TIMESTAMP,
+    UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX wd_due_idx ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+
+COMMENT ON TABLE webhook_subscriptions IS 'Подписки клиентов на события кошельков';
+COMMENT ON COLUMN webhook_subscriptions.id IS 'Идентификатор подписки';
+COMMENT ON COLUMN webhook_subscriptions.owner_id IS 'Идентификатор владельца подписки';
+COMMENT ON COLUMN webhook_subscriptions.url IS 'Адрес, на который доставляются события';
+COMMENT ON COLUMN webhook_subscriptions.secret IS 'Секрет для HMAC подписи доставок';
+COMMENT ON COLUMN webhook_subscriptions.event_types IS 'Типы событий, на которые оформлена подписка';
+COMMENT ON COLUMN webhook_subscriptions.wallet_ids IS 'Кошельки, события которых доставляются';
+COMMENT ON COLUMN webhook_subscriptions.created_at IS 'Время создания подписки';
+COMMENT ON TABLE webhook_deliveries IS 'Доставки событий на webhook';
+COMMENT ON COLUMN webhook_deliveries.id IS 'Идентификатор доставки';
+COMMENT ON COLUMN webhook_deliveries.subscription_id IS 'Подписка, по которой доставляется событие';
+COMMENT ON COLUMN webhook_deliveries.event_id IS 'Идентификатор события в outbox';
+COMMENT ON COLUMN webhook_deliveries.event_type IS 'Тип события';
+COMMENT ON COLUMN webhook_deliveries.payload IS 'Данные события';
+COMMENT ON COLUMN webhook_deliveries.status IS 'Статус доставки';
+COMMENT ON COLUMN webhook_deliveries.attempts IS 'Количество попыток доставки';
+COMMENT ON COLUMN webhook_deliveries.next_attempt_at IS 'Время следующей попытки доставки';
+COMMENT ON COLUMN webhook_deliveries.last_status_code IS 'HTTP статус ответа на последнюю попытку';
+COMMENT ON COLUMN webhook_deliveries.last_error IS 'Ошибка последней попытки';
+COMMENT ON COLUMN webhook_deliveries.created_at IS 'Время создания доставки';
+COMMENT ON COLUMN webhook_deliveries.delivered_at IS 'Время успешной доставки';
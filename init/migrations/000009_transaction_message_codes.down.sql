@@ -0,0 +1,10 @@
+UPDATE transactions
+SET message = CASE message
+    WHEN 'TRANSACTION_PENDING' THEN 'Transaction pending'
+    WHEN 'TRANSACTION_COMPLETED' THEN 'Transaction completed'
+    WHEN 'TRANSACTION_FAILED' THEN 'Transaction failed'
+    WHEN 'SENDER_NOT_HAVE_ENOUGH_BALANCE' THEN 'Sender does not have enough balance'
+END
+WHERE message IN ('TRANSACTION_PENDING', 'TRANSACTION_COMPLETED', 'TRANSACTION_FAILED', 'SENDER_NOT_HAVE_ENOUGH_BALANCE');
+
+COMMENT ON COLUMN transactions.message IS 'Сообщение';
@@ -0,0 +1,11 @@
+-- В сообщении транзакции хранится код, текст на языке клиента подставляется приложением
+UPDATE transactions
+SET message = CASE message
+    WHEN 'Transaction pending' THEN 'TRANSACTION_PENDING'
+    WHEN 'Transaction completed' THEN 'TRANSACTION_COMPLETED'
+    WHEN 'Transaction failed' THEN 'TRANSACTION_FAILED'
+    WHEN 'Sender does not have enough balance' THEN 'SENDER_NOT_HAVE_ENOUGH_BALANCE'
+END
+WHERE message IN ('Transaction pending', 'Transaction completed', 'Transaction failed', 'Sender does not have enough balance');
+
+COMMENT ON COLUMN transactions.message IS 'Код сообщения (TRANSACTION_COMPLETED, SENDER_NOT_HAVE_ENOUGH_BALANCE, ...)';
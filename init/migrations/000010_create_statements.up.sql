@@ -0,0 +1,16 @@
+CREATE TABLE statements
+(
+    wallet_id    VARCHAR(64) NOT NULL REFERENCES wallets (id),
+    period_start TIMESTAMP NOT NULL,
+    period_end   TIMESTAMP NOT NULL,
+    data         JSONB NOT NULL,
+    created_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (wallet_id, period_start, period_end)
+);
+
+COMMENT ON TABLE statements IS 'Сформированные выписки по кошелькам';
+COMMENT ON COLUMN statements.wallet_id IS 'Кошелек, по которому сформирована выписка';
+COMMENT ON COLUMN statements.period_start IS 'Начало периода выписки (включительно)';
+COMMENT ON COLUMN statements.period_end IS 'Конец периода выписки (не включительно)';
+COMMENT ON COLUMN statements.data IS 'Остатки, обороты и транзакции выписки';
+COMMENT ON COLUMN statements.created_at IS 'Время формирования выписки';
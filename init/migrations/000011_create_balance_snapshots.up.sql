@@ -0,0 +1,12 @@
+CREATE TABLE balance_snapshots
+(
+    wallet_id VARCHAR(64) NOT NULL REFERENCES wallets (id),
+    taken_at  TIMESTAMP NOT NULL,
+    balance   INTEGER NOT NULL,
+    PRIMARY KEY (wallet_id, taken_at)
+);
+
+COMMENT ON TABLE balance_snapshots IS 'Балансы кошельков на конец дней, в которые были проведенные транзакции';
+COMMENT ON COLUMN balance_snapshots.wallet_id IS 'Идентификатор кошелька';
+COMMENT ON COLUMN balance_snapshots.taken_at IS 'Момент, на который рассчитан баланс (полночь UTC)';
+COMMENT ON COLUMN balance_snapshots.balance IS 'Баланс с учетом всех проведенных транзакций, созданных до taken_at (в копейках)';
@@ -0,0 +1,5 @@
+ALTER TABLE wallets ALTER COLUMN owner_id TYPE VARCHAR(64) USING owner_id::VARCHAR;
+ALTER TABLE api_keys ALTER COLUMN owner_id TYPE VARCHAR(64) USING owner_id::VARCHAR;
+
+COMMENT ON COLUMN wallets.owner_id IS 'Идентификатор владельца кошелька';
+COMMENT ON COLUMN api_keys.owner_id IS 'Идентификатор владельца ключа';
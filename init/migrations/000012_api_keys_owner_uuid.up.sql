@@ -0,0 +1,8 @@
+-- Владелец ключа - идентификатор вызывающей стороны (Principal.ID), ключ с некорректным значением остановит миграцию
+ALTER TABLE api_keys ALTER COLUMN owner_id TYPE UUID USING owner_id::UUID;
+
+-- Владелец кошелька сравнивается с Principal.ID, поэтому хранится в том же типе
+ALTER TABLE wallets ALTER COLUMN owner_id TYPE UUID USING owner_id::UUID;
+
+COMMENT ON COLUMN api_keys.owner_id IS 'Идентификатор владельца ключа (UUID), совпадает с wallets.owner_id его кошельков';
+COMMENT ON COLUMN wallets.owner_id IS 'Идентификатор владельца кошелька (UUID), NULL - владелец не назначен';
@@ -0,0 +1,21 @@
+-- Записи разных цепочек нельзя объединить в одну без пересчета хешей, поэтому откат возможен, только пока используется первая
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM audit_log WHERE chain > 1) THEN
+        RAISE EXCEPTION 'audit_log has records outside chain 1';
+    END IF;
+END;
+$$;
+
+DELETE FROM audit_chain_head WHERE chain > 1;
+
+ALTER TABLE audit_chain_head DROP CONSTRAINT audit_chain_head_chain_check;
+ALTER TABLE audit_chain_head RENAME COLUMN chain TO id;
+ALTER TABLE audit_chain_head ADD CONSTRAINT audit_chain_head_id_check CHECK (id = 1);
+
+ALTER TABLE audit_log DROP CONSTRAINT audit_log_pkey;
+ALTER TABLE audit_log ADD PRIMARY KEY (id);
+ALTER TABLE audit_log DROP COLUMN chain;
+
+COMMENT ON COLUMN audit_log.id IS 'Порядковый номер записи, идёт без пропусков';
+COMMENT ON TABLE audit_chain_head IS 'Последняя запись цепочки журнала аудита';
@@ -0,0 +1,19 @@
+-- Журнал ведется несколькими независимыми цепочками хешей. Запись попадает в цепочку по идентификатору сущности,
+-- поэтому изменения ждут друг друга на строке audit_chain_head, только если их записи попали в одну цепочку.
+-- Существующие записи остаются в цепочке 1, количество цепочек совпадает с audit.ChainCount
+ALTER TABLE audit_log ADD COLUMN chain INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE audit_log ALTER COLUMN chain DROP DEFAULT;
+ALTER TABLE audit_log DROP CONSTRAINT audit_log_pkey;
+ALTER TABLE audit_log ADD PRIMARY KEY (chain, id);
+
+ALTER TABLE audit_chain_head DROP CONSTRAINT audit_chain_head_id_check;
+ALTER TABLE audit_chain_head RENAME COLUMN id TO chain;
+ALTER TABLE audit_chain_head ADD CONSTRAINT audit_chain_head_chain_check CHECK (chain BETWEEN 1 AND 16);
+
+INSERT INTO audit_chain_head (chain, last_id, last_hash)
+SELECT chain, 0, repeat('0', 64) FROM generate_series(2, 16) AS chain;
+
+COMMENT ON COLUMN audit_log.chain IS 'Номер цепочки, в которую входит запись';
+COMMENT ON COLUMN audit_log.id IS 'Порядковый номер записи в цепочке, идёт без пропусков';
+COMMENT ON TABLE audit_chain_head IS 'Последние записи цепочек журнала аудита';
+COMMENT ON COLUMN audit_chain_head.chain IS 'Номер цепочки';
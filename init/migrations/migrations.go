@@ -0,0 +1,10 @@
+// Пакет встраивает SQL миграции в бинарник
+// Файлы остаются в формате golang-migrate, поэтому их по-прежнему можно применять и внешней утилитой migrate
+package migrations
+
+import "embed"
+
+// Миграции вида NNNNNN_name.up.sql и NNNNNN_name.down.sql
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,10 @@
+package adjustment
+
+import "errors"
+
+// Список возможных ошибок бизнес-логики корректировок
+var ErrAdjustmentNotFound = errors.New("Adjustment not found")
+var ErrAdjustmentAlreadyReviewed = errors.New("Adjustment has already been reviewed")
+var ErrSelfReview = errors.New("Adjustment must be reviewed by a different operator")
+var ErrInsufficientBalance = errors.New("Wallet does not have enough balance for debit")
+var ErrAmountTooSmall = errors.New("Adjustment amount must be at least 0.01")
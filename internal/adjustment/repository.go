@@ -0,0 +1,17 @@
+package adjustment
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Интерфейс репозитория заявок на корректировку баланса
+// Одобрение заявки и изменение баланса выполняются в одной БД транзакции
+type Repository interface {
+	CreateAdjustment(ctx context.Context, adjustment *models.Adjustment) error
+	GetAdjustments(ctx context.Context, status *models.AdjustmentStatus) ([]*models.Adjustment, error)
+	ApproveAdjustment(ctx context.Context, adjustmentId uuid.UUID, reviewerId uuid.UUID) (*models.Adjustment, error)
+	RejectAdjustment(ctx context.Context, adjustmentId uuid.UUID, reviewerId uuid.UUID) (*models.Adjustment, error)
+}
@@ -0,0 +1,360 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"infotecstechtask/internal/adjustment"
+	"infotecstechtask/internal/audit"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/outbox"
+	"infotecstechtask/internal/wallet"
+	"infotecstechtask/pkg/database"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+const adjustmentColumns = `id, wallet_id, direction, amount, reason_code, comment, status, created_by, reviewed_by, transaction_id, created_at, reviewed_at`
+
+// Реализация репозитория
+// Создание и рассмотрение заявок фиксируется в журнале аудита в той же БД транзакции
+type AdjustmentRepository struct {
+	db            *database.Client
+	auditRecorder audit.Recorder
+	outboxWriter  outbox.Writer
+}
+
+func NewAdjustmentRepository(db *database.Client, auditRecorder audit.Recorder, outboxWriter outbox.Writer) *AdjustmentRepository {
+	return &AdjustmentRepository{
+		db:            db,
+		auditRecorder: auditRecorder,
+		outboxWriter:  outboxWriter,
+	}
+}
+
+// Состояние заявки и баланса кошелька, фиксируемое в журнале аудита
+type adjustmentState struct {
+	Status        models.AdjustmentStatus `json:"status"`
+	WalletBalance *int                    `json:"wallet_balance,omitempty"`
+	TransactionID *uuid.UUID              `json:"transaction_id,omitempty"`
+}
+
+// Реализация метода для создания заявки
+// Если кошелёк не найден, возвращается ошибка и заявка не создается
+func (r *AdjustmentRepository) CreateAdjustment(ctx context.Context, adjustmentToCreate *models.Adjustment) error {
+	return r.db.ExecuteTx(ctx, func(tx pgx.Tx) error {
+		var exists bool
+		err := tx.QueryRow(
+			ctx,
+			`SELECT EXISTS (SELECT 1 FROM wallets WHERE id = $1)`,
+			adjustmentToCreate.WalletID,
+		).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to check wallet: %w", err)
+		}
+		if !exists {
+			return wallet.ErrWalletNotFound
+		}
+
+		_, err = tx.Exec(
+			ctx,
+			`INSERT INTO adjustments (id, wallet_id, direction, amount, reason_code, comment, status, created_by, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			adjustmentToCreate.ID,
+			adjustmentToCreate.WalletID,
+			adjustmentToCreate.Direction,
+			adjustmentToCreate.Amount,
+			adjustmentToCreate.ReasonCode,
+			adjustmentToCreate.Comment,
+			adjustmentToCreate.Status,
+			adjustmentToCreate.CreatedBy,
+			adjustmentToCreate.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create adjustment: %w", err)
+		}
+
+		return r.auditRecorder.Record(ctx, tx, &models.AuditChange{
+			Action:     models.AuditAdjustmentCreate,
+			EntityType: models.AuditEntityAdjustment,
+			EntityID:   adjustmentToCreate.ID.String(),
+			After:      adjustmentToCreate,
+		})
+	})
+}
+
+// Возвращает заявки, отсортированные от новых к старым
+// Если статус не указан, возвращаются заявки во всех статусах
+func (r *AdjustmentRepository) GetAdjustments(ctx context.Context, status *models.AdjustmentStatus) ([]*models.Adjustment, error) {
+	sql := `SELECT ` + adjustmentColumns + `
+            FROM adjustments
+            WHERE $1::VARCHAR IS NULL OR status = $1
+            ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(ctx, sql, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	adjustments := []*models.Adjustment{}
+	for rows.Next() {
+		a, err := scanAdjustment(rows)
+		if err != nil {
+			return nil, err
+		}
+		adjustments = append(adjustments, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return adjustments, nil
+}
+
+// Реализация метода для одобрения заявки
+//
+// Заявку может одобрить только оператор, который её не создавал
+// При одобрении в одной БД транзакции изменяется баланс кошелька,
+// создается транзакция с типом adjustment, заявка переводится в статус approved
+// и в outbox записываются события о транзакции и изменении баланса
+//
+// Если при списании на балансе кошелька недостаточно средств, заявка остается в статусе pending
+func (r *AdjustmentRepository) ApproveAdjustment(ctx context.Context, adjustmentId uuid.UUID, reviewerId uuid.UUID) (*models.Adjustment, error) {
+	var result *models.Adjustment
+
+	err := r.db.ExecuteTx(ctx, func(tx pgx.Tx) error {
+		adjustmentToApprove, err := lockPendingAdjustment(ctx, tx, adjustmentId, reviewerId)
+		if err != nil {
+			return err
+		}
+
+		var balance int
+		err = tx.QueryRow(
+			ctx,
+			`SELECT balance FROM wallets WHERE id = $1 FOR UPDATE`,
+			adjustmentToApprove.WalletID,
+		).Scan(&balance)
+		if err != nil {
+			return fmt.Errorf("failed to lock wallet: %w", err)
+		}
+
+		transaction := &models.Transaction{
+			ID:        uuid.New(),
+			Type:      models.TypeAdjustment,
+			Amount:    adjustmentToApprove.Amount,
+			Status:    models.Completed,
+			Message:   models.TRANSACTION_COMPLETED,
+			CreatedAt: time.Now().UTC(),
+		}
+
+		delta := adjustmentToApprove.Amount
+		if adjustmentToApprove.Direction == models.Debit {
+			if balance < adjustmentToApprove.Amount {
+				return adjustment.ErrInsufficientBalance
+			}
+			delta = -delta
+			transaction.FromAddress = adjustmentToApprove.WalletID
+		} else {
+			transaction.ToAddress = adjustmentToApprove.WalletID
+		}
+
+		_, err = tx.Exec(
+			ctx,
+			`INSERT INTO transactions (id, type, from_address, to_address, amount, status, message, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			transaction.ID,
+			transaction.Type,
+			nullableAddress(transaction.FromAddress),
+			nullableAddress(transaction.ToAddress),
+			transaction.Amount,
+			transaction.Status,
+			transaction.Message,
+			transaction.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create transaction: %w", err)
+		}
+
+		_, err = tx.Exec(
+			ctx,
+			`UPDATE wallets SET balance = balance + $1 WHERE id = $2`,
+			delta,
+			adjustmentToApprove.WalletID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update wallet balance: %w", err)
+		}
+
+		reviewedAt := transaction.CreatedAt
+		adjustmentToApprove.Status = models.AdjustmentApproved
+		adjustmentToApprove.ReviewedBy = reviewerId
+		adjustmentToApprove.ReviewedAt = &reviewedAt
+		adjustmentToApprove.TransactionID = transaction.ID
+
+		_, err = tx.Exec(
+			ctx,
+			`UPDATE adjustments SET status = $1, reviewed_by = $2, reviewed_at = $3, transaction_id = $4 WHERE id = $5`,
+			adjustmentToApprove.Status,
+			adjustmentToApprove.ReviewedBy,
+			adjustmentToApprove.ReviewedAt,
+			adjustmentToApprove.TransactionID,
+			adjustmentToApprove.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update adjustment: %w", err)
+		}
+
+		balanceAfter := balance + delta
+		err = r.auditRecorder.Record(ctx, tx, &models.AuditChange{
+			Action:     models.AuditAdjustmentApprove,
+			EntityType: models.AuditEntityAdjustment,
+			EntityID:   adjustmentToApprove.ID.String(),
+			Before: adjustmentState{
+				Status:        models.AdjustmentPending,
+				WalletBalance: &balance,
+			},
+			After: adjustmentState{
+				Status:        adjustmentToApprove.Status,
+				WalletBalance: &balanceAfter,
+				TransactionID: &transaction.ID,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		events, err := outbox.TransactionEvents(transaction, outbox.BalanceChange{
+			WalletID: adjustmentToApprove.WalletID,
+			Balance:  balanceAfter,
+			Delta:    delta,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = r.outboxWriter.Add(ctx, tx, events)
+		if err != nil {
+			return err
+		}
+
+		result = adjustmentToApprove
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Реализация метода для отклонения заявки
+// Заявку может отклонить только оператор, который её не создавал, баланс кошелька не изменяется
+func (r *AdjustmentRepository) RejectAdjustment(ctx context.Context, adjustmentId uuid.UUID, reviewerId uuid.UUID) (*models.Adjustment, error) {
+	var result *models.Adjustment
+
+	err := r.db.ExecuteTx(ctx, func(tx pgx.Tx) error {
+		adjustmentToReject, err := lockPendingAdjustment(ctx, tx, adjustmentId, reviewerId)
+		if err != nil {
+			return err
+		}
+
+		reviewedAt := time.Now().UTC()
+		adjustmentToReject.Status = models.AdjustmentRejected
+		adjustmentToReject.ReviewedBy = reviewerId
+		adjustmentToReject.ReviewedAt = &reviewedAt
+
+		_, err = tx.Exec(
+			ctx,
+			`UPDATE adjustments SET status = $1, reviewed_by = $2, reviewed_at = $3 WHERE id = $4`,
+			adjustmentToReject.Status,
+			adjustmentToReject.ReviewedBy,
+			adjustmentToReject.ReviewedAt,
+			adjustmentToReject.ID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update adjustment: %w", err)
+		}
+
+		err = r.auditRecorder.Record(ctx, tx, &models.AuditChange{
+			Action:     models.AuditAdjustmentReject,
+			EntityType: models.AuditEntityAdjustment,
+			EntityID:   adjustmentToReject.ID.String(),
+			Before:     adjustmentState{Status: models.AdjustmentPending},
+			After:      adjustmentState{Status: adjustmentToReject.Status},
+		})
+		if err != nil {
+			return err
+		}
+
+		result = adjustmentToReject
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Функция блокирует заявку и проверяет, что её можно рассмотреть указанным оператором
+func lockPendingAdjustment(ctx context.Context, tx pgx.Tx, adjustmentId uuid.UUID, reviewerId uuid.UUID) (*models.Adjustment, error) {
+	row := tx.QueryRow(
+		ctx,
+		`SELECT `+adjustmentColumns+` FROM adjustments WHERE id = $1 FOR UPDATE`,
+		adjustmentId,
+	)
+
+	a, err := scanAdjustment(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, adjustment.ErrAdjustmentNotFound
+		}
+		return nil, fmt.Errorf("failed to lock adjustment: %w", err)
+	}
+
+	if a.Status != models.AdjustmentPending {
+		return nil, adjustment.ErrAdjustmentAlreadyReviewed
+	}
+	if a.CreatedBy == reviewerId {
+		return nil, adjustment.ErrSelfReview
+	}
+
+	return a, nil
+}
+
+// Функция для сборки заявки из строки результата запроса
+func scanAdjustment(row pgx.Row) (*models.Adjustment, error) {
+	var a models.Adjustment
+	err := row.Scan(
+		&a.ID,
+		&a.WalletID,
+		&a.Direction,
+		&a.Amount,
+		&a.ReasonCode,
+		&a.Comment,
+		&a.Status,
+		&a.CreatedBy,
+		&a.ReviewedBy,
+		&a.TransactionID,
+		&a.CreatedAt,
+		&a.ReviewedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+// Функция для записи отсутствующей стороны транзакции как NULL
+func nullableAddress(address uuid.UUID) *uuid.UUID {
+	if address == uuid.Nil {
+		return nil
+	}
+
+	return &address
+}
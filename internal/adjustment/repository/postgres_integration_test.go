@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"context"
+	"infotecstechtask/internal/adjustment"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/wallet"
+	"infotecstechtask/pkg/database"
+	"infotecstechtask/test/testutils"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	audrepo "infotecstechtask/internal/audit/repository"
+	orepo "infotecstechtask/internal/outbox/repository"
+)
+
+var (
+	maker    = uuid.MustParse("5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e01")
+	checker  = uuid.MustParse("5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e03")
+	credit   = uuid.MustParse("7c9e6679-7425-40de-944b-e07fc1f90ae7")
+	overdraw = uuid.MustParse("9b2d1e3f-5a6c-4d7e-8f90-1a2b3c4d5e6f")
+	debit    = uuid.MustParse("2f1e0d9c-8b7a-4c6d-9e5f-4a3b2c1d0e9f")
+)
+
+type AdjustmentRepositoryTestSuite struct {
+	suite.Suite
+	pgContainer *testutils.PGTestContainer
+	repo        *AdjustmentRepository
+	fixtures    *testutils.FixtureManager
+	ctx         context.Context
+}
+
+func (suite *AdjustmentRepositoryTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+
+	container, err := testutils.StartPGContainer(suite.ctx)
+	if err != nil {
+		log.Fatalf("Failed to start test container: %v", err)
+	}
+	suite.pgContainer = container
+
+	client := database.NewClientWithPool(container.Pool)
+	suite.repo = NewAdjustmentRepository(client, audrepo.NewAuditRepository(client), orepo.NewOutboxRepository(client))
+
+	suite.fixtures = testutils.NewFixtureManager(container.Pool)
+}
+
+func (suite *AdjustmentRepositoryTestSuite) TearDownSuite() {
+	if suite.pgContainer != nil {
+		if err := suite.pgContainer.Close(suite.ctx); err != nil {
+			log.Printf("Failed to close test container: %v", err)
+		}
+	}
+}
+
+func (suite *AdjustmentRepositoryTestSuite) BeforeTest(_, _ string) {
+	_, err := suite.pgContainer.Pool.Exec(suite.ctx, "TRUNCATE TABLE wallets, transactions, adjustments, outbox CASCADE")
+	if err != nil {
+		log.Printf("TRUNCATE error: %v", err)
+	}
+
+	assert.NoError(suite.T(), err)
+
+	err = suite.fixtures.ApplySQLFixture(suite.ctx, "wallets/wallets.sql")
+	suite.Require().NoError(err)
+}
+
+func TestAdjustmentRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(AdjustmentRepositoryTestSuite))
+}
+
+func (suite *AdjustmentRepositoryTestSuite) TestCreateAdjustmentSuccess() {
+	adjustmentToCreate := &models.Adjustment{
+		ID:         uuid.New(),
+		WalletID:   uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10"),
+		Direction:  models.Credit,
+		Amount:     100,
+		ReasonCode: models.ReasonRefund,
+		Status:     models.AdjustmentPending,
+		CreatedBy:  maker,
+		CreatedAt:  time.Now(),
+	}
+
+	err := suite.repo.CreateAdjustment(suite.ctx, adjustmentToCreate)
+	suite.Require().NoError(err)
+
+	pending := models.AdjustmentPending
+	actual, err := suite.repo.GetAdjustments(suite.ctx, &pending)
+	suite.Require().NoError(err)
+	suite.Require().Len(actual, 1)
+	suite.Assert().Equal(adjustmentToCreate.ID, actual[0].ID)
+	suite.Assert().Equal(uuid.Nil, actual[0].ReviewedBy)
+	suite.Assert().Nil(actual[0].ReviewedAt)
+}
+
+func (suite *AdjustmentRepositoryTestSuite) TestCreateAdjustmentWalletNotFound() {
+	adjustmentToCreate := &models.Adjustment{
+		ID:         uuid.New(),
+		WalletID:   uuid.New(),
+		Direction:  models.Credit,
+		Amount:     100,
+		ReasonCode: models.ReasonRefund,
+		Status:     models.AdjustmentPending,
+		CreatedBy:  maker,
+		CreatedAt:  time.Now(),
+	}
+
+	err := suite.repo.CreateAdjustment(suite.ctx, adjustmentToCreate)
+	suite.Assert().ErrorIs(err, wallet.ErrWalletNotFound)
+}
+
+func (suite *AdjustmentRepositoryTestSuite) TestApproveAdjustment() {
+	err := suite.fixtures.ApplySQLFixture(suite.ctx, "adjustments/adjustments.sql")
+	suite.Require().NoError(err)
+
+	approvedCredit, err := suite.repo.ApproveAdjustment(suite.ctx, credit, checker)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(models.AdjustmentApproved, approvedCredit.Status)
+	suite.Assert().Equal(checker, approvedCredit.ReviewedBy)
+	suite.verifyWalletBalance("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10", 10000+2550)
+	suite.verifyAdjustmentTransaction(approvedCredit.TransactionID, nil, &approvedCredit.WalletID)
+
+	approvedDebit, err := suite.repo.ApproveAdjustment(suite.ctx, debit, checker)
+	suite.Require().NoError(err)
+	suite.verifyWalletBalance("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12", 10000-500)
+	suite.verifyAdjustmentTransaction(approvedDebit.TransactionID, &approvedDebit.WalletID, nil)
+}
+
+func (suite *AdjustmentRepositoryTestSuite) TestApproveAdjustmentErrors() {
+	err := suite.fixtures.ApplySQLFixture(suite.ctx, "adjustments/adjustments.sql")
+	suite.Require().NoError(err)
+
+	_, err = suite.repo.ApproveAdjustment(suite.ctx, credit, maker)
+	suite.Assert().ErrorIs(err, adjustment.ErrSelfReview)
+
+	_, err = suite.repo.ApproveAdjustment(suite.ctx, overdraw, checker)
+	suite.Assert().ErrorIs(err, adjustment.ErrInsufficientBalance)
+	suite.verifyWalletBalance("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", 10000)
+
+	_, err = suite.repo.ApproveAdjustment(suite.ctx, uuid.New(), checker)
+	suite.Assert().ErrorIs(err, adjustment.ErrAdjustmentNotFound)
+
+	_, err = suite.repo.RejectAdjustment(suite.ctx, credit, checker)
+	suite.Require().NoError(err)
+
+	_, err = suite.repo.ApproveAdjustment(suite.ctx, credit, checker)
+	suite.Assert().ErrorIs(err, adjustment.ErrAdjustmentAlreadyReviewed)
+	suite.verifyWalletBalance("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10", 10000)
+}
+
+// Время проведения и рассмотрения хранится в UTC и при локальном поясе сервера, отличном от UTC
+func (suite *AdjustmentRepositoryTestSuite) TestReviewStoresTimesInUTC() {
+	testutils.SetNonUTCLocal(suite.T())
+
+	err := suite.fixtures.ApplySQLFixture(suite.ctx, "adjustments/adjustments.sql")
+	suite.Require().NoError(err)
+
+	approved, err := suite.repo.ApproveAdjustment(suite.ctx, credit, checker)
+	suite.Require().NoError(err)
+	rejected, err := suite.repo.RejectAdjustment(suite.ctx, debit, checker)
+	suite.Require().NoError(err)
+
+	var transactionCreatedAt, approvedAt, rejectedAt time.Time
+	err = suite.pgContainer.Pool.QueryRow(suite.ctx,
+		`SELECT t.created_at, a.reviewed_at FROM adjustments a JOIN transactions t ON t.id = a.transaction_id WHERE a.id = $1`,
+		approved.ID,
+	).Scan(&transactionCreatedAt, &approvedAt)
+	suite.Require().NoError(err)
+	err = suite.pgContainer.Pool.QueryRow(suite.ctx, `SELECT reviewed_at FROM adjustments WHERE id = $1`, rejected.ID).Scan(&rejectedAt)
+	suite.Require().NoError(err)
+
+	suite.Assert().WithinDuration(time.Now(), transactionCreatedAt, time.Minute)
+	suite.Assert().WithinDuration(time.Now(), approvedAt, time.Minute)
+	suite.Assert().WithinDuration(time.Now(), rejectedAt, time.Minute)
+}
+
+func (suite *AdjustmentRepositoryTestSuite) verifyWalletBalance(id string, expected int) {
+	var balance int
+	err := suite.pgContainer.Pool.QueryRow(suite.ctx, "SELECT balance FROM wallets WHERE id = $1", id).Scan(&balance)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(expected, balance)
+}
+
+func (suite *AdjustmentRepositoryTestSuite) verifyAdjustmentTransaction(id uuid.UUID, from *uuid.UUID, to *uuid.UUID) {
+	var transactionType models.TransactionType
+	var actualFrom, actualTo *string
+	err := suite.pgContainer.Pool.QueryRow(
+		suite.ctx,
+		"SELECT type, from_address, to_address FROM transactions WHERE id = $1",
+		id,
+	).Scan(&transactionType, &actualFrom, &actualTo)
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal(models.TypeAdjustment, transactionType)
+	suite.Assert().Equal(from == nil, actualFrom == nil)
+	suite.Assert().Equal(to == nil, actualTo == nil)
+}
@@ -0,0 +1,17 @@
+package adjustment
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Интерфейс сервиса
+// Заявку создаёт один оператор, а одобряет или отклоняет другой
+type Service interface {
+	CreateAdjustment(ctx context.Context, createdBy uuid.UUID, request *models.CreateAdjustmentRequest) (*models.AdjustmentResponse, error)
+	GetAdjustments(ctx context.Context, status *models.AdjustmentStatus) ([]*models.AdjustmentResponse, error)
+	ApproveAdjustment(ctx context.Context, adjustmentId uuid.UUID, reviewerId uuid.UUID) (*models.AdjustmentResponse, error)
+	RejectAdjustment(ctx context.Context, adjustmentId uuid.UUID, reviewerId uuid.UUID) (*models.AdjustmentResponse, error)
+}
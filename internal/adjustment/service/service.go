@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"infotecstechtask/internal/adjustment"
+	"infotecstechtask/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Реализация сервиса
+// Ответственна за создание заявок и маппинг моделей
+type AdjustmentService struct {
+	adjustmentRepository adjustment.Repository
+}
+
+func NewAdjustmentService(adjustmentRepository adjustment.Repository) *AdjustmentService {
+	return &AdjustmentService{
+		adjustmentRepository: adjustmentRepository,
+	}
+}
+
+// Сумма проверяется после округления до копеек: заявка на сумму меньше копейки не создается
+func (s AdjustmentService) CreateAdjustment(ctx context.Context, createdBy uuid.UUID, request *models.CreateAdjustmentRequest) (*models.AdjustmentResponse, error) {
+	adjustmentToCreate := models.ToAdjustment(request, uuid.New(), createdBy, time.Now().UTC())
+	if adjustmentToCreate.Amount <= 0 {
+		return nil, adjustment.ErrAmountTooSmall
+	}
+
+	if err := s.adjustmentRepository.CreateAdjustment(ctx, adjustmentToCreate); err != nil {
+		return nil, err
+	}
+
+	return models.ToAdjustmentResponse(adjustmentToCreate), nil
+}
+
+func (s AdjustmentService) GetAdjustments(ctx context.Context, status *models.AdjustmentStatus) ([]*models.AdjustmentResponse, error) {
+	adjustments, err := s.adjustmentRepository.GetAdjustments(ctx, status)
+
+	return models.ToAdjustmentResponses(adjustments), err
+}
+
+func (s AdjustmentService) ApproveAdjustment(ctx context.Context, adjustmentId uuid.UUID, reviewerId uuid.UUID) (*models.AdjustmentResponse, error) {
+	approved, err := s.adjustmentRepository.ApproveAdjustment(ctx, adjustmentId, reviewerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.ToAdjustmentResponse(approved), nil
+}
+
+func (s AdjustmentService) RejectAdjustment(ctx context.Context, adjustmentId uuid.UUID, reviewerId uuid.UUID) (*models.AdjustmentResponse, error) {
+	rejected, err := s.adjustmentRepository.RejectAdjustment(ctx, adjustmentId, reviewerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.ToAdjustmentResponse(rejected), nil
+}
@@ -0,0 +1,15 @@
+package audit
+
+import "hash/fnv"
+
+// Количество независимых цепочек журнала, совпадает с количеством строк audit_chain_head
+const ChainCount = 16
+
+// Функция возвращает номер цепочки (от 1 до ChainCount), в которую попадают записи об изменениях сущности
+// Записи об одной сущности всегда попадают в одну цепочку, поэтому сохраняют порядок между собой
+func ChainFor(entityId string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(entityId))
+
+	return int(h.Sum32()%ChainCount) + 1
+}
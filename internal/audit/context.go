@@ -0,0 +1,21 @@
+package audit
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+)
+
+type metadataKey struct{}
+
+// Функция для сохранения метаданных запроса в контексте
+func WithMetadata(ctx context.Context, metadata models.AuditMetadata) context.Context {
+	return context.WithValue(ctx, metadataKey{}, metadata)
+}
+
+// Функция для получения метаданных запроса из контекста
+// Если метаданные не были сохранены (например, при вызове из фоновой задачи), возвращаются пустые метаданные
+func MetadataFromContext(ctx context.Context) models.AuditMetadata {
+	metadata, _ := ctx.Value(metadataKey{}).(models.AuditMetadata)
+
+	return metadata
+}
@@ -0,0 +1,53 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"infotecstechtask/internal/models"
+	"strings"
+	"time"
+)
+
+// Хеш, с которым связывается первая запись журнала
+var GenesisHash = strings.Repeat("0", 64)
+
+// Каноническое представление записи, от которого считается хеш
+// Порядок полей фиксирован, поэтому сериализация детерминирована
+type canonicalRecord struct {
+	ID         int64  `json:"id"`
+	ActorID    string `json:"actor_id"`
+	ActorRole  string `json:"actor_role"`
+	IP         string `json:"ip"`
+	RequestID  string `json:"request_id"`
+	Action     string `json:"action"`
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	Before     string `json:"before"`
+	After      string `json:"after"`
+	CreatedAt  string `json:"created_at"`
+	PrevHash   string `json:"prev_hash"`
+}
+
+// Функция для вычисления хеша записи журнала
+// В хеш входит хеш предыдущей записи, поэтому изменение любой записи ломает всю последующую цепочку
+func ComputeHash(record *models.AuditRecord) string {
+	canonical, _ := json.Marshal(canonicalRecord{
+		ID:         record.ID,
+		ActorID:    record.ActorID.String(),
+		ActorRole:  string(record.ActorRole),
+		IP:         record.IP,
+		RequestID:  record.RequestID,
+		Action:     string(record.Action),
+		EntityType: string(record.EntityType),
+		EntityID:   record.EntityID,
+		Before:     record.Before,
+		After:      record.After,
+		CreatedAt:  record.CreatedAt.UTC().Format(time.RFC3339Nano),
+		PrevHash:   record.PrevHash,
+	})
+
+	sum := sha256.Sum256(canonical)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,22 @@
+package audit
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// Интерфейс для записи в журнал аудита
+// Запись выполняется внутри уже открытой БД транзакции, в которой происходит само изменение,
+// поэтому запись журнала и изменение фиксируются или откатываются вместе
+type Recorder interface {
+	Record(ctx context.Context, tx pgx.Tx, change *models.AuditChange) error
+}
+
+// Интерфейс репозитория для чтения журнала аудита
+// GetHeads возвращает последние записи всех цепочек, GetRecords - записи одной цепочки
+type Repository interface {
+	GetRecords(ctx context.Context, chain int, afterId int64, limit int) ([]*models.AuditRecord, error)
+	GetHeads(ctx context.Context) ([]*models.AuditChainHead, error)
+}
@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"infotecstechtask/internal/audit"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/pkg/database"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+// Реализация репозитория
+type AuditRepository struct {
+	db *database.Client
+}
+
+func NewAuditRepository(db *database.Client) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+// Реализация метода для записи в журнал аудита
+//
+// Запись добавляется в цепочку audit.ChainFor(EntityID). Строка audit_chain_head этой цепочки блокируется до конца транзакции,
+// поэтому внутри цепочки записи добавляются строго по одной, идентификаторы идут без пропусков,
+// а порядок совпадает с порядком фиксации транзакций. Изменения, записи которых попали в разные цепочки, друг друга не ждут.
+// Если head изменила параллельная транзакция, Postgres вернет ошибку сериализации и транзакция будет повторена
+//
+// Actor, IP и идентификатор запроса берутся из метаданных в контексте
+func (r *AuditRepository) Record(ctx context.Context, tx pgx.Tx, change *models.AuditChange) error {
+	before, err := marshalState(change.Before)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit before state: %w", err)
+	}
+	after, err := marshalState(change.After)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit after state: %w", err)
+	}
+
+	chain := audit.ChainFor(change.EntityID)
+
+	var lastId int64
+	var lastHash string
+	err = tx.QueryRow(
+		ctx,
+		`SELECT last_id, last_hash FROM audit_chain_head WHERE chain = $1 FOR UPDATE`,
+		chain,
+	).Scan(&lastId, &lastHash)
+	if err != nil {
+		return fmt.Errorf("failed to lock audit chain head: %w", err)
+	}
+
+	metadata := audit.MetadataFromContext(ctx)
+	record := &models.AuditRecord{
+		Chain:      chain,
+		ID:         lastId + 1,
+		ActorID:    metadata.ActorID,
+		ActorRole:  metadata.ActorRole,
+		IP:         metadata.IP,
+		RequestID:  metadata.RequestID,
+		Action:     change.Action,
+		EntityType: change.EntityType,
+		EntityID:   change.EntityID,
+		Before:     before,
+		After:      after,
+		CreatedAt:  time.Now().UTC().Truncate(time.Microsecond),
+		PrevHash:   lastHash,
+	}
+	record.Hash = audit.ComputeHash(record)
+
+	_, err = tx.Exec(
+		ctx,
+		`INSERT INTO audit_log (chain, id, actor_id, actor_role, ip, request_id, action, entity_type, entity_id, before_state, after_state, created_at, prev_hash, hash)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+		record.Chain,
+		record.ID,
+		nullableActor(record.ActorID),
+		record.ActorRole,
+		record.IP,
+		record.RequestID,
+		record.Action,
+		record.EntityType,
+		record.EntityID,
+		record.Before,
+		record.After,
+		record.CreatedAt,
+		record.PrevHash,
+		record.Hash,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit record: %w", err)
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`UPDATE audit_chain_head SET last_id = $1, last_hash = $2 WHERE chain = $3`,
+		record.ID,
+		record.Hash,
+		record.Chain,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update audit chain head: %w", err)
+	}
+
+	return nil
+}
+
+// Возвращает записи цепочки chain с идентификатором больше afterId в порядке цепочки
+func (r *AuditRepository) GetRecords(ctx context.Context, chain int, afterId int64, limit int) ([]*models.AuditRecord, error) {
+	sql := `SELECT chain, id, actor_id, actor_role, ip, request_id, action, entity_type, entity_id, before_state, after_state, created_at, prev_hash, hash
+            FROM audit_log
+            WHERE chain = $1 AND id > $2
+            ORDER BY id
+            LIMIT $3`
+
+	rows, err := r.db.Query(ctx, sql, chain, afterId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := make([]*models.AuditRecord, 0, limit)
+	for rows.Next() {
+		var record models.AuditRecord
+		err := rows.Scan(
+			&record.Chain,
+			&record.ID,
+			&record.ActorID,
+			&record.ActorRole,
+			&record.IP,
+			&record.RequestID,
+			&record.Action,
+			&record.EntityType,
+			&record.EntityID,
+			&record.Before,
+			&record.After,
+			&record.CreatedAt,
+			&record.PrevHash,
+			&record.Hash,
+		)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, &record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// Возвращает последние записи всех цепочек в порядке номеров цепочек
+func (r *AuditRepository) GetHeads(ctx context.Context) ([]*models.AuditChainHead, error) {
+	rows, err := r.db.Query(ctx, `SELECT chain, last_id, last_hash FROM audit_chain_head ORDER BY chain`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	heads := make([]*models.AuditChainHead, 0, audit.ChainCount)
+	for rows.Next() {
+		var head models.AuditChainHead
+		if err := rows.Scan(&head.Chain, &head.LastID, &head.LastHash); err != nil {
+			return nil, err
+		}
+		heads = append(heads, &head)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return heads, nil
+}
+
+// Функция для сериализации состояния сущности
+// Отсутствующее состояние хранится как JSON null
+func marshalState(state any) (string, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// Функция для записи неизвестного актора (например, фоновой задачи) как NULL
+func nullableActor(actorId uuid.UUID) *uuid.UUID {
+	if actorId == uuid.Nil {
+		return nil
+	}
+
+	return &actorId
+}
@@ -0,0 +1,242 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"infotecstechtask/internal/audit"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/pkg/database"
+	"infotecstechtask/test/testutils"
+	"log"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	audservice "infotecstechtask/internal/audit/service"
+)
+
+type AuditRepositoryTestSuite struct {
+	suite.Suite
+	pgContainer *testutils.PGTestContainer
+	client      *database.Client
+	repo        *AuditRepository
+	service     *audservice.AuditService
+	ctx         context.Context
+}
+
+func (suite *AuditRepositoryTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+
+	container, err := testutils.StartPGContainer(suite.ctx)
+	if err != nil {
+		log.Fatalf("Failed to start test container: %v", err)
+	}
+	suite.pgContainer = container
+
+	suite.client = database.NewClientWithPool(container.Pool)
+	suite.repo = NewAuditRepository(suite.client)
+	suite.service = audservice.NewAuditService(suite.repo)
+}
+
+func (suite *AuditRepositoryTestSuite) TearDownSuite() {
+	if suite.pgContainer != nil {
+		if err := suite.pgContainer.Close(suite.ctx); err != nil {
+			log.Printf("Failed to close test container: %v", err)
+		}
+	}
+}
+
+func (suite *AuditRepositoryTestSuite) BeforeTest(_, _ string) {
+	_, err := suite.pgContainer.Pool.Exec(suite.ctx, "TRUNCATE TABLE audit_log; UPDATE audit_chain_head SET last_id = 0, last_hash = repeat('0', 64)")
+	if err != nil {
+		log.Printf("TRUNCATE error: %v", err)
+	}
+
+	assert.NoError(suite.T(), err)
+}
+
+func TestAuditRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditRepositoryTestSuite))
+}
+
+func (suite *AuditRepositoryTestSuite) TestRecordAndVerifyChain() {
+	ctx := audit.WithMetadata(suite.ctx, models.AuditMetadata{
+		ActorID:   uuid.MustParse("5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e03"),
+		ActorRole: models.RoleAdmin,
+		IP:        "10.0.0.1",
+		RequestID: "req-1",
+	})
+	entityId := uuid.NewString()
+	chain := audit.ChainFor(entityId)
+
+	for i := 0; i < 3; i++ {
+		suite.record(ctx, entityId)
+	}
+	suite.record(suite.ctx, entityId)
+
+	records, err := suite.repo.GetRecords(suite.ctx, chain, 0, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(records, 4)
+	suite.Assert().Equal(chain, records[0].Chain)
+	suite.Assert().Equal(audit.GenesisHash, records[0].PrevHash)
+	suite.Assert().Equal(records[0].Hash, records[1].PrevHash)
+	suite.Assert().Equal("10.0.0.1", records[0].IP)
+	suite.Assert().Equal(uuid.Nil, records[3].ActorID)
+
+	result, err := suite.service.VerifyChain(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Assert().True(result.Valid)
+	suite.Assert().Equal(audit.ChainCount, result.Chains)
+	suite.Assert().Equal(int64(4), result.Checked)
+}
+
+func (suite *AuditRepositoryTestSuite) TestConcurrentRecordsKeepChainValid() {
+	concurrency := 5
+	sameEntityId := uuid.NewString()
+	var wg sync.WaitGroup
+	wg.Add(2 * concurrency)
+
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			suite.record(suite.ctx, uuid.NewString())
+		}()
+		go func() {
+			defer wg.Done()
+			suite.record(suite.ctx, sameEntityId)
+		}()
+	}
+
+	wg.Wait()
+
+	result, err := suite.service.VerifyChain(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Assert().True(result.Valid)
+	suite.Assert().Equal(int64(2*concurrency), result.Checked)
+}
+
+// Запись об изменении, попавшем в другую цепочку, не ждет транзакцию, которая держит блокировку своей цепочки
+func (suite *AuditRepositoryTestSuite) TestRecordsInDifferentChainsDoNotWait() {
+	firstEntityId := uuid.NewString()
+	secondEntityId := uuid.NewString()
+	for audit.ChainFor(secondEntityId) == audit.ChainFor(firstEntityId) {
+		secondEntityId = uuid.NewString()
+	}
+
+	tx, err := suite.pgContainer.Pool.Begin(suite.ctx)
+	suite.Require().NoError(err)
+	defer func() { _ = tx.Rollback(suite.ctx) }()
+
+	err = suite.repo.Record(suite.ctx, tx, &models.AuditChange{
+		Action:     models.AuditAdjustmentReject,
+		EntityType: models.AuditEntityAdjustment,
+		EntityID:   firstEntityId,
+	})
+	suite.Require().NoError(err)
+
+	ctx, cancel := context.WithTimeout(suite.ctx, 5*time.Second)
+	defer cancel()
+	suite.record(ctx, secondEntityId)
+
+	suite.Require().NoError(tx.Commit(suite.ctx))
+
+	result, err := suite.service.VerifyChain(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Assert().True(result.Valid)
+	suite.Assert().Equal(int64(2), result.Checked)
+}
+
+func (suite *AuditRepositoryTestSuite) TestVerifyChainDetectsTampering() {
+	entityId := uuid.NewString()
+	for i := 0; i < 3; i++ {
+		suite.record(suite.ctx, entityId)
+	}
+
+	_, err := suite.pgContainer.Pool.Exec(suite.ctx, `
+		ALTER TABLE audit_log DISABLE TRIGGER audit_log_append_only;
+		UPDATE audit_log SET after_state = '{"status":"approved"}' WHERE id = 2;
+		ALTER TABLE audit_log ENABLE TRIGGER audit_log_append_only`)
+	suite.Require().NoError(err)
+
+	result, err := suite.service.VerifyChain(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Assert().False(result.Valid)
+	suite.Assert().Equal(audit.ChainFor(entityId), result.BrokenChain)
+	suite.Assert().Equal(int64(2), result.BrokenAt)
+}
+
+func (suite *AuditRepositoryTestSuite) TestVerifyChainDetectsDeletedRecords() {
+	entityId := uuid.NewString()
+	for i := 0; i < 3; i++ {
+		suite.record(suite.ctx, entityId)
+	}
+
+	testCases := []struct {
+		name     string
+		id       int64
+		brokenAt int64
+	}{
+		{"last record", 3, 3},
+		{"record in the middle", 1, 2},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			_, err := suite.pgContainer.Pool.Exec(suite.ctx, `
+				ALTER TABLE audit_log DISABLE TRIGGER audit_log_append_only;
+				DELETE FROM audit_log WHERE id = `+strconv.FormatInt(tc.id, 10)+`;
+				ALTER TABLE audit_log ENABLE TRIGGER audit_log_append_only`)
+			suite.Require().NoError(err)
+
+			result, err := suite.service.VerifyChain(suite.ctx)
+			suite.Require().NoError(err)
+			suite.Assert().False(result.Valid)
+			suite.Assert().Equal(audit.ChainFor(entityId), result.BrokenChain)
+			suite.Assert().Equal(tc.brokenAt, result.BrokenAt)
+		})
+	}
+}
+
+func (suite *AuditRepositoryTestSuite) TestRolledBackChangeIsNotRecorded() {
+	err := suite.client.ExecuteTx(suite.ctx, func(tx pgx.Tx) error {
+		err := suite.repo.Record(suite.ctx, tx, &models.AuditChange{
+			Action:     models.AuditAdjustmentReject,
+			EntityType: models.AuditEntityAdjustment,
+			EntityID:   uuid.NewString(),
+		})
+		suite.Require().NoError(err)
+		return errors.New("rollback")
+	})
+	suite.Require().Error(err)
+
+	result, err := suite.service.VerifyChain(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Assert().True(result.Valid)
+	suite.Assert().Zero(result.Checked)
+}
+
+func (suite *AuditRepositoryTestSuite) TestAuditLogIsAppendOnly() {
+	suite.record(suite.ctx, uuid.NewString())
+
+	_, err := suite.pgContainer.Pool.Exec(suite.ctx, `DELETE FROM audit_log WHERE id = 1`)
+	suite.Assert().Error(err)
+}
+
+func (suite *AuditRepositoryTestSuite) record(ctx context.Context, entityId string) {
+	err := suite.client.ExecuteTx(ctx, func(tx pgx.Tx) error {
+		return suite.repo.Record(ctx, tx, &models.AuditChange{
+			Action:     models.AuditAdjustmentReject,
+			EntityType: models.AuditEntityAdjustment,
+			EntityID:   entityId,
+			Before:     map[string]string{"status": "pending"},
+			After:      map[string]string{"status": "rejected"},
+		})
+	})
+	suite.Require().NoError(err)
+}
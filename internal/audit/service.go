@@ -0,0 +1,12 @@
+package audit
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+)
+
+// Интерфейс сервиса
+// Содержит в себе метод для проверки целостности цепочки хешей журнала аудита
+type Service interface {
+	VerifyChain(ctx context.Context) (*models.AuditVerificationResult, error)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"infotecstechtask/internal/audit"
+	"infotecstechtask/internal/models"
+)
+
+// Размер пачки записей, которые читаются из БД за один запрос при проверке цепочки
+const verificationBatchSize = 1000
+
+// Реализация сервиса
+// Ответственна за проверку целостности журнала аудита
+type AuditService struct {
+	auditRepository audit.Repository
+}
+
+func NewAuditService(auditRepository audit.Repository) *AuditService {
+	return &AuditService{
+		auditRepository: auditRepository,
+	}
+}
+
+// Реализация метода для проверки цепочек хешей
+//
+// Цепочки проверяются по очереди, записи каждой читаются по порядку и для каждой проверяется, что:
+// идентификатор следует за предыдущим без пропусков, prev_hash совпадает с хешем предыдущей записи,
+// а сохраненный хеш совпадает с пересчитанным.
+// В конце каждой цепочки проверяется, что последняя запись совпадает с audit_chain_head, что позволяет обнаружить удаление записей с конца
+func (s AuditService) VerifyChain(ctx context.Context) (*models.AuditVerificationResult, error) {
+	heads, err := s.auditRepository.GetHeads(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit chain heads: %w", err)
+	}
+
+	result := &models.AuditVerificationResult{}
+	for _, head := range heads {
+		ok, err := s.verifyChain(ctx, head, result)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return result, nil
+		}
+		result.Chains++
+	}
+
+	result.Valid = true
+	return result, nil
+}
+
+// Функция проверяет одну цепочку, при нарушении заполняет result и возвращает false
+func (s AuditService) verifyChain(ctx context.Context, head *models.AuditChainHead, result *models.AuditVerificationResult) (bool, error) {
+	var lastId int64
+	lastHash := audit.GenesisHash
+
+	for {
+		records, err := s.auditRepository.GetRecords(ctx, head.Chain, lastId, verificationBatchSize)
+		if err != nil {
+			return false, fmt.Errorf("failed to read audit records: %w", err)
+		}
+
+		for _, record := range records {
+			switch {
+			case record.ID != lastId+1:
+				broken(result, head.Chain, record.ID, fmt.Sprintf("expected record %d, found %d", lastId+1, record.ID))
+				return false, nil
+			case record.PrevHash != lastHash:
+				broken(result, head.Chain, record.ID, "prev_hash does not match hash of previous record")
+				return false, nil
+			case audit.ComputeHash(record) != record.Hash:
+				broken(result, head.Chain, record.ID, "record hash does not match its contents")
+				return false, nil
+			}
+
+			result.Checked++
+			lastId = record.ID
+			lastHash = record.Hash
+		}
+
+		if len(records) < verificationBatchSize {
+			break
+		}
+	}
+
+	if lastId != head.LastID || lastHash != head.LastHash {
+		broken(result, head.Chain, lastId+1, fmt.Sprintf("chain ends at record %d, head points to record %d", lastId, head.LastID))
+		return false, nil
+	}
+
+	return true, nil
+}
+
+func broken(result *models.AuditVerificationResult, chain int, id int64, reason string) {
+	result.Valid = false
+	result.BrokenChain = chain
+	result.BrokenAt = id
+	result.Reason = reason
+}
@@ -0,0 +1,6 @@
+package auth
+
+import "errors"
+
+// Список возможных ошибок аутентификации
+var ErrInvalidAPIKey = errors.New("Invalid API key")
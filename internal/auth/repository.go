@@ -0,0 +1,12 @@
+package auth
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+)
+
+// Интерфейс репозитория для поиска владельца API ключа
+// В БД хранятся только хеши ключей
+type Repository interface {
+	GetPrincipalByKeyHash(ctx context.Context, keyHash string) (*models.Principal, error)
+}
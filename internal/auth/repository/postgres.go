@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/pkg/database"
+)
+
+// Реализация репозитория
+type AuthRepository struct {
+	db *database.Client
+}
+
+func NewAuthRepository(db *database.Client) *AuthRepository {
+	return &AuthRepository{
+		db: db,
+	}
+}
+
+func (r AuthRepository) GetPrincipalByKeyHash(ctx context.Context, keyHash string) (*models.Principal, error) {
+	sql := `SELECT owner_id, role FROM api_keys WHERE key_hash = $1`
+
+	row := r.db.QueryRow(ctx, sql, keyHash)
+
+	principal := &models.Principal{}
+	err := row.Scan(&principal.ID, &principal.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return principal, nil
+}
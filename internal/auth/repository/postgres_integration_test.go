@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"infotecstechtask/internal/auth/service"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/pkg/database"
+	"infotecstechtask/test/testutils"
+	"log"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuthRepositoryTestSuite struct {
+	suite.Suite
+	pgContainer *testutils.PGTestContainer
+	repo        *AuthRepository
+	fixtures    *testutils.FixtureManager
+	ctx         context.Context
+}
+
+func (suite *AuthRepositoryTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+
+	container, err := testutils.StartPGContainer(suite.ctx)
+	if err != nil {
+		log.Fatalf("Failed to start test container: %v", err)
+	}
+	suite.pgContainer = container
+
+	client := database.NewClientWithPool(container.Pool)
+	suite.repo = NewAuthRepository(client)
+
+	suite.fixtures = testutils.NewFixtureManager(container.Pool)
+}
+
+func (suite *AuthRepositoryTestSuite) TearDownSuite() {
+	if suite.pgContainer != nil {
+		if err := suite.pgContainer.Close(suite.ctx); err != nil {
+			log.Printf("Failed to close test container: %v", err)
+		}
+	}
+}
+
+func (suite *AuthRepositoryTestSuite) BeforeTest(_, _ string) {
+	_, err := suite.pgContainer.Pool.Exec(suite.ctx, "TRUNCATE TABLE api_keys")
+	if err != nil {
+		log.Printf("TRUNCATE error: %v", err)
+	}
+
+	assert.NoError(suite.T(), err)
+}
+
+func TestAuthRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthRepositoryTestSuite))
+}
+
+func (suite *AuthRepositoryTestSuite) TestGetPrincipalByKeyHashSuccess() {
+	err := suite.fixtures.ApplySQLFixture(suite.ctx, "api_keys/api_keys.sql")
+	suite.Require().NoError(err)
+
+	testCases := []struct {
+		apiKey   string
+		expected models.Principal
+	}{
+		{"client-api-key", models.Principal{ID: uuid.MustParse("5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e01"), Role: models.RoleClient}},
+		{"admin-api-key", models.Principal{ID: uuid.MustParse("5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e03"), Role: models.RoleAdmin}},
+	}
+
+	for _, tc := range testCases {
+		actual, err := suite.repo.GetPrincipalByKeyHash(suite.ctx, service.HashAPIKey(tc.apiKey))
+		suite.Require().NoError(err)
+
+		suite.Assert().Equal(tc.expected, *actual)
+	}
+}
+
+func (suite *AuthRepositoryTestSuite) TestGetPrincipalByUnknownKeyHash() {
+	err := suite.fixtures.ApplySQLFixture(suite.ctx, "api_keys/api_keys.sql")
+	suite.Require().NoError(err)
+
+	_, err = suite.repo.GetPrincipalByKeyHash(suite.ctx, service.HashAPIKey("unknown-api-key"))
+	suite.Require().Error(err)
+
+	suite.Assert().Equal(pgx.ErrNoRows, err)
+}
@@ -0,0 +1,12 @@
+package auth
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+)
+
+// Интерфейс сервиса
+// Содержит в себе метод для аутентификации по API ключу
+type Service interface {
+	Authenticate(ctx context.Context, apiKey string) (*models.Principal, error)
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"infotecstechtask/internal/auth"
+	"infotecstechtask/internal/models"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// Реализация сервиса
+// Ответственна за хеширование ключа и обработку ошибок
+type AuthService struct {
+	authRepository auth.Repository
+}
+
+func NewAuthService(authRepository auth.Repository) *AuthService {
+	return &AuthService{
+		authRepository: authRepository,
+	}
+}
+
+func (s AuthService) Authenticate(ctx context.Context, apiKey string) (*models.Principal, error) {
+	principal, err := s.authRepository.GetPrincipalByKeyHash(ctx, HashAPIKey(apiKey))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, auth.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	return principal, nil
+}
+
+// Функция для получения хеша API ключа в том виде, в котором он хранится в БД
+func HashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,6 @@
+package balance
+
+import "errors"
+
+// Список возможных ошибок бизнес-логики баланса
+var ErrTooManyPoints = errors.New("Balance history must not exceed 1000 points")
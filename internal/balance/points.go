@@ -0,0 +1,29 @@
+package balance
+
+import (
+	"infotecstechtask/internal/models"
+	"time"
+)
+
+// Максимальное количество точек в ряду баланса
+const MaxPoints = 1000
+
+// Функция возвращает моменты ряда баланса: from, каждый шаг interval после него и to
+// Моменты переводятся в UTC, как и время транзакций в БД
+func Points(from time.Time, to time.Time, interval models.BalanceInterval) ([]time.Time, error) {
+	from, to = from.UTC(), to.UTC()
+
+	points := []time.Time{from}
+	for n := 1; ; n++ {
+		at := interval.Add(from, n)
+		if !at.Before(to) {
+			break
+		}
+		if len(points) == MaxPoints-1 {
+			return nil, ErrTooManyPoints
+		}
+		points = append(points, at)
+	}
+
+	return append(points, to), nil
+}
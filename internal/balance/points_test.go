@@ -0,0 +1,75 @@
+package balance
+
+import (
+	"infotecstechtask/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoints(t *testing.T) {
+	from := time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		to       time.Time
+		interval models.BalanceInterval
+		expected []time.Time
+	}{
+		{
+			name:     "last step shorter than interval",
+			to:       from.Add(36 * time.Hour),
+			interval: models.IntervalDay,
+			expected: []time.Time{from, from.AddDate(0, 0, 1), from.Add(36 * time.Hour)},
+		},
+		{
+			name:     "months counted from start",
+			to:       time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC),
+			interval: models.IntervalMonth,
+			expected: []time.Time{
+				from,
+				time.Date(2026, time.March, 3, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, time.March, 31, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:     "to on step boundary",
+			to:       from.AddDate(0, 0, 14),
+			interval: models.IntervalWeek,
+			expected: []time.Time{from, from.AddDate(0, 0, 7), from.AddDate(0, 0, 14)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := Points(from, tt.to, tt.interval)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestPointsLimit(t *testing.T) {
+	from := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	actual, err := Points(from, from.Add((MaxPoints-1)*time.Hour), models.IntervalHour)
+	require.NoError(t, err)
+	assert.Len(t, actual, MaxPoints)
+
+	_, err = Points(from, from.Add(MaxPoints*time.Hour), models.IntervalHour)
+	assert.ErrorIs(t, err, ErrTooManyPoints)
+}
+
+func TestPointsInUTC(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	from := time.Date(2026, time.January, 1, 3, 0, 0, 0, moscow)
+
+	actual, err := Points(from, from.Add(time.Hour), models.IntervalDay)
+	require.NoError(t, err)
+
+	assert.Equal(t, []time.Time{time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, time.January, 1, 1, 0, 0, 0, time.UTC)}, actual)
+}
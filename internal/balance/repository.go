@@ -0,0 +1,22 @@
+package balance
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Интерфейс репозитория для расчета баланса кошелька на момент времени
+// Балансы возвращаются в копейках, если кошелек не найден - pgx.ErrNoRows
+type Repository interface {
+	GetBalanceAt(ctx context.Context, walletId uuid.UUID, at time.Time) (int, error)
+	GetBalanceHistory(ctx context.Context, walletId uuid.UUID, points []time.Time) ([]int, error)
+}
+
+// Интерфейс репозитория снимков баланса
+// TakeSnapshots сохраняет снимки кошелька на все моменты до before, которых еще нет, и возвращает их количество
+type SnapshotRepository interface {
+	GetWalletsWithoutSnapshot(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error)
+	TakeSnapshots(ctx context.Context, walletId uuid.UUID, before time.Time) (int, error)
+}
@@ -0,0 +1,234 @@
+package postgres
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/pkg/database"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+// Баланс на момент $2 считается от ближайшего снимка после него (или от текущего баланса, если снимков нет)
+// за вычетом проведенных транзакций между $2 и снимком, поэтому читается не больше одного дня истории кошелька
+const balanceAtQuery = `SELECT a.balance - COALESCE((
+                            SELECT SUM(CASE WHEN t.to_address = $1 THEN t.amount ELSE -t.amount END)
+                            FROM transactions t
+                            WHERE (t.from_address = $1 OR t.to_address = $1)
+                              AND t.status = $3
+                              AND t.created_at >= $2
+                              AND t.created_at < a.taken_at
+                        ), 0)
+                        FROM (
+                            (SELECT balance, taken_at FROM balance_snapshots WHERE wallet_id = $1 AND taken_at >= $2 ORDER BY taken_at LIMIT 1)
+                            UNION ALL
+                            (SELECT balance, 'infinity'::TIMESTAMP FROM wallets WHERE id = $1)
+                            ORDER BY taken_at
+                            LIMIT 1
+                        ) a`
+
+// Реализация репозитория
+// Снимки хранят баланс на полночь UTC после каждого дня, в который у кошелька были проведенные транзакции
+type BalanceRepository struct {
+	db *database.Client
+}
+
+func NewBalanceRepository(db *database.Client) *BalanceRepository {
+	return &BalanceRepository{
+		db: db,
+	}
+}
+
+// Реализация метода для получения баланса на момент времени
+func (r BalanceRepository) GetBalanceAt(ctx context.Context, walletId uuid.UUID, at time.Time) (int, error) {
+	var balance int
+	err := r.db.ReadQueryRow(ctx, balanceAtQuery, walletId, at.UTC(), models.Completed).Scan(&balance)
+
+	return balance, err
+}
+
+// Реализация метода для получения ряда баланса
+//
+// Баланс на первый момент считается как в GetBalanceAt, остальные - прибавлением оборотов между соседними моментами,
+// которые собираются одним запросом. Оба запроса выполняются в одной транзакции только на чтение и видят одни и те же данные
+func (r BalanceRepository) GetBalanceHistory(ctx context.Context, walletId uuid.UUID, points []time.Time) ([]int, error) {
+	utcPoints := make([]time.Time, 0, len(points))
+	for _, point := range points {
+		utcPoints = append(utcPoints, point.UTC())
+	}
+
+	balances := make([]int, len(points))
+	err := r.db.ReadTx(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, balanceAtQuery, walletId, utcPoints[0], models.Completed).Scan(&balances[0])
+		if err != nil {
+			return err
+		}
+
+		// width_bucket возвращает i для транзакций из [points[i-1], points[i])
+		rows, err := tx.Query(ctx,
+			`SELECT width_bucket(created_at, $2::TIMESTAMP[]) AS bucket,
+                    SUM(CASE WHEN to_address = $1 THEN amount ELSE -amount END)
+             FROM transactions
+             WHERE (from_address = $1 OR to_address = $1)
+               AND status = $3
+               AND created_at >= $4
+               AND created_at < $5
+             GROUP BY bucket`,
+			walletId, utcPoints, models.Completed, utcPoints[0], utcPoints[len(utcPoints)-1],
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		deltas := make([]int, len(points))
+		for rows.Next() {
+			var bucket, delta int
+			if err := rows.Scan(&bucket, &delta); err != nil {
+				return err
+			}
+			deltas[bucket] = delta
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for i := 1; i < len(balances); i++ {
+			balances[i] = balances[i-1] + deltas[i]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return balances, nil
+}
+
+// Реализация метода для получения кошельков, у которых есть проведенные транзакции до before после последнего снимка
+func (r BalanceRepository) GetWalletsWithoutSnapshot(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT w.id
+         FROM wallets w
+         WHERE EXISTS (
+             SELECT 1
+             FROM transactions t
+             WHERE (t.from_address = w.id OR t.to_address = w.id)
+               AND t.status = $2
+               AND t.created_at < $1
+               AND t.created_at >= COALESCE((SELECT MAX(s.taken_at) FROM balance_snapshots s WHERE s.wallet_id = w.id), '-infinity')
+         )
+         ORDER BY w.id
+         LIMIT $3`,
+		before.UTC(), models.Completed, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	walletIds := make([]uuid.UUID, 0, limit)
+	for rows.Next() {
+		var walletId uuid.UUID
+		if err := rows.Scan(&walletId); err != nil {
+			return nil, err
+		}
+		walletIds = append(walletIds, walletId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return walletIds, nil
+}
+
+// Реализация метода для сохранения снимков баланса
+//
+// Снимок делается на полночь после каждого дня с проведенными транзакциями начиная с последнего снимка.
+// Балансы считаются назад от текущего баланса, поэтому первые снимки верны и для кошельков,
+// баланс которых был задан без транзакций. Если кошелек не найден, возвращается pgx.ErrNoRows
+func (r BalanceRepository) TakeSnapshots(ctx context.Context, walletId uuid.UUID, before time.Time) (int, error) {
+	before = before.UTC()
+
+	taken := 0
+	err := r.db.ExecuteTx(ctx, func(tx pgx.Tx) error {
+		taken = 0
+
+		var balance int
+		var lastSnapshot *time.Time
+		err := tx.QueryRow(ctx,
+			`SELECT w.balance, (SELECT MAX(s.taken_at) FROM balance_snapshots s WHERE s.wallet_id = w.id)
+             FROM wallets w
+             WHERE w.id = $1`,
+			walletId,
+		).Scan(&balance, &lastSnapshot)
+		if err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx,
+			`SELECT date_trunc('day', created_at) AS day,
+                    SUM(CASE WHEN to_address = $1 THEN amount ELSE -amount END)
+             FROM transactions
+             WHERE (from_address = $1 OR to_address = $1)
+               AND status = $2
+               AND ($3::TIMESTAMP IS NULL OR created_at >= $3)
+             GROUP BY day
+             ORDER BY day DESC`,
+			walletId, models.Completed, lastSnapshot,
+		)
+		if err != nil {
+			return err
+		}
+
+		type snapshot struct {
+			takenAt time.Time
+			balance int
+		}
+		var snapshots []snapshot
+		for rows.Next() {
+			var day time.Time
+			var delta int
+			if err := rows.Scan(&day, &delta); err != nil {
+				rows.Close()
+				return err
+			}
+
+			// balance - баланс без транзакций этого и следующих дней, то есть на полночь после этого дня
+			takenAt := day.AddDate(0, 0, 1)
+			if !takenAt.After(before) && (lastSnapshot == nil || takenAt.After(*lastSnapshot)) {
+				snapshots = append(snapshots, snapshot{takenAt: takenAt, balance: balance})
+			}
+			balance -= delta
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, s := range snapshots {
+			err := tx.QueryRow(ctx,
+				`INSERT INTO balance_snapshots (wallet_id, taken_at, balance) VALUES ($1, $2, $3)
+                 ON CONFLICT (wallet_id, taken_at) DO NOTHING
+                 RETURNING 1`,
+				walletId, s.takenAt, s.balance,
+			).Scan(new(int))
+			if err == pgx.ErrNoRows {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			taken++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return taken, nil
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"infotecstechtask/pkg/database"
+	"infotecstechtask/test/testutils"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type BalanceRepositoryTestSuite struct {
+	suite.Suite
+	pgContainer *testutils.PGTestContainer
+	repo        *BalanceRepository
+	fixtures    *testutils.FixtureManager
+	ctx         context.Context
+}
+
+func (suite *BalanceRepositoryTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+
+	container, err := testutils.StartPGContainer(suite.ctx)
+	if err != nil {
+		log.Fatalf("Failed to start test container: %v", err)
+	}
+	suite.pgContainer = container
+
+	client := database.NewClientWithPool(container.Pool)
+	suite.repo = NewBalanceRepository(client)
+
+	suite.fixtures = testutils.NewFixtureManager(container.Pool)
+}
+
+func (suite *BalanceRepositoryTestSuite) TearDownSuite() {
+	if suite.pgContainer != nil {
+		if err := suite.pgContainer.Close(suite.ctx); err != nil {
+			log.Printf("Failed to close test container: %v", err)
+		}
+	}
+}
+
+func (suite *BalanceRepositoryTestSuite) BeforeTest(_, _ string) {
+	_, err := suite.pgContainer.Pool.Exec(suite.ctx, "TRUNCATE TABLE wallets, transactions, balance_snapshots CASCADE")
+	if err != nil {
+		log.Printf("TRUNCATE error: %v", err)
+	}
+
+	assert.NoError(suite.T(), err)
+
+	err = suite.fixtures.ApplySQLFixture(suite.ctx, "wallets/wallets.sql")
+	suite.Require().NoError(err)
+
+	err = suite.fixtures.ApplySQLFixture(suite.ctx, "transactions/transactions.sql")
+	suite.Require().NoError(err)
+}
+
+func TestBalanceRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(BalanceRepositoryTestSuite))
+}
+
+func (suite *BalanceRepositoryTestSuite) TestGetBalanceAtSuccess() {
+	walletId := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10")
+
+	// Учитывается только проведенная транзакция от 2 августа
+	actual, err := suite.repo.GetBalanceAt(suite.ctx, walletId, time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC))
+	suite.Require().NoError(err)
+	suite.Assert().Equal(11330, actual)
+
+	actual, err = suite.repo.GetBalanceAt(suite.ctx, walletId, time.Date(2025, time.August, 2, 0, 0, 0, 0, time.UTC))
+	suite.Require().NoError(err)
+	suite.Assert().Equal(11330, actual)
+
+	actual, err = suite.repo.GetBalanceAt(suite.ctx, walletId, time.Date(2025, time.August, 3, 0, 0, 0, 0, time.UTC))
+	suite.Require().NoError(err)
+	suite.Assert().Equal(10000, actual)
+}
+
+func (suite *BalanceRepositoryTestSuite) TestGetBalanceAtForUnknownWallet() {
+	_, err := suite.repo.GetBalanceAt(suite.ctx, uuid.New(), time.Now())
+
+	suite.Assert().ErrorIs(err, pgx.ErrNoRows)
+}
+
+func (suite *BalanceRepositoryTestSuite) TestGetBalanceHistorySuccess() {
+	walletId := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10")
+	from := time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
+	points := []time.Time{from, from.AddDate(0, 0, 1), from.AddDate(0, 0, 2), from.AddDate(0, 0, 3)}
+
+	actual, err := suite.repo.GetBalanceHistory(suite.ctx, walletId, points)
+	suite.Require().NoError(err)
+	suite.Assert().Equal([]int{11330, 11330, 10000, 10000}, actual)
+
+	recipient := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12")
+	actual, err = suite.repo.GetBalanceHistory(suite.ctx, recipient, points)
+	suite.Require().NoError(err)
+	suite.Assert().Equal([]int{8670, 8670, 10000, 10000}, actual)
+}
+
+func (suite *BalanceRepositoryTestSuite) TestTakeSnapshotsSuccess() {
+	walletId := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10")
+	before := time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
+
+	pending, err := suite.repo.GetWalletsWithoutSnapshot(suite.ctx, before, 10)
+	suite.Require().NoError(err)
+	suite.Assert().ElementsMatch([]uuid.UUID{walletId, uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12")}, pending)
+
+	// Снимок не делается на границу после before
+	taken, err := suite.repo.TakeSnapshots(suite.ctx, walletId, time.Date(2025, time.August, 2, 12, 0, 0, 0, time.UTC))
+	suite.Require().NoError(err)
+	suite.Assert().Equal(0, taken)
+
+	taken, err = suite.repo.TakeSnapshots(suite.ctx, walletId, before)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(1, taken)
+
+	// Повторный вызов не создает новых снимков
+	taken, err = suite.repo.TakeSnapshots(suite.ctx, walletId, before)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(0, taken)
+
+	pending, err = suite.repo.GetWalletsWithoutSnapshot(suite.ctx, before, 10)
+	suite.Require().NoError(err)
+	suite.Assert().NotContains(pending, walletId)
+
+	// После снимка баланс считается от него и не меняется
+	actual, err := suite.repo.GetBalanceAt(suite.ctx, walletId, time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC))
+	suite.Require().NoError(err)
+	suite.Assert().Equal(11330, actual)
+
+	actual, err = suite.repo.GetBalanceAt(suite.ctx, walletId, time.Date(2025, time.August, 3, 0, 0, 0, 0, time.UTC))
+	suite.Require().NoError(err)
+	suite.Assert().Equal(10000, actual)
+}
+
+func (suite *BalanceRepositoryTestSuite) TestTakeSnapshotsForUnknownWallet() {
+	_, err := suite.repo.TakeSnapshots(suite.ctx, uuid.New(), time.Now())
+
+	suite.Assert().ErrorIs(err, pgx.ErrNoRows)
+}
@@ -0,0 +1,16 @@
+package balance
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Интерфейс сервиса
+// Баланс на момент и ряд баланса доступны владельцу кошелька или пользователю с ролью не ниже viewer
+type Service interface {
+	GetBalanceAt(ctx context.Context, principal *models.Principal, walletId uuid.UUID, at time.Time) (*models.WalletResponse, error)
+	GetBalanceHistory(ctx context.Context, principal *models.Principal, walletId uuid.UUID, from time.Time, to time.Time, interval models.BalanceInterval) (*models.BalanceHistoryResponse, error)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"infotecstechtask/internal/balance"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/wallet"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+// Реализация сервиса
+// Ответственна за проверку доступа к кошельку, построение моментов ряда и маппинг моделей
+type BalanceService struct {
+	balanceRepository balance.Repository
+	walletRepository  wallet.Repository
+}
+
+func NewBalanceService(balanceRepository balance.Repository, walletRepository wallet.Repository) *BalanceService {
+	return &BalanceService{
+		balanceRepository: balanceRepository,
+		walletRepository:  walletRepository,
+	}
+}
+
+// Реализация метода для получения баланса на момент времени
+// Баланс может получить владелец кошелька или пользователь с ролью не ниже viewer
+func (s BalanceService) GetBalanceAt(ctx context.Context, principal *models.Principal, walletId uuid.UUID, at time.Time) (*models.WalletResponse, error) {
+	if err := s.checkAccess(ctx, principal, walletId); err != nil {
+		return nil, err
+	}
+
+	result, err := s.balanceRepository.GetBalanceAt(ctx, walletId, at)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, wallet.ErrWalletNotFound
+		}
+		return nil, err
+	}
+
+	return models.ToWalletResponse(&models.Wallet{ID: walletId, Balance: result}), nil
+}
+
+// Реализация метода для получения ряда баланса
+//
+// Ряд может получить владелец кошелька или пользователь с ролью не ниже viewer.
+// Без interval баланс считается на каждый день, в ряду не больше balance.MaxPoints моментов
+func (s BalanceService) GetBalanceHistory(ctx context.Context, principal *models.Principal, walletId uuid.UUID, from time.Time, to time.Time, interval models.BalanceInterval) (*models.BalanceHistoryResponse, error) {
+	if interval == "" {
+		interval = models.IntervalDay
+	}
+	points, err := balance.Points(from, to, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkAccess(ctx, principal, walletId); err != nil {
+		return nil, err
+	}
+
+	balances, err := s.balanceRepository.GetBalanceHistory(ctx, walletId, points)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, wallet.ErrWalletNotFound
+		}
+		return nil, err
+	}
+
+	return models.ToBalanceHistoryResponse(walletId, interval, points, balances), nil
+}
+
+// Функция проверяет, что кошелек существует и принадлежит вызывающей стороне или её роль не ниже viewer
+func (s BalanceService) checkAccess(ctx context.Context, principal *models.Principal, walletId uuid.UUID) error {
+	walletToReport, err := s.walletRepository.GetWallet(ctx, walletId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return wallet.ErrWalletNotFound
+		}
+		return err
+	}
+	if walletToReport.OwnerID != principal.ID && !principal.Role.Allows(models.RoleViewer) {
+		return wallet.ErrWalletNotOwned
+	}
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package snapshotter
+
+import "time"
+
+// Структура, хранящая в себе параметры снимков баланса
+// Значения собираются пакетом internal/config
+//
+// Interval - период проверки, есть ли кошельки без снимков за прошедшие дни, BatchSize - количество кошельков в одном запросе
+type Config struct {
+	Interval  time.Duration
+	BatchSize int
+}
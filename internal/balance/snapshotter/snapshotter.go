@@ -0,0 +1,94 @@
+package snapshotter
+
+import (
+	"context"
+	"infotecstechtask/internal/balance"
+	"log/slog"
+	"time"
+)
+
+// Ключ advisory блокировки, которую удерживает процесс во время снимков баланса
+const snapshotterLockKey int64 = 7_263_003
+
+// Задержка снимка после окончания дня, чтобы успели провестись транзакции, созданные в конце дня
+const snapshotDelay = 1 * time.Hour
+
+// Интерфейс для выполнения функции под распределенной блокировкой
+type Locker interface {
+	WithAdvisoryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error)
+}
+
+// Фоновый процесс, сохраняющий баланс кошельков на конец каждого дня с проведенными транзакциями
+//
+// Баланс на произвольный момент считается от ближайшего следующего снимка,
+// поэтому запросы к старым кошелькам не читают всю историю транзакций.
+// Кошельки обрабатываются пачками по BatchSize, пропущенные дни досчитываются на следующем запуске
+type Snapshotter struct {
+	repository balance.SnapshotRepository
+	locker     Locker
+	interval   time.Duration
+	batchSize  int
+	now        func() time.Time
+}
+
+func NewSnapshotter(repository balance.SnapshotRepository, locker Locker, config Config) *Snapshotter {
+	return &Snapshotter{
+		repository: repository,
+		locker:     locker,
+		interval:   config.Interval,
+		batchSize:  config.BatchSize,
+		now:        time.Now,
+	}
+}
+
+// Функция запускает цикл снимков баланса и завершается при отмене контекста
+func (s *Snapshotter) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := s.locker.WithAdvisoryLock(ctx, snapshotterLockKey, s.takePending)
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "balance snapshotter failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Функция сохраняет снимки на все завершившиеся дни для кошельков, у которых их еще нет
+func (s *Snapshotter) takePending(ctx context.Context) error {
+	before := s.now().UTC().Add(-snapshotDelay).Truncate(24 * time.Hour)
+
+	taken := 0
+	for {
+		walletIds, err := s.repository.GetWalletsWithoutSnapshot(ctx, before, s.batchSize)
+		if err != nil {
+			return err
+		}
+
+		batchTaken := 0
+		for _, walletId := range walletIds {
+			count, err := s.repository.TakeSnapshots(ctx, walletId, before)
+			if err != nil {
+				return err
+			}
+			batchTaken += count
+		}
+		taken += batchTaken
+
+		// Пачка без новых снимков означает, что те же кошельки вернутся снова
+		if len(walletIds) < s.batchSize || batchTaken == 0 {
+			break
+		}
+	}
+
+	if taken > 0 {
+		slog.InfoContext(ctx, "balance snapshots taken", "before", before, "count", taken)
+	}
+	return nil
+}
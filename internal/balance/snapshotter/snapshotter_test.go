@@ -0,0 +1,73 @@
+package snapshotter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepository struct {
+	pending map[uuid.UUID]int
+	order   []uuid.UUID
+	before  []time.Time
+	failing map[uuid.UUID]bool
+}
+
+func (r *fakeRepository) GetWalletsWithoutSnapshot(_ context.Context, _ time.Time, limit int) ([]uuid.UUID, error) {
+	var walletIds []uuid.UUID
+	for _, walletId := range r.order {
+		if r.pending[walletId] > 0 {
+			walletIds = append(walletIds, walletId)
+		}
+	}
+	return walletIds[:min(limit, len(walletIds))], nil
+}
+
+func (r *fakeRepository) TakeSnapshots(_ context.Context, walletId uuid.UUID, before time.Time) (int, error) {
+	if r.failing[walletId] {
+		return 0, errors.New("unavailable")
+	}
+	r.before = append(r.before, before)
+
+	taken := r.pending[walletId]
+	delete(r.pending, walletId)
+	return taken, nil
+}
+
+type fakeLocker struct{}
+
+func (fakeLocker) WithAdvisoryLock(ctx context.Context, _ int64, fn func(ctx context.Context) error) (bool, error) {
+	return true, fn(ctx)
+}
+
+func TestTakePendingCoversAllWallets(t *testing.T) {
+	repository := &fakeRepository{order: []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}}
+	repository.pending = map[uuid.UUID]int{repository.order[0]: 2, repository.order[1]: 1, repository.order[2]: 5}
+	snapshotter := NewSnapshotter(repository, fakeLocker{}, Config{Interval: time.Hour, BatchSize: 2})
+	snapshotter.now = func() time.Time { return time.Date(2026, time.March, 15, 0, 30, 0, 0, time.UTC) }
+
+	require.NoError(t, snapshotter.takePending(context.Background()))
+
+	assert.Empty(t, repository.pending)
+	// Снимки делаются только на полночь, после которой прошла задержка
+	require.Len(t, repository.before, 3)
+	for _, before := range repository.before {
+		assert.Equal(t, time.Date(2026, time.March, 14, 0, 0, 0, 0, time.UTC), before)
+	}
+}
+
+func TestTakePendingStopsOnError(t *testing.T) {
+	repository := &fakeRepository{order: []uuid.UUID{uuid.New(), uuid.New()}}
+	repository.pending = map[uuid.UUID]int{repository.order[0]: 1, repository.order[1]: 1}
+	repository.failing = map[uuid.UUID]bool{repository.order[0]: true}
+
+	err := NewSnapshotter(repository, fakeLocker{}, Config{Interval: time.Hour, BatchSize: 10}).takePending(context.Background())
+
+	assert.Error(t, err)
+	assert.Empty(t, repository.before)
+}
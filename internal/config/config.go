@@ -0,0 +1,239 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	bsnapshotter "infotecstechtask/internal/balance/snapshotter"
+	dgrpc "infotecstechtask/internal/delivery/grpc"
+	"infotecstechtask/internal/health"
+	"infotecstechtask/internal/metrics"
+	"infotecstechtask/internal/middleware"
+	opublisher "infotecstechtask/internal/outbox/publisher"
+	orelay "infotecstechtask/internal/outbox/relay"
+	stgenerator "infotecstechtask/internal/statement/generator"
+	"infotecstechtask/internal/tracing"
+	whdispatcher "infotecstechtask/internal/webhook/dispatcher"
+	"infotecstechtask/pkg/database"
+	"infotecstechtask/pkg/lifecycle"
+	"infotecstechtask/pkg/logger"
+	"log/slog"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Режимы sslmode, которые понимает pgx
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// Общая конфигурация приложения
+//
+// Значения собираются по возрастанию приоритета: значения по умолчанию, файл конфигурации (YAML или TOML),
+// переменные окружения, флаги командной строки. Полный список параметров задается в settings.go
+type Config struct {
+	Server           ServerConfig
+	GRPC             dgrpc.Config
+	Database         database.Config
+	Migrations       MigrationsConfig
+	RateLimit        RateLimitConfig
+	Timeouts         middleware.TimeoutConfig
+	Shutdown         lifecycle.Config
+	Health           health.Config
+	Log              logger.Config
+	Metrics          metrics.Config
+	Tracing          tracing.Config
+	Outbox           opublisher.Config
+	OutboxRelay      orelay.Config
+	Webhooks         whdispatcher.Config
+	Statements       stgenerator.Config
+	BalanceSnapshots bsnapshotter.Config
+}
+
+// Параметры http сервера
+type ServerConfig struct {
+	Port           string
+	ReadTimeout    time.Duration
+	WriteTimeout   time.Duration
+	MaxHeaderBytes int
+}
+
+// Параметры миграций схемы
+// Если Auto включен, при старте применяются все встроенные миграции, которых еще нет в БД
+type MigrationsConfig struct {
+	Auto bool
+}
+
+// Параметры рейт лимитера: не более Requests запросов с одного IP за Period
+type RateLimitConfig struct {
+	Requests int64
+	Period   time.Duration
+}
+
+// Функция возвращает конфигурацию со значениями по умолчанию
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:           "8080",
+			ReadTimeout:    10 * time.Second,
+			WriteTimeout:   10 * time.Second,
+			MaxHeaderBytes: 1 << 20,
+		},
+		GRPC: dgrpc.Config{
+			Port:    "50051",
+			Timeout: 5 * time.Second,
+		},
+		Database: database.Config{
+			Host:            "localhost",
+			Port:            "5432",
+			SSLMode:         "prefer",
+			ApplicationName: "wallet-service",
+			ConnectTimeout:  30 * time.Second,
+			MaxReplicaLag:   time.Second,
+			Pool: database.PoolConfig{
+				MaxConns:          10,
+				MaxConnLifetime:   time.Hour,
+				MaxConnIdleTime:   30 * time.Minute,
+				HealthCheckPeriod: time.Minute,
+			},
+		},
+		RateLimit: RateLimitConfig{
+			Requests: 100,
+			Period:   time.Minute,
+		},
+		Timeouts: middleware.TimeoutConfig{
+			Default: 5 * time.Second,
+			Routes:  make(map[string]time.Duration),
+		},
+		Shutdown: lifecycle.Config{
+			ShutdownTimeout: 15 * time.Second,
+		},
+		Health: health.Config{
+			DrainDelay: 5 * time.Second,
+		},
+		Log: logger.Config{
+			Level:  slog.LevelInfo,
+			Format: logger.FormatJSON,
+		},
+		Tracing: tracing.Config{
+			Exporter:    tracing.ExporterNone,
+			ServiceName: "wallet-service",
+		},
+		Outbox: opublisher.Config{
+			Kind: opublisher.KindStdout,
+		},
+		OutboxRelay: orelay.Config{
+			Interval:  time.Second,
+			BatchSize: 100,
+		},
+		Webhooks: whdispatcher.Config{
+			MaxAttempts: 8,
+			Timeout:     10 * time.Second,
+		},
+		Statements: stgenerator.Config{
+			Interval:  time.Hour,
+			BatchSize: 100,
+		},
+		BalanceSnapshots: bsnapshotter.Config{
+			Interval:  time.Hour,
+			BatchSize: 100,
+		},
+	}
+}
+
+// Функция проверяет конфигурацию и возвращает все найденные ошибки сразу
+// В тексте ошибки указывается ключ параметра из файла конфигурации
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key string, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+
+	check(isPort(c.Server.Port), "server.port", "must be a number from 1 to 65535, got %q", c.Server.Port)
+	check(c.Server.ReadTimeout > 0, "server.read_timeout", "must be positive")
+	check(c.Server.WriteTimeout > 0, "server.write_timeout", "must be positive")
+	check(c.Server.MaxHeaderBytes > 0, "server.max_header_bytes", "must be positive")
+
+	if c.GRPC.Port != "" {
+		check(isPort(c.GRPC.Port), "grpc.port", "must be empty or a number from 1 to 65535, got %q", c.GRPC.Port)
+		check(c.GRPC.Port != c.Server.Port, "grpc.port", "must differ from server.port")
+	}
+	check(c.GRPC.Timeout >= 0, "grpc.timeout", "must not be negative")
+
+	if c.Database.URL != "" {
+		_, err := c.Database.ConnString()
+		check(err == nil, "database.url", "%v", err)
+	} else {
+		check(c.Database.Host != "", "database.host", "is required")
+		check(isPort(c.Database.Port), "database.port", "must be a number from 1 to 65535, got %q", c.Database.Port)
+		check(c.Database.User != "", "database.user", "is required")
+		check(c.Database.DBName != "", "database.name", "is required")
+		check(slices.Contains(sslModes, c.Database.SSLMode), "database.ssl_mode", "must be one of %v, got %q", sslModes, c.Database.SSLMode)
+	}
+	check((c.Database.SSLCert == "") == (c.Database.SSLKey == ""), "database.ssl_cert", "must be set together with database.ssl_key")
+	check(c.Database.StatementTimeout >= 0, "database.statement_timeout", "must not be negative")
+	check(c.Database.ConnectTimeout > 0, "database.connect_timeout", "must be positive")
+	for i, replicaURL := range c.Database.ReplicaURLs {
+		_, err := database.Config{URL: replicaURL}.ConnString()
+		check(err == nil, fmt.Sprintf("database.replica_urls[%d]", i), "%v", err)
+	}
+	check(c.Database.MaxReplicaLag > 0, "database.max_replica_lag", "must be positive")
+
+	pool := c.Database.Pool
+	check(pool.MaxConns > 0, "pool.max_conns", "must be positive")
+	check(pool.MinConns >= 0 && pool.MinConns <= pool.MaxConns, "pool.min_conns", "must be from 0 to pool.max_conns (%d)", pool.MaxConns)
+	check(pool.MaxConnLifetime > 0, "pool.max_conn_lifetime", "must be positive")
+	check(pool.MaxConnIdleTime > 0, "pool.max_conn_idle_time", "must be positive")
+	check(pool.HealthCheckPeriod > 0, "pool.health_check_period", "must be positive")
+
+	check(c.RateLimit.Requests > 0, "rate_limit.requests", "must be positive")
+	check(c.RateLimit.Period > 0, "rate_limit.period", "must be positive")
+
+	check(c.Timeouts.Default >= 0, "timeouts.default", "must not be negative")
+
+	check(c.Shutdown.ShutdownTimeout > 0, "shutdown.timeout", "must be positive")
+	check(c.Health.DrainDelay >= 0 && c.Health.DrainDelay < c.Shutdown.ShutdownTimeout, "health.drain_delay", "must be from 0 to shutdown.timeout (%s)", c.Shutdown.ShutdownTimeout)
+
+	if c.Metrics.Addr != "" {
+		_, port, err := net.SplitHostPort(c.Metrics.Addr)
+		check(err == nil && isPort(port), "metrics.addr", "must be empty or host:port, got %q", c.Metrics.Addr)
+		check(err != nil || port != c.Server.Port, "metrics.addr", "port must differ from server.port")
+	}
+
+	check(slices.Contains(logger.Formats, c.Log.Format), "log.format", "must be one of %v, got %q", logger.Formats, c.Log.Format)
+
+	check(slices.Contains(tracing.Exporters, c.Tracing.Exporter), "tracing.exporter", "must be one of %v, got %q", tracing.Exporters, c.Tracing.Exporter)
+	check(c.Tracing.ServiceName != "", "tracing.service_name", "is required")
+	if c.Tracing.Exporter == tracing.ExporterFile {
+		check(c.Tracing.FilePath != "", "tracing.file_path", "is required for %s exporter", tracing.ExporterFile)
+	}
+
+	check(slices.Contains(opublisher.Kinds, c.Outbox.Kind), "outbox.publisher", "must be one of %v, got %q", opublisher.Kinds, c.Outbox.Kind)
+	switch c.Outbox.Kind {
+	case opublisher.KindFile:
+		check(c.Outbox.FilePath != "", "outbox.file_path", "is required for %s publisher", opublisher.KindFile)
+	case opublisher.KindWebhook:
+		target, err := url.Parse(c.Outbox.WebhookURL)
+		check(err == nil && (target.Scheme == "http" || target.Scheme == "https") && target.Host != "", "outbox.webhook_url", "must be an http or https URL for %s publisher", opublisher.KindWebhook)
+	}
+	check(c.OutboxRelay.Interval > 0, "outbox.interval", "must be positive")
+	check(c.OutboxRelay.BatchSize > 0, "outbox.batch_size", "must be positive")
+
+	check(c.Webhooks.MaxAttempts > 0, "webhooks.max_attempts", "must be positive")
+	check(c.Webhooks.Timeout > 0, "webhooks.timeout", "must be positive")
+
+	check(c.Statements.Interval > 0, "statements.interval", "must be positive")
+	check(c.Statements.BatchSize > 0, "statements.batch_size", "must be positive")
+
+	check(c.BalanceSnapshots.Interval > 0, "balance_snapshots.interval", "must be positive")
+	check(c.BalanceSnapshots.BatchSize > 0, "balance_snapshots.batch_size", "must be positive")
+
+	return errors.Join(errs...)
+}
+
+func isPort(value string) bool {
+	port, err := strconv.Atoi(value)
+	return err == nil && port > 0 && port <= 65535
+}
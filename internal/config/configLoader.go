@@ -0,0 +1,228 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Значение, которым в выводе --print-config заменяются секреты
+const redacted = "[REDACTED]"
+
+// Параметры запуска, которые не входят в конфигурацию приложения
+// File можно задать флагом --config или переменной окружения CONFIG_FILE
+type Options struct {
+	File        string
+	PrintConfig bool
+}
+
+// Функция для загрузки конфига: собирает значения из всех источников и проверяет результат
+// args - аргументы командной строки без имени программы, при --help возвращается flag.ErrHelp
+func Load(args []string) (Config, Options, error) {
+	return load(args, os.LookupEnv, os.Stderr)
+}
+
+func load(args []string, lookupEnv func(string) (string, bool), output io.Writer) (Config, Options, error) {
+	config := Default()
+	table := settings(&config)
+
+	var options Options
+	options.File, _ = lookupEnv("CONFIG_FILE")
+
+	flags := flag.NewFlagSet("infotecs-tech-task", flag.ContinueOnError)
+	flags.SetOutput(output)
+	flags.StringVar(&options.File, "config", options.File, "path to YAML or TOML config file (env CONFIG_FILE)")
+	flags.BoolVar(&options.PrintConfig, "print-config", false, "print the effective config with secrets redacted and exit")
+
+	// Флаги имеют наивысший приоритет, но файл конфигурации известен только после разбора флагов,
+	// поэтому значения флагов запоминаются и применяются последними
+	var flagValues []func() error
+	for _, s := range table {
+		usage := fmt.Sprintf("%s (env %s)", s.usage, s.env)
+		record := func(raw string) error {
+			flagValues = append(flagValues, func() error {
+				return wrapSettingError(s, "flag --"+s.flagName(), s.value.Set(raw))
+			})
+			return nil
+		}
+		// Логический флаг можно указать без значения: --migrations-auto
+		if _, ok := s.value.(*boolValue); ok {
+			flags.BoolFunc(s.flagName(), usage, record)
+		} else {
+			flags.Func(s.flagName(), usage, record)
+		}
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return config, options, err
+	}
+	if flags.NArg() > 0 {
+		return config, options, fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	var errs []error
+	if options.File != "" {
+		errs = append(errs, loadFile(options.File, table))
+	}
+	for _, s := range table {
+		// Пустые переменные считаются незаданными: docker-compose передает их, даже если в .env значения нет
+		if raw, ok := lookupEnv(s.env); ok && raw != "" {
+			errs = append(errs, wrapSettingError(s, "env "+s.env, s.value.Set(raw)))
+		}
+	}
+	for _, apply := range flagValues {
+		errs = append(errs, apply())
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return config, options, err
+	}
+
+	return config, options, config.Validate()
+}
+
+func wrapSettingError(s setting, source string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s (%s): %w", s.key, source, err)
+}
+
+// Функция применяет значения из файла конфигурации, формат определяется по расширению
+// Неизвестные ключи считаются ошибкой, чтобы опечатка в имени параметра не проходила молча
+func loadFile(path string, table []setting) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	raw := make(map[string]any)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return fmt.Errorf("config file %s: unsupported format, expected .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	byKey := make(map[string]setting, len(table))
+	for _, s := range table {
+		byKey[s.key] = s
+	}
+
+	var errs []error
+	var walk func(prefix string, values map[string]any)
+	walk = func(prefix string, values map[string]any) {
+		for _, name := range sortedKeys(values) {
+			key := name
+			if prefix != "" {
+				key = prefix + "." + name
+			}
+
+			s, known := byKey[key]
+			nested, isMap := values[name].(map[string]any)
+			switch {
+			case known:
+				errs = append(errs, wrapSettingError(s, "config file "+path, setFileValue(s, values[name])))
+			case isMap:
+				walk(key, nested)
+			default:
+				errs = append(errs, fmt.Errorf("config file %s: unknown key %q", path, key))
+			}
+		}
+	}
+	walk("", raw)
+
+	return errors.Join(errs...)
+}
+
+func setFileValue(s setting, raw any) error {
+	switch raw := raw.(type) {
+	case map[string]any:
+		routes, err := routesString(raw)
+		if err != nil {
+			return err
+		}
+		return s.value.Set(routes)
+	case []any:
+		items := make([]string, 0, len(raw))
+		for _, item := range raw {
+			switch item.(type) {
+			case map[string]any, []any, nil:
+				return errors.New("list items must be scalar values")
+			}
+			if strings.Contains(fmt.Sprint(item), ",") {
+				return errors.New("list items must not contain ','")
+			}
+			items = append(items, fmt.Sprint(item))
+		}
+		return s.value.Set(strings.Join(items, ","))
+	case nil:
+		return s.value.Set("")
+	default:
+		return s.value.Set(fmt.Sprint(raw))
+	}
+}
+
+// Функция заменяет непустые значения секрета, у списков сохраняется количество элементов
+func redact(value any) any {
+	switch value := value.(type) {
+	case string:
+		if value != "" {
+			return redacted
+		}
+	case []string:
+		list := make([]string, len(value))
+		for i := range list {
+			list[i] = redacted
+		}
+		return list
+	}
+	return value
+}
+
+func sortedKeys(values map[string]any) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Функция выводит итоговую конфигурацию в формате YAML, который можно использовать как файл конфигурации
+// Значения секретов заменяются на [REDACTED]
+func Print(w io.Writer, config Config) error {
+	sections := make(map[string]map[string]any)
+	for _, s := range settings(&config) {
+		section, name, _ := strings.Cut(s.key, ".")
+		if sections[section] == nil {
+			sections[section] = make(map[string]any)
+		}
+
+		value := s.value.Get()
+		if s.secret {
+			value = redact(value)
+		}
+		sections[section][name] = value
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(sections); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"infotecstechtask/internal/middleware"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Параметр конфигурации
+// key - путь в файле конфигурации, из него же строится имя флага: "rate_limit.period" -> --rate-limit-period
+// Значения secret скрываются в выводе --print-config
+type setting struct {
+	key    string
+	env    string
+	usage  string
+	secret bool
+	value  value
+}
+
+// Значение параметра, в строковом виде оно приходит из всех источников
+type value interface {
+	Set(raw string) error
+	// Значение для вывода --print-config в том виде, в каком его можно записать в файл конфигурации
+	Get() any
+}
+
+func (s setting) flagName() string {
+	return strings.NewReplacer(".", "-", "_", "-").Replace(s.key)
+}
+
+// Функция описывает все параметры конфигурации, значения записываются в config
+func settings(config *Config) []setting {
+	return []setting{
+		{key: "server.port", env: "APP_PORT", usage: "http server port", value: (*stringValue)(&config.Server.Port)},
+		{key: "server.read_timeout", env: "SERVER_READ_TIMEOUT", usage: "maximum duration for reading the whole request", value: (*durationValue)(&config.Server.ReadTimeout)},
+		{key: "server.write_timeout", env: "SERVER_WRITE_TIMEOUT", usage: "maximum duration before timing out writes of the response", value: (*durationValue)(&config.Server.WriteTimeout)},
+		{key: "server.max_header_bytes", env: "SERVER_MAX_HEADER_BYTES", usage: "maximum size of request headers", value: &intValue[int]{&config.Server.MaxHeaderBytes}},
+
+		{key: "grpc.port", env: "GRPC_PORT", usage: "grpc server port, empty disables the grpc server", value: (*stringValue)(&config.GRPC.Port)},
+		{key: "grpc.timeout", env: "GRPC_TIMEOUT", usage: "deadline of unary grpc calls, 0 disables it", value: (*durationValue)(&config.GRPC.Timeout)},
+
+		{key: "database.url", env: "DATABASE_URL", usage: "full postgres connection URL, replaces host, port, user, password, name and ssl_mode", secret: true, value: (*stringValue)(&config.Database.URL)},
+		{key: "database.host", env: "POSTGRES_HOST", usage: "postgres host", value: (*stringValue)(&config.Database.Host)},
+		{key: "database.port", env: "POSTGRES_PORT", usage: "postgres port", value: (*stringValue)(&config.Database.Port)},
+		{key: "database.user", env: "POSTGRES_USER", usage: "postgres user", value: (*stringValue)(&config.Database.User)},
+		{key: "database.password", env: "POSTGRES_PASSWORD", usage: "postgres password", secret: true, value: (*stringValue)(&config.Database.Password)},
+		{key: "database.name", env: "POSTGRES_DB_NAME", usage: "postgres database name", value: (*stringValue)(&config.Database.DBName)},
+		{key: "database.ssl_mode", env: "POSTGRES_SSL_MODE", usage: "postgres sslmode", value: (*stringValue)(&config.Database.SSLMode)},
+		{key: "database.ssl_root_cert", env: "POSTGRES_SSL_ROOT_CERT", usage: "path to the server root CA certificate for verify-ca and verify-full", value: (*stringValue)(&config.Database.SSLRootCert)},
+		{key: "database.ssl_cert", env: "POSTGRES_SSL_CERT", usage: "path to the client certificate", value: (*stringValue)(&config.Database.SSLCert)},
+		{key: "database.ssl_key", env: "POSTGRES_SSL_KEY", usage: "path to the client certificate key", value: (*stringValue)(&config.Database.SSLKey)},
+		{key: "database.application_name", env: "POSTGRES_APPLICATION_NAME", usage: "application_name reported to postgres", value: (*stringValue)(&config.Database.ApplicationName)},
+		{key: "database.statement_timeout", env: "POSTGRES_STATEMENT_TIMEOUT", usage: "statement_timeout for all connections, 0 keeps the server value", value: (*durationValue)(&config.Database.StatementTimeout)},
+		{key: "database.connect_timeout", env: "POSTGRES_CONNECT_TIMEOUT", usage: "total time to retry connecting at startup", value: (*durationValue)(&config.Database.ConnectTimeout)},
+		{key: "database.replica_urls", env: "DATABASE_REPLICA_URLS", usage: "comma-separated postgres URLs of read replicas", secret: true, value: (*listValue)(&config.Database.ReplicaURLs)},
+		{key: "database.max_replica_lag", env: "DATABASE_MAX_REPLICA_LAG", usage: "replicas lagging behind the primary by more than this are not used for reads", value: (*durationValue)(&config.Database.MaxReplicaLag)},
+
+		{key: "pool.max_conns", env: "POSTGRES_MAX_CONNS", usage: "maximum size of the connection pool", value: &intValue[int32]{&config.Database.Pool.MaxConns}},
+		{key: "pool.min_conns", env: "POSTGRES_MIN_CONNS", usage: "minimum size of the connection pool", value: &intValue[int32]{&config.Database.Pool.MinConns}},
+		{key: "pool.max_conn_lifetime", env: "POSTGRES_MAX_CONN_LIFETIME", usage: "duration after which a connection is closed", value: (*durationValue)(&config.Database.Pool.MaxConnLifetime)},
+		{key: "pool.max_conn_idle_time", env: "POSTGRES_MAX_CONN_IDLE_TIME", usage: "duration after which an idle connection is closed", value: (*durationValue)(&config.Database.Pool.MaxConnIdleTime)},
+		{key: "pool.health_check_period", env: "POSTGRES_HEALTH_CHECK_PERIOD", usage: "interval between health checks of idle connections", value: (*durationValue)(&config.Database.Pool.HealthCheckPeriod)},
+
+		{key: "migrations.auto", env: "MIGRATE_ON_START", usage: "apply embedded migrations at startup", value: (*boolValue)(&config.Migrations.Auto)},
+
+		{key: "rate_limit.requests", env: "RATE_LIMIT_REQUESTS", usage: "maximum number of requests from one IP per period", value: &intValue[int64]{&config.RateLimit.Requests}},
+		{key: "rate_limit.period", env: "RATE_LIMIT_PERIOD", usage: "rate limit period", value: (*durationValue)(&config.RateLimit.Period)},
+
+		{key: "timeouts.default", env: "REQUEST_TIMEOUT", usage: "default request timeout, 0 disables it", value: (*durationValue)(&config.Timeouts.Default)},
+		{key: "timeouts.routes", env: "ROUTE_TIMEOUTS", usage: `per-route timeouts, e.g. "POST /api/send=3s,GET /api/transactions=10s"`, value: (*routesValue)(&config.Timeouts)},
+
+		{key: "shutdown.timeout", env: "SHUTDOWN_TIMEOUT", usage: "total time to finish requests, stop background workers and release resources", value: (*durationValue)(&config.Shutdown.ShutdownTimeout)},
+		{key: "health.drain_delay", env: "READINESS_DRAIN_DELAY", usage: "delay between failing readiness and stopping the servers", value: (*durationValue)(&config.Health.DrainDelay)},
+
+		{key: "log.level", env: "LOG_LEVEL", usage: "minimum log level: debug, info, warn or error", value: (*levelValue)(&config.Log.Level)},
+		{key: "log.format", env: "LOG_FORMAT", usage: "log format: json or text", value: (*stringValue)(&config.Log.Format)},
+
+		{key: "metrics.addr", env: "METRICS_ADDR", usage: "separate host:port for /metrics, empty serves it from the http server", value: (*stringValue)(&config.Metrics.Addr)},
+
+		{key: "tracing.exporter", env: "TRACING_EXPORTER", usage: "trace exporter: none, otlp, stdout or file", value: (*stringValue)(&config.Tracing.Exporter)},
+		{key: "tracing.file_path", env: "TRACING_FILE_PATH", usage: "file for the file trace exporter", value: (*stringValue)(&config.Tracing.FilePath)},
+		{key: "tracing.service_name", env: "OTEL_SERVICE_NAME", usage: "service.name attribute of exported spans", value: (*stringValue)(&config.Tracing.ServiceName)},
+
+		{key: "outbox.publisher", env: "OUTBOX_PUBLISHER", usage: "event publisher: stdout, file or webhook", value: (*stringValue)(&config.Outbox.Kind)},
+		{key: "outbox.file_path", env: "OUTBOX_FILE_PATH", usage: "file for the file event publisher", value: (*stringValue)(&config.Outbox.FilePath)},
+		{key: "outbox.webhook_url", env: "OUTBOX_WEBHOOK_URL", usage: "URL for the webhook event publisher", secret: true, value: (*stringValue)(&config.Outbox.WebhookURL)},
+		{key: "outbox.interval", env: "OUTBOX_RELAY_INTERVAL", usage: "interval between checks for unpublished outbox events", value: (*durationValue)(&config.OutboxRelay.Interval)},
+		{key: "outbox.batch_size", env: "OUTBOX_RELAY_BATCH_SIZE", usage: "number of outbox events read in one query", value: &intValue[int]{&config.OutboxRelay.BatchSize}},
+
+		{key: "webhooks.max_attempts", env: "WEBHOOK_MAX_ATTEMPTS", usage: "failed attempts after which a webhook delivery is moved to dead", value: &intValue[int]{&config.Webhooks.MaxAttempts}},
+		{key: "webhooks.timeout", env: "WEBHOOK_TIMEOUT", usage: "time to wait for a webhook receiver response", value: (*durationValue)(&config.Webhooks.Timeout)},
+
+		{key: "statements.interval", env: "STATEMENT_GENERATOR_INTERVAL", usage: "interval between checks for wallets without last month statement", value: (*durationValue)(&config.Statements.Interval)},
+		{key: "statements.batch_size", env: "STATEMENT_GENERATOR_BATCH_SIZE", usage: "number of wallets read in one statement generation query", value: &intValue[int]{&config.Statements.BatchSize}},
+
+		{key: "balance_snapshots.interval", env: "BALANCE_SNAPSHOT_INTERVAL", usage: "interval between checks for wallets without daily balance snapshots", value: (*durationValue)(&config.BalanceSnapshots.Interval)},
+		{key: "balance_snapshots.batch_size", env: "BALANCE_SNAPSHOT_BATCH_SIZE", usage: "number of wallets read in one balance snapshot query", value: &intValue[int]{&config.BalanceSnapshots.BatchSize}},
+	}
+}
+
+type stringValue string
+
+func (v *stringValue) Set(raw string) error {
+	*v = stringValue(raw)
+	return nil
+}
+
+func (v *stringValue) Get() any {
+	return string(*v)
+}
+
+// Список задается значениями через запятую, каждый источник заменяет список целиком
+type listValue []string
+
+func (v *listValue) Set(raw string) error {
+	list := listValue{}
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	*v = list
+	return nil
+}
+
+func (v *listValue) Get() any {
+	return []string(*v)
+}
+
+type boolValue bool
+
+func (v *boolValue) Set(raw string) error {
+	parsed, err := strconv.ParseBool(raw)
+	if err != nil {
+		return fmt.Errorf("invalid boolean %q, expected true or false", raw)
+	}
+	*v = boolValue(parsed)
+	return nil
+}
+
+func (v *boolValue) Get() any {
+	return bool(*v)
+}
+
+type durationValue time.Duration
+
+func (v *durationValue) Set(raw string) error {
+	duration, err := time.ParseDuration(raw)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q, expected a value like 500ms, 10s or 1m", raw)
+	}
+	*v = durationValue(duration)
+	return nil
+}
+
+func (v *durationValue) Get() any {
+	return time.Duration(*v).String()
+}
+
+type intValue[T ~int | ~int32 | ~int64] struct {
+	p *T
+}
+
+func (v *intValue[T]) Set(raw string) error {
+	parsed, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || int64(T(parsed)) != parsed {
+		return fmt.Errorf("invalid integer %q", raw)
+	}
+	*v.p = T(parsed)
+	return nil
+}
+
+func (v *intValue[T]) Get() any {
+	return int64(*v.p)
+}
+
+// Уровень логирования задается именем (debug, info, warn, error), допускается смещение вида "debug+2"
+type levelValue slog.Level
+
+func (v *levelValue) Set(raw string) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(raw)); err != nil {
+		return fmt.Errorf("invalid log level %q, expected debug, info, warn or error", raw)
+	}
+	*v = levelValue(level)
+	return nil
+}
+
+func (v *levelValue) Get() any {
+	return strings.ToLower(slog.Level(*v).String())
+}
+
+// Таймауты маршрутов задаются списком "METHOD /path=duration" через запятую
+// Каждый источник дополняет таймауты маршрутов, заданные источниками с меньшим приоритетом
+type routesValue middleware.TimeoutConfig
+
+func (v *routesValue) Set(raw string) error {
+	config := (*middleware.TimeoutConfig)(v)
+	for _, entry := range strings.Split(raw, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		route, rawTimeout, ok := strings.Cut(entry, "=")
+		method, path, hasPath := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok || !hasPath || !strings.HasPrefix(strings.TrimSpace(path), "/") {
+			return fmt.Errorf("invalid route timeout %q, expected \"METHOD /path=duration\"", strings.TrimSpace(entry))
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(rawTimeout))
+		if err != nil || timeout < 0 {
+			return fmt.Errorf("invalid timeout in %q, expected a non-negative duration", strings.TrimSpace(entry))
+		}
+		config.SetRoute(method, path, timeout)
+	}
+	return nil
+}
+
+func (v *routesValue) Get() any {
+	routes := make(map[string]string, len(v.Routes))
+	for route, timeout := range v.Routes {
+		routes[route] = timeout.String()
+	}
+	return routes
+}
+
+// Функция преобразует таблицу маршрутов из файла конфигурации в строку формата ROUTE_TIMEOUTS
+func routesString(routes map[string]any) (string, error) {
+	keys := make([]string, 0, len(routes))
+	for route := range routes {
+		if strings.Contains(route, ",") || strings.Contains(route, "=") {
+			return "", errors.New("route must not contain ',' or '='")
+		}
+		keys = append(keys, route)
+	}
+	sort.Strings(keys)
+
+	entries := make([]string, 0, len(keys))
+	for _, route := range keys {
+		entries = append(entries, fmt.Sprintf("%s=%v", route, routes[route]))
+	}
+	return strings.Join(entries, ","), nil
+}
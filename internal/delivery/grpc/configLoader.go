@@ -0,0 +1,13 @@
+package grpc
+
+import "time"
+
+// Структура, хранящая в себе параметры gRPC сервера
+// Значения собираются пакетом internal/config
+//
+// Port - порт gRPC сервера, пустое значение отключает сервер
+// Timeout - дедлайн unary вызовов, клиент может только сократить его своим дедлайном, 0 отключает ограничение
+type Config struct {
+	Port    string
+	Timeout time.Duration
+}
@@ -0,0 +1,56 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"infotecstechtask/internal/auth"
+	"infotecstechtask/internal/i18n"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/payment"
+	"infotecstechtask/internal/stream"
+	"infotecstechtask/internal/wallet"
+	"log/slog"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Соответствие ошибок фасада статусам gRPC, сообщение берется из каталога по коду ошибки
+var domainErrors = []struct {
+	err    error
+	status codes.Code
+	code   models.ErrorCode
+}{
+	{err: payment.ErrSenderWalletNotFound, status: codes.NotFound, code: models.CodeSenderWalletNotFound},
+	{err: payment.ErrRecipientWalletNotFound, status: codes.NotFound, code: models.CodeRecipientWalletNotFound},
+	{err: wallet.ErrWalletNotFound, status: codes.NotFound, code: models.CodeWalletNotFound},
+	{err: payment.ErrSenderAndRecipientSame, status: codes.InvalidArgument, code: models.CodeSameWallet},
+	{err: wallet.ErrWalletNotOwned, status: codes.PermissionDenied, code: models.CodeWalletNotOwned},
+	{err: auth.ErrInvalidAPIKey, status: codes.Unauthenticated, code: models.CodeInvalidAPIKey},
+	{err: stream.ErrStreamClosed, status: codes.Unavailable, code: models.CodeStreamClosed},
+}
+
+// Функция преобразует ошибку фасада в статус gRPC с сообщением на языке запроса
+// Неизвестные ошибки логируются и возвращаются клиенту как INTERNAL без подробностей
+func toStatus(ctx context.Context, err error) error {
+	for _, domainErr := range domainErrors {
+		if errors.Is(err, domainErr.err) {
+			return localizedStatus(ctx, domainErr.status, string(domainErr.code))
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return localizedStatus(ctx, codes.DeadlineExceeded, string(models.CodeRequestTimeout))
+	case errors.Is(err, context.Canceled) && ctx.Err() != nil:
+		return localizedStatus(ctx, codes.Canceled, "request.canceled")
+	default:
+		slog.ErrorContext(ctx, "grpc request failed", "error", err)
+		return localizedStatus(ctx, codes.Internal, string(models.CodeInternalError))
+	}
+}
+
+// Функция собирает статус с сообщением из каталога на языке вызова
+func localizedStatus(ctx context.Context, code codes.Code, key string, args ...any) error {
+	return status.Error(code, i18n.Translate(i18n.FromContext(ctx), key, args...))
+}
@@ -0,0 +1,212 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"infotecstechtask/internal/audit"
+	"infotecstechtask/internal/i18n"
+	"infotecstechtask/internal/middleware"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/pkg/logger"
+	"log/slog"
+	"net"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Ключи метаданных, аналогичные заголовкам http API
+const (
+	apiKeyMetadata        = "x-api-key"
+	authorizationMetadata = "authorization"
+	requestIdMetadata     = "x-request-id"
+	languageMetadata      = "accept-language"
+)
+
+type principalKey struct{}
+
+// Функция возвращает вызывающую сторону, сохраненную интерцептором аутентификации
+func principalFrom(ctx context.Context) *models.Principal {
+	principal, _ := ctx.Value(principalKey{}).(*models.Principal)
+	return principal
+}
+
+// Обертка над потоком, позволяющая интерцепторам заменить контекст
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// Интерцептор для идентификатора запроса, логирования и восстановления после паники, аналог миддлваров RequestID, Logger и Recovery
+// Идентификатор берется из метаданных x-request-id или генерируется и возвращается клиенту в заголовке ответа.
+// Язык сообщений выбирается по метаданным accept-language, как в миддлваре Localization
+func loggingUnaryInterceptor(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (response any, err error) {
+	ctx = withLanguage(withRequestID(ctx))
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIdMetadata, logger.RequestID(ctx)))
+
+	start := time.Now()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = recoverPanic(ctx, recovered)
+		}
+		logCall(ctx, info.FullMethod, start, err)
+	}()
+
+	return handler(ctx, request)
+}
+
+func loggingStreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	ctx := withLanguage(withRequestID(ss.Context()))
+	_ = ss.SetHeader(metadata.Pairs(requestIdMetadata, logger.RequestID(ctx)))
+
+	start := time.Now()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = recoverPanic(ctx, recovered)
+		}
+		logCall(ctx, info.FullMethod, start, err)
+	}()
+
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+func withRequestID(ctx context.Context) context.Context {
+	var requestId string
+	if values := metadata.ValueFromIncomingContext(ctx, requestIdMetadata); len(values) > 0 {
+		requestId = values[0]
+	}
+	if !middleware.ValidRequestID(requestId) {
+		requestId = uuid.NewString()
+	}
+
+	return logger.WithRequestID(ctx, requestId)
+}
+
+func withLanguage(ctx context.Context) context.Context {
+	return i18n.WithLanguage(ctx, i18n.Match(strings.Join(metadata.ValueFromIncomingContext(ctx, languageMetadata), ",")))
+}
+
+func recoverPanic(ctx context.Context, recovered any) error {
+	slog.ErrorContext(ctx, "panic recovered",
+		slog.String("error", fmt.Sprint(recovered)),
+		slog.String("stack", string(debug.Stack())),
+	)
+	return localizedStatus(ctx, codes.Internal, string(models.CodeInternalError))
+}
+
+// Вызовы, завершившиеся ошибкой сервера, логируются с уровнем error, ошибкой клиента - warn
+func logCall(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	level := slog.LevelInfo
+	switch code {
+	case codes.OK:
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unimplemented:
+		level = slog.LevelError
+	default:
+		level = slog.LevelWarn
+	}
+
+	slog.LogAttrs(ctx, level, "grpc request",
+		slog.String("method", method),
+		slog.String("code", code.String()),
+		slog.Duration("latency", time.Since(start)),
+		slog.String("client_ip", clientIP(ctx)),
+	)
+}
+
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
+
+// Интерцептор ограничивает время unary вызова, аналог миддлвара Timeout
+// Дедлайн клиента сохраняется, если он раньше таймаута сервера. Для потоков дедлайн не устанавливается
+func deadlineUnaryInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if timeout == 0 {
+			return handler(ctx, request)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		return handler(ctx, request)
+	}
+}
+
+// Интерцептор для аутентификации, аналог миддлваров Authentication и AuditMetadata
+// Ключ принимается из метаданных x-api-key или authorization: Bearer <key>
+func authUnaryInterceptor(authenticator middleware.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, request any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, authenticator)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, request)
+	}
+}
+
+func authStreamInterceptor(authenticator middleware.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), authenticator)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context, authenticator middleware.Authenticator) (context.Context, error) {
+	apiKey := extractAPIKey(ctx)
+	if apiKey == "" {
+		return nil, localizedStatus(ctx, codes.Unauthenticated, string(models.CodeAPIKeyRequired))
+	}
+
+	principal, err := authenticator.Authenticate(ctx, apiKey)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	ctx = context.WithValue(ctx, principalKey{}, principal)
+	ctx = audit.WithMetadata(ctx, models.AuditMetadata{
+		ActorID:   principal.ID,
+		ActorRole: principal.Role,
+		IP:        clientIP(ctx),
+		RequestID: logger.RequestID(ctx),
+	})
+
+	return ctx, nil
+}
+
+func extractAPIKey(ctx context.Context) string {
+	if values := metadata.ValueFromIncomingContext(ctx, apiKeyMetadata); len(values) > 0 && values[0] != "" {
+		return values[0]
+	}
+
+	if values := metadata.ValueFromIncomingContext(ctx, authorizationMetadata); len(values) > 0 {
+		if token, ok := strings.CutPrefix(values[0], "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,97 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	walletv1 "infotecstechtask/api/wallet/v1"
+	"infotecstechtask/internal/facade"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// gRPC сервер в виде, которым управляет lifecycle.Manager
+// Интерцепторы выполняются в порядке: идентификатор запроса и логирование, дедлайн, аутентификация
+type Server struct {
+	addr   string
+	server *grpc.Server
+
+	mu         sync.Mutex
+	onShutdown []func()
+}
+
+func NewServer(config Config, facade facade.Facade) *Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			loggingUnaryInterceptor,
+			deadlineUnaryInterceptor(config.Timeout),
+			authUnaryInterceptor(facade),
+		),
+		grpc.ChainStreamInterceptor(
+			loggingStreamInterceptor,
+			authStreamInterceptor(facade),
+		),
+	)
+	walletv1.RegisterWalletServiceServer(server, NewService(facade))
+
+	return &Server{
+		addr:   ":" + config.Port,
+		server: server,
+	}
+}
+
+func (s *Server) Addr() string {
+	return s.addr
+}
+
+// Функция регистрирует функцию, которая вызывается в начале Shutdown, аналог http.Server.RegisterOnShutdown
+// Используется для закрытия потоков событий, которые иначе не дают серверу остановиться
+func (s *Server) RegisterOnShutdown(f func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onShutdown = append(s.onShutdown, f)
+}
+
+// Функция принимает соединения до вызова Shutdown или Close, после остановки возвращает nil
+func (s *Server) ListenAndServe() error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+
+	err = s.server.Serve(listener)
+	if errors.Is(err, grpc.ErrServerStopped) {
+		return nil
+	}
+	return err
+}
+
+// Функция перестает принимать вызовы и дожидается завершения текущих
+// Если ctx завершился раньше, оставшиеся вызовы прерываются
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	for _, f := range s.onShutdown {
+		go f()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
+
+func (s *Server) Close() error {
+	s.server.Stop()
+	return nil
+}
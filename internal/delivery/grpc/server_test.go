@@ -0,0 +1,402 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	walletv1 "infotecstechtask/api/wallet/v1"
+	"infotecstechtask/internal/auth"
+	"infotecstechtask/internal/facade"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/payment"
+	"infotecstechtask/internal/wallet"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testAPIKey = "test-api-key"
+
+var (
+	testPrincipal = &models.Principal{
+		ID:   uuid.MustParse("5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e01"),
+		Role: models.RoleClient,
+	}
+	testWalletId = uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a14")
+)
+
+// Функция запускает сервер в памяти и возвращает клиент к нему
+func startServer(t *testing.T, mockFacade *facade.MockFacade, config Config) walletv1.WalletServiceClient {
+	_, client := startTestServer(t, mockFacade, config, nil)
+	return client
+}
+
+func startTestServer(t *testing.T, mockFacade *facade.MockFacade, config Config, onShutdown func()) (*Server, walletv1.WalletServiceClient) {
+	listener := bufconn.Listen(1 << 20)
+	server := NewServer(config, mockFacade)
+	if onShutdown != nil {
+		server.RegisterOnShutdown(onShutdown)
+	}
+	go func() {
+		_ = server.server.Serve(listener)
+	}()
+	t.Cleanup(func() { _ = server.Close() })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return server, walletv1.NewWalletServiceClient(conn)
+}
+
+// Функция настраивает мок фасада так, чтобы тестовый API ключ принадлежал владельцу с указанной ролью
+func mockAuthentication(mockFacade *facade.MockFacade, role models.Role) *models.Principal {
+	principal := &models.Principal{ID: testPrincipal.ID, Role: role}
+	mockFacade.On("Authenticate", mock.Anything, testAPIKey).Return(principal, nil)
+	mockFacade.On("Authenticate", mock.Anything, mock.Anything).Return(nil, auth.ErrInvalidAPIKey)
+	return principal
+}
+
+func withAPIKey(apiKey string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), apiKeyMetadata, apiKey)
+}
+
+func TestAuthentication(t *testing.T) {
+	mockFacade := new(facade.MockFacade)
+	mockAuthentication(mockFacade, models.RoleClient)
+	mockFacade.On("GetWallet", mock.Anything, mock.Anything, testWalletId).Return(&models.WalletResponse{ID: testWalletId, Balance: 100}, nil)
+	client := startServer(t, mockFacade, Config{Timeout: time.Second})
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected codes.Code
+	}{
+		{name: "missing key", ctx: context.Background(), expected: codes.Unauthenticated},
+		{name: "invalid key", ctx: withAPIKey("wrong-key"), expected: codes.Unauthenticated},
+		{name: "x-api-key", ctx: withAPIKey(testAPIKey), expected: codes.OK},
+		{name: "bearer token", ctx: metadata.AppendToOutgoingContext(context.Background(), authorizationMetadata, "Bearer "+testAPIKey), expected: codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.GetWallet(tt.ctx, &walletv1.GetWalletRequest{Id: testWalletId.String()})
+			assert.Equal(t, tt.expected, status.Code(err))
+		})
+	}
+}
+
+func TestRequestIdHeader(t *testing.T) {
+	mockFacade := new(facade.MockFacade)
+	mockAuthentication(mockFacade, models.RoleClient)
+	mockFacade.On("GetWallet", mock.Anything, mock.Anything, testWalletId).Return(&models.WalletResponse{ID: testWalletId, Balance: 100}, nil)
+	client := startServer(t, mockFacade, Config{})
+
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(withAPIKey(testAPIKey), requestIdMetadata, "client-request-1")
+	response, err := client.GetWallet(ctx, &walletv1.GetWalletRequest{Id: testWalletId.String()}, grpc.Header(&header))
+	require.NoError(t, err)
+
+	assert.Equal(t, 100.0, response.GetBalance())
+	assert.Equal(t, []string{"client-request-1"}, header.Get(requestIdMetadata))
+}
+
+func TestCreateTransaction(t *testing.T) {
+	from := uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")
+	to := uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12")
+
+	mockFacade := new(facade.MockFacade)
+	mockAuthentication(mockFacade, models.RoleClient)
+	mockFacade.On("CreateTransaction", mock.Anything, mock.Anything, &models.CreateTransactionRequest{FromAddress: from.String(), ToAddress: to.String(), Amount: 10}).
+		Return(&models.TransactionResponse{
+			ID:          uuid.MustParse("c0eebc99-9c0b-4ef8-bb6d-6bb9bd380a13"),
+			Type:        models.TypeTransfer,
+			FromAddress: &from,
+			ToAddress:   &to,
+			Amount:      10,
+			Status:      models.Completed,
+			CreatedAt:   time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC),
+		}, nil)
+	mockFacade.On("CreateTransaction", mock.Anything, mock.Anything, &models.CreateTransactionRequest{FromAddress: from.String(), ToAddress: from.String(), Amount: 10}).
+		Return(nil, payment.ErrSenderAndRecipientSame)
+	mockFacade.On("CreateTransaction", mock.Anything, mock.Anything, &models.CreateTransactionRequest{FromAddress: to.String(), ToAddress: from.String(), Amount: 10}).
+		Return(nil, wallet.ErrWalletNotOwned)
+	client := startServer(t, mockFacade, Config{Timeout: time.Second})
+	ctx := withAPIKey(testAPIKey)
+
+	transaction, err := client.CreateTransaction(ctx, &walletv1.CreateTransactionRequest{From: from.String(), To: to.String(), Amount: 10})
+	require.NoError(t, err)
+	assert.Equal(t, walletv1.TransactionType_TRANSACTION_TYPE_TRANSFER, transaction.GetType())
+	assert.Equal(t, walletv1.TransactionStatus_TRANSACTION_STATUS_COMPLETED, transaction.GetStatus())
+	assert.Equal(t, time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC), transaction.GetCreatedAt().AsTime())
+
+	_, err = client.CreateTransaction(ctx, &walletv1.CreateTransactionRequest{From: from.String(), To: from.String(), Amount: 10})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.CreateTransaction(ctx, &walletv1.CreateTransactionRequest{From: to.String(), To: from.String(), Amount: 10})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestCreateTransactionValidation(t *testing.T) {
+	mockFacade := new(facade.MockFacade)
+	mockAuthentication(mockFacade, models.RoleClient)
+	client := startServer(t, mockFacade, Config{})
+
+	_, err := client.CreateTransaction(withAPIKey(testAPIKey), &walletv1.CreateTransactionRequest{From: "not-a-uuid", Amount: -1})
+	st := status.Convert(err)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	fields := make([]string, 0, len(badRequest.GetFieldViolations()))
+	for _, violation := range badRequest.GetFieldViolations() {
+		fields = append(fields, violation.GetField())
+	}
+	assert.ElementsMatch(t, []string{"from", "to", "amount"}, fields)
+	mockFacade.AssertNotCalled(t, "CreateTransaction", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLocalizedMessages(t *testing.T) {
+	mockFacade := new(facade.MockFacade)
+	mockAuthentication(mockFacade, models.RoleClient)
+	mockFacade.On("GetAllTransactionsByOwner", mock.Anything, testPrincipal.ID).Return([]*models.TransactionResponse{{
+		ID:          uuid.New(),
+		Status:      models.Completed,
+		Message:     "Transaction completed",
+		MessageCode: models.TRANSACTION_COMPLETED,
+	}}, nil)
+	client := startServer(t, mockFacade, Config{})
+	ctx := metadata.AppendToOutgoingContext(withAPIKey(testAPIKey), languageMetadata, "ru-RU")
+
+	response, err := client.GetTransactions(ctx, &walletv1.GetTransactionsRequest{})
+	require.NoError(t, err)
+	require.Len(t, response.GetTransactions(), 1)
+	assert.Equal(t, "Транзакция выполнена", response.GetTransactions()[0].GetMessage())
+
+	_, err = client.CreateTransaction(ctx, &walletv1.CreateTransactionRequest{From: "not-a-uuid", To: uuid.NewString(), Amount: 1})
+	st := status.Convert(err)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, "Ошибка валидации", st.Message())
+	require.Len(t, st.Details(), 1)
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Len(t, badRequest.GetFieldViolations(), 1)
+	assert.Equal(t, "Поле должно быть корректным UUID", badRequest.GetFieldViolations()[0].GetDescription())
+}
+
+// Сообщения статусов ошибок переводятся по коду ошибки на язык из accept-language
+func TestLocalizedErrors(t *testing.T) {
+	mockFacade := new(facade.MockFacade)
+	mockAuthentication(mockFacade, models.RoleClient)
+	mockFacade.On("GetWallet", mock.Anything, mock.Anything, testWalletId).Return(nil, wallet.ErrWalletNotOwned)
+	client := startServer(t, mockFacade, Config{})
+
+	tests := []struct {
+		language string
+		request  *walletv1.GetWalletRequest
+		code     codes.Code
+		message  string
+	}{
+		{language: "ru", request: &walletv1.GetWalletRequest{Id: testWalletId.String()}, code: codes.PermissionDenied, message: "Кошелек не принадлежит владельцу ключа"},
+		{language: "en", request: &walletv1.GetWalletRequest{Id: testWalletId.String()}, code: codes.PermissionDenied, message: "Wallet does not belong to the caller"},
+		{language: "ru", request: &walletv1.GetWalletRequest{Id: "not-a-uuid"}, code: codes.InvalidArgument, message: "Поле id должно быть корректным UUID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language+" "+tt.message, func(t *testing.T) {
+			ctx := metadata.AppendToOutgoingContext(withAPIKey(testAPIKey), languageMetadata, tt.language)
+
+			_, err := client.GetWallet(ctx, tt.request)
+			st := status.Convert(err)
+			assert.Equal(t, tt.code, st.Code())
+			assert.Equal(t, tt.message, st.Message())
+		})
+	}
+}
+
+func TestGetTransactions(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     models.Role
+		request  *walletv1.GetTransactionsRequest
+		method   string
+		args     []any
+		expected codes.Code
+	}{
+		{name: "own wallets", role: models.RoleClient, request: &walletv1.GetTransactionsRequest{Count: 5}, method: "GetTransactionsByOwner", args: []any{testPrincipal.ID, 5}},
+		{name: "all own wallets", role: models.RoleClient, request: &walletv1.GetTransactionsRequest{}, method: "GetAllTransactionsByOwner", args: []any{testPrincipal.ID}},
+		{name: "all wallets", role: models.RoleViewer, request: &walletv1.GetTransactionsRequest{AllWallets: true, Count: 5}, method: "GetTransactions", args: []any{5}},
+		{name: "all wallets for client", role: models.RoleClient, request: &walletv1.GetTransactionsRequest{AllWallets: true}, expected: codes.PermissionDenied},
+		{name: "negative count", role: models.RoleClient, request: &walletv1.GetTransactionsRequest{Count: -1}, expected: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFacade := new(facade.MockFacade)
+			mockAuthentication(mockFacade, tt.role)
+			if tt.method != "" {
+				mockFacade.On(tt.method, append([]any{mock.Anything}, tt.args...)...).Return([]*models.TransactionResponse{}, nil)
+			}
+			client := startServer(t, mockFacade, Config{})
+
+			_, err := client.GetTransactions(withAPIKey(testAPIKey), tt.request)
+			assert.Equal(t, tt.expected, status.Code(err))
+			mockFacade.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetWalletErrors(t *testing.T) {
+	mockFacade := new(facade.MockFacade)
+	principal := mockAuthentication(mockFacade, models.RoleClient)
+	foreignWalletId := uuid.New()
+	mockFacade.On("GetWallet", mock.Anything, mock.Anything, testWalletId).Return(nil, wallet.ErrWalletNotFound)
+	mockFacade.On("GetWallet", mock.Anything, principal, foreignWalletId).Return(nil, wallet.ErrWalletNotOwned)
+	client := startServer(t, mockFacade, Config{})
+	ctx := withAPIKey(testAPIKey)
+
+	_, err := client.GetWallet(ctx, &walletv1.GetWalletRequest{Id: testWalletId.String()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetWallet(ctx, &walletv1.GetWalletRequest{Id: foreignWalletId.String()})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = client.GetWallet(ctx, &walletv1.GetWalletRequest{Id: "not-a-uuid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetWalletDeadline(t *testing.T) {
+	mockFacade := new(facade.MockFacade)
+	mockAuthentication(mockFacade, models.RoleClient)
+	mockFacade.On("GetWallet", mock.Anything, mock.Anything, testWalletId).
+		Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		}).
+		Return(nil, context.DeadlineExceeded)
+	client := startServer(t, mockFacade, Config{Timeout: 50 * time.Millisecond})
+
+	_, err := client.GetWallet(withAPIKey(testAPIKey), &walletv1.GetWalletRequest{Id: testWalletId.String()})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+}
+
+type testSubscription struct {
+	events chan *models.Event
+	closed atomic.Bool
+}
+
+func (s *testSubscription) Events() <-chan *models.Event {
+	return s.events
+}
+
+func (s *testSubscription) Close() {
+	s.closed.Store(true)
+}
+
+func TestStreamTransactionEvents(t *testing.T) {
+	subscription := &testSubscription{events: make(chan *models.Event, 1)}
+	subscription.events <- &models.Event{
+		ID:       42,
+		WalletID: testWalletId,
+		Type:     models.EventWalletBalanceChanged,
+		Payload:  json.RawMessage(`{"balance":115}`),
+	}
+	close(subscription.events)
+
+	mockFacade := new(facade.MockFacade)
+	principal := mockAuthentication(mockFacade, models.RoleClient)
+	mockFacade.On("SubscribeWalletEvents", mock.Anything, principal, testWalletId, int64(41)).Return(subscription, nil)
+	client := startServer(t, mockFacade, Config{})
+
+	stream, err := client.StreamTransactionEvents(withAPIKey(testAPIKey), &walletv1.StreamTransactionEventsRequest{
+		WalletIds:   []string{testWalletId.String(), testWalletId.String()},
+		LastEventId: 41,
+	})
+	require.NoError(t, err)
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), event.GetId())
+	assert.Equal(t, string(models.EventWalletBalanceChanged), event.GetType())
+	assert.JSONEq(t, `{"balance":115}`, string(event.GetPayload()))
+
+	// Закрытая подписка завершает поток, клиент должен переподключиться
+	_, err = stream.Recv()
+	assert.NotEqual(t, io.EOF, err)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Eventually(t, subscription.closed.Load, time.Second, 10*time.Millisecond)
+	mockFacade.AssertNumberOfCalls(t, "SubscribeWalletEvents", 1)
+}
+
+func TestStreamTransactionEventsErrors(t *testing.T) {
+	mockFacade := new(facade.MockFacade)
+	principal := mockAuthentication(mockFacade, models.RoleClient)
+	mockFacade.On("SubscribeWalletEvents", mock.Anything, principal, testWalletId, int64(0)).Return(nil, wallet.ErrWalletNotOwned)
+	client := startServer(t, mockFacade, Config{})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		walletIds []string
+		expected  codes.Code
+	}{
+		{name: "missing key", ctx: context.Background(), walletIds: []string{testWalletId.String()}, expected: codes.Unauthenticated},
+		{name: "no wallets", ctx: withAPIKey(testAPIKey), expected: codes.InvalidArgument},
+		{name: "invalid wallet id", ctx: withAPIKey(testAPIKey), walletIds: []string{"not-a-uuid"}, expected: codes.InvalidArgument},
+		{name: "foreign wallet", ctx: withAPIKey(testAPIKey), walletIds: []string{testWalletId.String()}, expected: codes.PermissionDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.StreamTransactionEvents(tt.ctx, &walletv1.StreamTransactionEventsRequest{WalletIds: tt.walletIds})
+			require.NoError(t, err)
+
+			_, err = stream.Recv()
+			assert.Equal(t, tt.expected, status.Code(err))
+		})
+	}
+}
+
+func TestShutdownClosesStreams(t *testing.T) {
+	subscription := &testSubscription{events: make(chan *models.Event)}
+
+	mockFacade := new(facade.MockFacade)
+	principal := mockAuthentication(mockFacade, models.RoleClient)
+	subscribed := make(chan struct{})
+	mockFacade.On("SubscribeWalletEvents", mock.Anything, principal, testWalletId, int64(0)).
+		Run(func(mock.Arguments) { close(subscribed) }).
+		Return(subscription, nil)
+
+	server, client := startTestServer(t, mockFacade, Config{}, func() { close(subscription.events) })
+
+	stream, err := client.StreamTransactionEvents(withAPIKey(testAPIKey), &walletv1.StreamTransactionEventsRequest{
+		WalletIds: []string{testWalletId.String()},
+	})
+	require.NoError(t, err)
+	<-subscribed
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, server.Shutdown(ctx))
+
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
@@ -0,0 +1,254 @@
+package grpc
+
+import (
+	"context"
+	walletv1 "infotecstechtask/api/wallet/v1"
+	"infotecstechtask/internal/facade"
+	"infotecstechtask/internal/i18n"
+	"infotecstechtask/internal/middleware"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/stream"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Максимальное количество кошельков в одном потоке событий, как и в WebSocket-ленте
+const maxStreamWallets = 100
+
+// Реализация gRPC сервиса WalletService
+// Как и http хендлер, вызывает функции фасада и преобразует результат в ответ клиенту
+type Service struct {
+	walletv1.UnimplementedWalletServiceServer
+
+	facade   facade.Facade
+	validate *validator.Validate
+}
+
+func NewService(facade facade.Facade) *Service {
+	validate := validator.New()
+	// В ошибках валидации поля называются так же, как в protobuf сообщениях
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		return name
+	})
+
+	return &Service{
+		facade:   facade,
+		validate: validate,
+	}
+}
+
+// Создает перевод с кошелька вызывающей стороны, ролям operator и выше доступны все кошельки
+func (s *Service) CreateTransaction(ctx context.Context, request *walletv1.CreateTransactionRequest) (*walletv1.Transaction, error) {
+	createTransactionRequest := &models.CreateTransactionRequest{
+		FromAddress: request.GetFrom(),
+		ToAddress:   request.GetTo(),
+		Amount:      request.GetAmount(),
+	}
+	if err := s.validate.Struct(createTransactionRequest); err != nil {
+		return nil, validationStatus(ctx, err)
+	}
+
+	transaction, err := s.facade.CreateTransaction(ctx, principalFrom(ctx), createTransactionRequest)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return toTransaction(ctx, transaction), nil
+}
+
+// Возвращает транзакции кошельков вызывающей стороны или, с all_wallets, всех кошельков
+func (s *Service) GetTransactions(ctx context.Context, request *walletv1.GetTransactionsRequest) (*walletv1.GetTransactionsResponse, error) {
+	principal := principalFrom(ctx)
+	count := int(request.GetCount())
+	if count < 0 {
+		return nil, localizedStatus(ctx, codes.InvalidArgument, "params.negative_count")
+	}
+
+	var transactions []*models.TransactionResponse
+	var err error
+	switch {
+	case request.GetAllWallets() && !principal.Role.Allows(models.RoleViewer):
+		return nil, localizedStatus(ctx, codes.PermissionDenied, string(models.CodeInsufficientRole))
+	case request.GetAllWallets() && count > 0:
+		transactions, err = s.facade.GetTransactions(ctx, count)
+	case request.GetAllWallets():
+		transactions, err = s.facade.GetAllTransactions(ctx)
+	case count > 0:
+		transactions, err = s.facade.GetTransactionsByOwner(ctx, principal.ID, count)
+	default:
+		transactions, err = s.facade.GetAllTransactionsByOwner(ctx, principal.ID)
+	}
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	response := &walletv1.GetTransactionsResponse{
+		Transactions: make([]*walletv1.Transaction, 0, len(transactions)),
+	}
+	for _, transaction := range transactions {
+		response.Transactions = append(response.Transactions, toTransaction(ctx, transaction))
+	}
+
+	return response, nil
+}
+
+// Возвращает баланс кошелька, владельцу доступны свои кошельки, ролям viewer и выше - все
+func (s *Service) GetWallet(ctx context.Context, request *walletv1.GetWalletRequest) (*walletv1.Wallet, error) {
+	walletId, err := uuid.Parse(request.GetId())
+	if err != nil {
+		return nil, localizedStatus(ctx, codes.InvalidArgument, "params.invalid_id")
+	}
+
+	walletToReturn, err := s.facade.GetWallet(ctx, principalFrom(ctx), walletId)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return &walletv1.Wallet{
+		Id:      walletToReturn.ID.String(),
+		Balance: walletToReturn.Balance,
+	}, nil
+}
+
+// Отдает события кошельков в одном потоке
+//
+// Права на кошельки те же, что и для SSE. Если одна из подписок прервана (клиент не успевает читать события
+// или приложение останавливается), поток завершается со статусом UNAVAILABLE
+// и клиент должен переподключиться с last_event_id последнего полученного события
+func (s *Service) StreamTransactionEvents(request *walletv1.StreamTransactionEventsRequest, server walletv1.WalletService_StreamTransactionEventsServer) error {
+	ctx := server.Context()
+	principal := principalFrom(ctx)
+
+	walletIds, err := parseWalletIds(ctx, request.GetWalletIds())
+	if err != nil {
+		return err
+	}
+
+	subscriptions := make([]stream.Subscription, 0, len(walletIds))
+	defer func() {
+		for _, subscription := range subscriptions {
+			subscription.Close()
+		}
+	}()
+	for _, walletId := range walletIds {
+		subscription, err := s.facade.SubscribeWalletEvents(ctx, principal, walletId, request.GetLastEventId())
+		if err != nil {
+			return toStatus(ctx, err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	events := make(chan *models.Event)
+	interrupted := make(chan struct{})
+	var interruptOnce sync.Once
+	for _, subscription := range subscriptions {
+		go func() {
+			for event := range subscription.Events() {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+			interruptOnce.Do(func() { close(interrupted) })
+		}()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return toStatus(ctx, ctx.Err())
+		case <-interrupted:
+			return localizedStatus(ctx, codes.Unavailable, "feed.resubscribe")
+		case event := <-events:
+			if err := server.Send(toEvent(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func parseWalletIds(ctx context.Context, rawIds []string) ([]uuid.UUID, error) {
+	if len(rawIds) == 0 || len(rawIds) > maxStreamWallets {
+		return nil, localizedStatus(ctx, codes.InvalidArgument, "feed.wallet_ids_count", maxStreamWallets)
+	}
+
+	seen := make(map[uuid.UUID]bool, len(rawIds))
+	walletIds := make([]uuid.UUID, 0, len(rawIds))
+	for _, rawId := range rawIds {
+		walletId, err := uuid.Parse(rawId)
+		if err != nil {
+			return nil, localizedStatus(ctx, codes.InvalidArgument, "feed.invalid_wallet_id", rawId)
+		}
+		if !seen[walletId] {
+			seen[walletId] = true
+			walletIds = append(walletIds, walletId)
+		}
+	}
+
+	return walletIds, nil
+}
+
+// Функция собирает статус INVALID_ARGUMENT, ошибки полей передаются в деталях BadRequest на языке вызова
+func validationStatus(ctx context.Context, err error) error {
+	lang := i18n.FromContext(ctx)
+	st := status.New(codes.InvalidArgument, i18n.Translate(lang, string(models.CodeValidationFailed)))
+
+	badRequest := &errdetails.BadRequest{}
+	for _, fieldErr := range middleware.FormatValidationErrors(err, lang) {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       fieldErr.Field,
+			Description: fieldErr.Message,
+		})
+	}
+
+	if detailed, err := st.WithDetails(badRequest); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+var transactionTypes = map[models.TransactionType]walletv1.TransactionType{
+	models.TypeTransfer:   walletv1.TransactionType_TRANSACTION_TYPE_TRANSFER,
+	models.TypeAdjustment: walletv1.TransactionType_TRANSACTION_TYPE_ADJUSTMENT,
+}
+
+var transactionStatuses = map[models.Status]walletv1.TransactionStatus{
+	models.Pending:   walletv1.TransactionStatus_TRANSACTION_STATUS_PENDING,
+	models.Completed: walletv1.TransactionStatus_TRANSACTION_STATUS_COMPLETED,
+	models.Failed:    walletv1.TransactionStatus_TRANSACTION_STATUS_FAILED,
+}
+
+// Сообщение транзакции переводится на язык вызова
+func toTransaction(ctx context.Context, transaction *models.TransactionResponse) *walletv1.Transaction {
+	transaction.Localize(i18n.FromContext(ctx))
+	return &walletv1.Transaction{
+		Id:        transaction.ID.String(),
+		Type:      transactionTypes[transaction.Type],
+		From:      models.AddressString(transaction.FromAddress),
+		To:        models.AddressString(transaction.ToAddress),
+		Amount:    transaction.Amount,
+		Status:    transactionStatuses[transaction.Status],
+		Message:   transaction.Message,
+		CreatedAt: timestamppb.New(transaction.CreatedAt),
+	}
+}
+
+func toEvent(event *models.Event) *walletv1.Event {
+	return &walletv1.Event{
+		Id:        event.ID,
+		WalletId:  event.WalletID.String(),
+		Type:      string(event.Type),
+		Payload:   event.Payload,
+		CreatedAt: timestamppb.New(event.CreatedAt),
+	}
+}
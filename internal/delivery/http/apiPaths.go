@@ -2,14 +2,42 @@ package http
 
 // Список эндпоинтов
 const (
-	BASED_PATH = "/api"
-	SWAGGER    = "/swagger/*any"
+	BASED_PATH  = "/api"
+	API_V1_PATH = "/api/v1"
+	API_V2_PATH = "/api/v2"
+	ADMIN_PATH  = "/admin"
+	SWAGGER     = "/swagger/*any"
+	OPENAPI     = "/openapi.json"
 
 	SEND               = "/send"
 	TRANSACTIONS       = "/transactions"
+	TRANSACTION_FEED   = "/transactions/feed"
 	GET_WALLET_BALANCE = "/wallet/:walletId/balance"
+	BALANCE_HISTORY    = "/wallet/:walletId/balance-history"
+	WALLET_HISTORY     = "/wallet/:walletId/transactions"
+	WALLET_EVENTS      = "/wallet/:walletId/events"
+	WALLET_STATEMENTS  = "/wallet/:walletId/statements"
+	ADJUSTMENTS        = "/adjustments"
+	APPROVE_ADJUSTMENT = "/adjustments/:adjustmentId/approve"
+	REJECT_ADJUSTMENT  = "/adjustments/:adjustmentId/reject"
+	WEBHOOKS           = "/webhooks"
+	WEBHOOK_DELIVERIES = "/webhooks/:webhookId/deliveries"
+	REDELIVER_WEBHOOK  = "/webhooks/:webhookId/deliveries/:deliveryId/redeliver"
 
+	// Пути API без версии, устаревший псевдоним v1
 	FULL_SEND               = "/api/send"
 	FULL_TRANSACTIONS       = "/api/transactions"
+	FULL_TRANSACTION_FEED   = "/api/transactions/feed"
 	FULL_GET_WALLET_BALANCE = "/api/wallet/:walletId/balance"
+	FULL_BALANCE_HISTORY    = "/api/wallet/:walletId/balance-history"
+	FULL_WALLET_HISTORY     = "/api/wallet/:walletId/transactions"
+	FULL_WALLET_EVENTS      = "/api/wallet/:walletId/events"
+	FULL_WALLET_STATEMENTS  = "/api/wallet/:walletId/statements"
+	FULL_ADMIN_TRANSACTIONS = "/api/admin/transactions"
+	FULL_ADJUSTMENTS        = "/api/admin/adjustments"
+	FULL_APPROVE_ADJUSTMENT = "/api/admin/adjustments/:adjustmentId/approve"
+	FULL_REJECT_ADJUSTMENT  = "/api/admin/adjustments/:adjustmentId/reject"
+	FULL_WEBHOOKS           = "/api/webhooks"
+	FULL_WEBHOOK_DELIVERIES = "/api/webhooks/:webhookId/deliveries"
+	FULL_REDELIVER_WEBHOOK  = "/api/webhooks/:webhookId/deliveries/:deliveryId/redeliver"
 )
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"infotecstechtask/internal/adjustment"
+	"infotecstechtask/internal/balance"
+	"infotecstechtask/internal/middleware"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/payment"
+	"infotecstechtask/internal/statement"
+	"infotecstechtask/internal/stream"
+	"infotecstechtask/internal/wallet"
+	"infotecstechtask/internal/webhook"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Соответствие ошибок фасада статусам и кодам ответа
+// detail берется из каталога сообщений по коду, английский текст совпадает с текстом ошибки фасада
+var domainErrors = []struct {
+	err    error
+	status int
+	code   models.ErrorCode
+}{
+	{err: payment.ErrSenderWalletNotFound, status: http.StatusBadRequest, code: models.CodeSenderWalletNotFound},
+	{err: payment.ErrRecipientWalletNotFound, status: http.StatusBadRequest, code: models.CodeRecipientWalletNotFound},
+	{err: payment.ErrSenderAndRecipientSame, status: http.StatusBadRequest, code: models.CodeSameWallet},
+	{err: wallet.ErrWalletNotFound, status: http.StatusNotFound, code: models.CodeWalletNotFound},
+	{err: wallet.ErrWalletNotOwned, status: http.StatusForbidden, code: models.CodeWalletNotOwned},
+	{err: adjustment.ErrAdjustmentNotFound, status: http.StatusNotFound, code: models.CodeAdjustmentNotFound},
+	{err: adjustment.ErrSelfReview, status: http.StatusForbidden, code: models.CodeAdjustmentSelfReview},
+	{err: adjustment.ErrAdjustmentAlreadyReviewed, status: http.StatusConflict, code: models.CodeAdjustmentAlreadyReviewed},
+	{err: adjustment.ErrInsufficientBalance, status: http.StatusConflict, code: models.CodeInsufficientFunds},
+	{err: adjustment.ErrAmountTooSmall, status: http.StatusBadRequest, code: models.CodeAdjustmentAmountTooSmall},
+	{err: webhook.ErrWebhookNotFound, status: http.StatusNotFound, code: models.CodeWebhookNotFound},
+	{err: webhook.ErrDeliveryNotFound, status: http.StatusNotFound, code: models.CodeDeliveryNotFound},
+	{err: webhook.ErrURLNotAllowed, status: http.StatusBadRequest, code: models.CodeWebhookURLNotAllowed},
+	{err: statement.ErrPeriodTooLong, status: http.StatusBadRequest, code: models.CodeStatementPeriodTooLong},
+	{err: balance.ErrTooManyPoints, status: http.StatusBadRequest, code: models.CodeBalanceHistoryTooLong},
+	{err: stream.ErrStreamClosed, status: http.StatusServiceUnavailable, code: models.CodeStreamClosed},
+}
+
+// Функция возвращает код ошибки фасада по таблице domainErrors
+func domainErrorCode(err error) (models.ErrorCode, bool) {
+	for _, domainErr := range domainErrors {
+		if errors.Is(err, domainErr.err) {
+			return domainErr.code, true
+		}
+	}
+
+	return "", false
+}
+
+// Функция собирает ответ для ошибки фасада
+// Известные ошибки отдаются по таблице domainErrors, истекший таймаут - как 504,
+// отключение клиента логируется со статусом 499, остальные ошибки логируются и возвращаются как 500 без подробностей
+func abortWithError(c *gin.Context, err error) {
+	for _, domainErr := range domainErrors {
+		if errors.Is(err, domainErr.err) {
+			middleware.AbortWithError(c, domainErr.status, domainErr.code)
+			return
+		}
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		middleware.AbortWithError(c, http.StatusGatewayTimeout, models.CodeRequestTimeout)
+	case errors.Is(err, context.Canceled) && c.Request.Context().Err() != nil:
+		c.AbortWithStatus(middleware.StatusClientClosedRequest)
+	default:
+		slog.ErrorContext(c.Request.Context(), "http request failed", "error", err)
+		middleware.Abort(c, http.StatusInternalServerError, middleware.InternalError, nil)
+	}
+}
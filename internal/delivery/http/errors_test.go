@@ -0,0 +1,19 @@
+package http
+
+import (
+	"infotecstechtask/internal/i18n"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Ответы v1 на английском должны совпадать с текстами ошибок фасада
+func TestDomainErrorMessages(t *testing.T) {
+	for _, domainErr := range domainErrors {
+		assert.Equal(t, domainErr.err.Error(), i18n.Translate(i18n.English, string(domainErr.code)))
+		for _, lang := range []i18n.Language{i18n.English, i18n.Russian} {
+			_, ok := i18n.Lookup(lang, string(domainErr.code))
+			assert.True(t, ok, "%s: message %s is missing", lang, domainErr.code)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"infotecstechtask/internal/i18n"
+	"infotecstechtask/internal/middleware"
+	"infotecstechtask/internal/models"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Форматы ответа с историей транзакций
+const (
+	formatJSON   = "json"
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+
+	mimeCSV    = "text/csv"
+	mimeNDJSON = "application/x-ndjson"
+)
+
+// Количество строк, после которого накопленная часть выгрузки отправляется клиенту
+const exportFlushRows = 1000
+
+// Колонки CSV выгрузки, порядок не меняется между релизами
+var csvColumns = []string{"id", "type", "from", "to", "amount", "status", "message_code", "message", "created_at"}
+
+// Функция выбирает формат ответа: параметр format важнее заголовка Accept, по умолчанию отдается JSON
+func exportFormat(c *gin.Context, format string) string {
+	if format != "" {
+		return format
+	}
+
+	switch c.NegotiateFormat(gin.MIMEJSON, mimeCSV, mimeNDJSON) {
+	case mimeCSV:
+		return formatCSV
+	case mimeNDJSON:
+		return formatNDJSON
+	default:
+		return formatJSON
+	}
+}
+
+// Отдает транзакции по фильтру в выбранном формате
+//
+// JSON собирается целиком, CSV и NDJSON пишутся в ответ по мере чтения из БД и не ограничены таймаутом маршрута.
+// Заголовки отправляются вместе с первой транзакцией, поэтому ошибки доступа к кошельку возвращаются обычным ответом.
+// Если выгрузка прервалась после начала ответа, соединение разрывается, чтобы клиент не принял неполный файл за целый
+func (h *Handler) exportTransactions(c *gin.Context, format string, filter models.TransactionFilter) {
+	if format == formatJSON {
+		transactions := make([]*models.TransactionResponse, 0)
+		err := h.facade.StreamTransactions(c.Request.Context(), filter, func(transaction *models.TransactionResponse) error {
+			transactions = append(transactions, transaction)
+			return nil
+		})
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		localizeTransactions(c, transactions...)
+		respond(c, http.StatusOK, transactions)
+		return
+	}
+
+	// WriteTimeout сервера ограничивает время всего ответа, для выгрузки он снимается
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	ctx := middleware.WithoutTimeout(c)
+	export := &transactionExport{c: c, format: format, lang: i18n.FromContext(ctx)}
+	err := h.facade.StreamTransactions(ctx, filter, export.write)
+	if err == nil {
+		err = export.finish()
+	}
+	if err == nil {
+		return
+	}
+
+	if export.writer == nil {
+		abortWithError(c, err)
+		return
+	}
+	if ctx.Err() == nil {
+		slog.ErrorContext(ctx, "transaction export interrupted", "error", err, "rows", export.rows)
+	}
+	panic(http.ErrAbortHandler)
+}
+
+// Запись транзакций в потоковом формате
+type transactionWriter interface {
+	Write(transaction *models.TransactionResponse) error
+	Flush() error
+}
+
+// Выгрузка, которая начинает ответ при записи первой транзакции
+type transactionExport struct {
+	c      *gin.Context
+	format string
+	lang   i18n.Language
+	writer transactionWriter
+	rows   int
+}
+
+func (e *transactionExport) write(transaction *models.TransactionResponse) error {
+	if e.writer == nil {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+
+	transaction.Localize(e.lang)
+	if err := e.writer.Write(transaction); err != nil {
+		return err
+	}
+
+	e.rows++
+	if e.rows%exportFlushRows == 0 {
+		return e.flush()
+	}
+	return nil
+}
+
+// Пустая выгрузка тоже отдается со статусом 200, CSV - с одной строкой заголовков
+func (e *transactionExport) finish() error {
+	if e.writer == nil {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+
+	return e.flush()
+}
+
+func (e *transactionExport) start() error {
+	e.c.Header("Content-Disposition", `attachment; filename="transactions.`+e.format+`"`)
+
+	switch e.format {
+	case formatCSV:
+		e.c.Header("Content-Type", mimeCSV+"; charset=utf-8")
+		e.c.Status(http.StatusOK)
+		writer := &csvTransactionWriter{writer: csv.NewWriter(e.c.Writer)}
+		e.writer = writer
+		return writer.writer.Write(csvColumns)
+	default:
+		e.c.Header("Content-Type", mimeNDJSON)
+		e.c.Status(http.StatusOK)
+		e.writer = &ndjsonTransactionWriter{encoder: json.NewEncoder(e.c.Writer)}
+		return nil
+	}
+}
+
+func (e *transactionExport) flush() error {
+	if err := e.writer.Flush(); err != nil {
+		return err
+	}
+
+	e.c.Writer.Flush()
+	return nil
+}
+
+// Строки CSV пишутся в порядке csvColumns, сумма - в рублях с двумя знаками после точки, время - в UTC
+type csvTransactionWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvTransactionWriter) Write(transaction *models.TransactionResponse) error {
+	return w.writer.Write([]string{
+		transaction.ID.String(),
+		string(transaction.Type),
+		models.AddressString(transaction.FromAddress),
+		models.AddressString(transaction.ToAddress),
+		strconv.FormatFloat(transaction.Amount, 'f', 2, 64),
+		string(transaction.Status),
+		transaction.MessageCode,
+		transaction.Message,
+		transaction.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+}
+
+func (w *csvTransactionWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// Каждая транзакция пишется отдельной строкой в том же виде, что и в JSON ответе
+type ndjsonTransactionWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonTransactionWriter) Write(transaction *models.TransactionResponse) error {
+	return w.encoder.Encode(transaction)
+}
+
+func (w *ndjsonTransactionWriter) Flush() error {
+	return nil
+}
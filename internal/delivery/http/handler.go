@@ -4,18 +4,27 @@ import (
 	"context"
 	"errors"
 	"infotecstechtask/internal/facade"
+	"infotecstechtask/internal/i18n"
+	"infotecstechtask/internal/middleware"
 	"infotecstechtask/internal/models"
-	"infotecstechtask/internal/payment"
+	"infotecstechtask/internal/tracing"
 	"infotecstechtask/internal/wallet"
+	"io"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// Интервал, с которым в поток событий отправляется комментарий, чтобы прокси не закрывали неактивное соединение
+const sseHeartbeatInterval = 15 * time.Second
+
 // Хендлер вызывает функции фасада и в зависимости от возвращаемых значений собирает ответ для клиента
 // Запрос сюда попадает после прохожождения всех миддлваров
+// Фасад вызывается с контекстом запроса: работа прерывается при отключении клиента и по таймауту маршрута (миддлвар Timeout)
 type Handler struct {
 	facade facade.Facade
 }
@@ -26,91 +35,326 @@ func NewHandler(facade facade.Facade) *Handler {
 	}
 }
 
+// Функция отправляет успешный ответ, в API v2 данные оборачиваются в конверт {"data": ...}
+func respond(c *gin.Context, status int, body any) {
+	if middleware.APIVersion(c) == middleware.APIVersion2 {
+		c.JSON(status, models.DataResponse{Data: body})
+		return
+	}
+
+	c.JSON(status, body)
+}
+
+// Функция переводит сообщения транзакций на язык запроса
+func localizeTransactions(c *gin.Context, transactions ...*models.TransactionResponse) {
+	lang := i18n.FromContext(c.Request.Context())
+	for _, transaction := range transactions {
+		transaction.Localize(lang)
+	}
+}
+
+// Создает перевод с кошелька вызывающей стороны, ролям operator и выше доступны все кошельки
 func (h *Handler) CreateTransaction(c *gin.Context) {
 	createTransactionRequest := c.MustGet("validatedBody").(*models.CreateTransactionRequest)
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	principal := c.MustGet("principal").(*models.Principal)
+	ctx := c.Request.Context()
+	ctx, span := tracing.Start(ctx, "Handler.CreateTransaction")
+	defer span.End()
 
-	transaction, err := h.facade.CreateTransaction(ctx, createTransactionRequest)
+	transaction, err := h.facade.CreateTransaction(ctx, principal, createTransactionRequest)
 	if err != nil {
-		if errors.Is(err, payment.ErrSenderWalletNotFound) || errors.Is(err, payment.ErrRecipientWalletNotFound) || errors.Is(err, payment.ErrSenderAndRecipientSame) {
-			c.AbortWithStatusJSON(
-				http.StatusBadRequest,
-				models.Error{
-					Error: err.Error(),
-				},
-			)
-			return
-		}
-		if errors.Is(err, context.DeadlineExceeded) {
-			c.AbortWithStatus(http.StatusServiceUnavailable)
-			return
-		}
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, transaction)
+	localizeTransactions(c, transaction)
+	respond(c, http.StatusOK, transaction)
 }
 
+// Возвращает транзакции кошельков владельца ключа
+// С фильтром по дате или в форматах CSV и NDJSON транзакции читаются потоком (exportTransactions)
 func (h *Handler) GetTransactions(c *gin.Context) {
+	params := c.MustGet("validatedParams").(*models.GetTransactionHistoryRequest)
+	principal := c.MustGet("principal").(*models.Principal)
+
+	format := exportFormat(c, params.Format)
+	if format != formatJSON || !params.From.IsZero() || !params.To.IsZero() {
+		filter := params.Filter()
+		filter.OwnerID = &principal.ID
+		h.exportTransactions(c, format, filter)
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	var transactions []*models.TransactionResponse
+	var err error
+	if params.Count != nil {
+		transactions, err = h.facade.GetTransactionsByOwner(ctx, principal.ID, *params.Count)
+	} else {
+		transactions, err = h.facade.GetAllTransactionsByOwner(ctx, principal.ID)
+	}
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	localizeTransactions(c, transactions...)
+	respond(c, http.StatusOK, transactions)
+}
+
+// Возвращает историю транзакций кошелька
+// Владельцу доступны свои кошельки, ролям viewer и выше - все
+func (h *Handler) GetWalletTransactions(c *gin.Context) {
+	params := c.MustGet("validatedParams").(*models.GetWalletTransactionsRequest)
+	principal := c.MustGet("principal").(*models.Principal)
+	walletId := uuid.MustParse(params.ID)
+
+	filter := params.Filter()
+	filter.WalletID = &walletId
+	if !principal.Role.Allows(models.RoleViewer) {
+		filter.OwnerID = &principal.ID
+	}
+
+	h.exportTransactions(c, exportFormat(c, params.Format), filter)
+}
+
+// Возвращает общую ленту транзакций всех кошельков
+// Доступен только через административное API
+func (h *Handler) GetAdminTransactions(c *gin.Context) {
 	params := c.MustGet("validatedParams").(*models.GetTransactionWithCountRequest)
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	ctx := c.Request.Context()
 
+	var transactions []*models.TransactionResponse
+	var err error
 	if params.Count != nil {
-		transactions, err := h.facade.GetTransactions(ctx, *params.Count)
-		if err != nil {
-			if errors.Is(err, wallet.ErrWalletNotFound) {
-				c.AbortWithStatus(http.StatusNotFound)
-				return
-			}
-			if errors.Is(err, context.DeadlineExceeded) {
-				c.AbortWithStatus(http.StatusServiceUnavailable)
-				return
-			}
-			c.AbortWithStatus(http.StatusInternalServerError)
-			return
-		}
+		transactions, err = h.facade.GetTransactions(ctx, *params.Count)
+	} else {
+		transactions, err = h.facade.GetAllTransactions(ctx)
+	}
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	localizeTransactions(c, transactions...)
+	respond(c, http.StatusOK, transactions)
+}
+
+// Возвращает текущий баланс кошелька, с параметром at - баланс на этот момент
+// Владельцу доступны свои кошельки, ролям viewer и выше - все
+func (h *Handler) GetWallet(c *gin.Context) {
+	params := c.MustGet("validatedParams").(*models.GetWalletBalanceRequest)
+	principal := c.MustGet("principal").(*models.Principal)
+	walletId := uuid.MustParse(params.ID)
+	ctx := c.Request.Context()
 
-		c.JSON(http.StatusOK, transactions)
+	var walletToReturn *models.WalletResponse
+	var err error
+	if params.At.IsZero() {
+		walletToReturn, err = h.facade.GetWallet(ctx, principal, walletId)
 	} else {
-		transactions, err := h.facade.GetAllTransactions(ctx)
-		if err != nil {
-			if errors.Is(err, wallet.ErrWalletNotFound) {
-				c.AbortWithStatus(http.StatusNotFound)
-				return
-			}
-			if errors.Is(err, context.DeadlineExceeded) {
-				c.AbortWithStatus(http.StatusServiceUnavailable)
-				return
-			}
-			c.AbortWithStatus(http.StatusInternalServerError)
-			return
-		}
+		walletToReturn, err = h.facade.GetWalletBalanceAt(ctx, principal, walletId, params.At)
+	}
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
 
-		c.JSON(http.StatusOK, transactions)
+	// В API v1 поля кошелька сериализуются без json тегов (ID, Balance), в v2 - в snake_case
+	if middleware.APIVersion(c) == middleware.APIVersion2 {
+		respond(c, http.StatusOK, models.ToWalletResponseV2(walletToReturn))
+		return
 	}
+	c.JSON(http.StatusOK, walletToReturn)
 }
 
-func (h *Handler) GetWallet(c *gin.Context) {
-	walletId := uuid.MustParse(c.MustGet("validatedParams").(*models.GetWalletBalanceRequest).ID)
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+// Возвращает ряд баланса кошелька за период
+// Владельцу доступны свои кошельки, ролям viewer и выше - все
+func (h *Handler) GetBalanceHistory(c *gin.Context) {
+	params := c.MustGet("validatedParams").(*models.GetBalanceHistoryRequest)
+	principal := c.MustGet("principal").(*models.Principal)
+	walletId := uuid.MustParse(params.ID)
 
-	walletToReturn, err := h.facade.GetWallet(ctx, walletId)
+	history, err := h.facade.GetBalanceHistory(c.Request.Context(), principal, walletId, params.From, params.To, params.Interval)
 	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, history)
+}
+
+// Создает заявку на корректировку баланса от имени вызывающего оператора
+func (h *Handler) CreateAdjustment(c *gin.Context) {
+	createAdjustmentRequest := c.MustGet("validatedBody").(*models.CreateAdjustmentRequest)
+	principal := c.MustGet("principal").(*models.Principal)
+
+	ctx := c.Request.Context()
+
+	createdAdjustment, err := h.facade.CreateAdjustment(ctx, principal.ID, createAdjustmentRequest)
+	if err != nil {
+		// Кошелек передается в теле запроса, поэтому его отсутствие - ошибка запроса, а не отсутствующий ресурс
 		if errors.Is(err, wallet.ErrWalletNotFound) {
-			c.AbortWithStatus(http.StatusNotFound)
-			return
-		}
-		if errors.Is(err, context.DeadlineExceeded) {
-			c.AbortWithStatus(http.StatusServiceUnavailable)
+			middleware.AbortWithError(c, http.StatusBadRequest, models.CodeWalletNotFound)
 			return
 		}
-		c.AbortWithStatus(http.StatusInternalServerError)
+		abortWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, walletToReturn)
+	respond(c, http.StatusCreated, createdAdjustment)
+}
+
+func (h *Handler) GetAdjustments(c *gin.Context) {
+	params := c.MustGet("validatedParams").(*models.GetAdjustmentsRequest)
+
+	ctx := c.Request.Context()
+
+	adjustments, err := h.facade.GetAdjustments(ctx, params.Status)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, adjustments)
+}
+
+func (h *Handler) ApproveAdjustment(c *gin.Context) {
+	h.reviewAdjustment(c, h.facade.ApproveAdjustment)
+}
+
+func (h *Handler) RejectAdjustment(c *gin.Context) {
+	h.reviewAdjustment(c, h.facade.RejectAdjustment)
+}
+
+// Общая часть одобрения и отклонения заявки
+// Рассматривающим заявку оператором считается вызывающая сторона
+func (h *Handler) reviewAdjustment(c *gin.Context, review func(ctx context.Context, adjustmentId uuid.UUID, reviewerId uuid.UUID) (*models.AdjustmentResponse, error)) {
+	adjustmentId := uuid.MustParse(c.MustGet("validatedParams").(*models.ReviewAdjustmentRequest).ID)
+	principal := c.MustGet("principal").(*models.Principal)
+
+	ctx := c.Request.Context()
+
+	reviewedAdjustment, err := review(ctx, adjustmentId, principal.ID)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, reviewedAdjustment)
+}
+
+// Создает подписку на события кошельков вызывающей стороны
+func (h *Handler) CreateWebhook(c *gin.Context) {
+	createWebhookRequest := c.MustGet("validatedBody").(*models.CreateWebhookRequest)
+	principal := c.MustGet("principal").(*models.Principal)
+
+	ctx := c.Request.Context()
+
+	createdWebhook, err := h.facade.CreateWebhook(ctx, principal.ID, createWebhookRequest)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	respond(c, http.StatusCreated, createdWebhook)
+}
+
+func (h *Handler) GetWebhooks(c *gin.Context) {
+	principal := c.MustGet("principal").(*models.Principal)
+
+	ctx := c.Request.Context()
+
+	webhooks, err := h.facade.GetWebhooks(ctx, principal.ID)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, webhooks)
+}
+
+// Возвращает историю доставок по подписке вызывающей стороны
+func (h *Handler) GetWebhookDeliveries(c *gin.Context) {
+	webhookId := uuid.MustParse(c.MustGet("validatedParams").(*models.GetWebhookDeliveriesRequest).ID)
+	principal := c.MustGet("principal").(*models.Principal)
+
+	ctx := c.Request.Context()
+
+	deliveries, err := h.facade.GetWebhookDeliveries(ctx, principal.ID, webhookId)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, deliveries)
+}
+
+// Ставит доставку в очередь повторно, в том числе после перехода в статус dead
+func (h *Handler) RedeliverWebhook(c *gin.Context) {
+	params := c.MustGet("validatedParams").(*models.RedeliverWebhookRequest)
+	principal := c.MustGet("principal").(*models.Principal)
+
+	ctx := c.Request.Context()
+
+	delivery, err := h.facade.RedeliverWebhook(ctx, principal.ID, uuid.MustParse(params.ID), uuid.MustParse(params.DeliveryID))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	respond(c, http.StatusAccepted, delivery)
+}
+
+// Отдает поток событий кошелька в формате Server-Sent Events
+//
+// Идентификатор SSE события совпадает с идентификатором события в outbox,
+// поэтому при переподключении с заголовком Last-Event-ID клиент получает все пропущенные события.
+// Поток живет, пока клиент не закроет соединение, поэтому используется контекст запроса без таймаута
+func (h *Handler) StreamWalletEvents(c *gin.Context) {
+	walletId := uuid.MustParse(c.MustGet("validatedParams").(*models.GetWalletEventsRequest).ID)
+	principal := c.MustGet("principal").(*models.Principal)
+	lastEventId, _ := strconv.ParseInt(c.GetHeader("Last-Event-ID"), 10, 64)
+
+	ctx := c.Request.Context()
+	subscription, err := h.facade.SubscribeWalletEvents(ctx, principal, walletId, lastEventId)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	defer subscription.Close()
+
+	// WriteTimeout сервера ограничивает время всего ответа, для потока он снимается
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Header("Content-Type", "text/event-stream;charset=utf-8")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-subscription.Events():
+			if !ok {
+				return
+			}
+			c.Render(-1, sse.Event{
+				Id:    strconv.FormatInt(event.ID, 10),
+				Event: string(event.Type),
+				Data:  event.Payload,
+			})
+		}
+		c.Writer.Flush()
+	}
 }
@@ -2,24 +2,38 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"infotecstechtask/internal/adjustment"
+	"infotecstechtask/internal/auth"
+	"infotecstechtask/internal/balance"
 	"infotecstechtask/internal/facade"
+	"infotecstechtask/internal/middleware"
 	"infotecstechtask/internal/models"
 	"infotecstechtask/internal/payment"
+	"infotecstechtask/internal/statement"
 	"infotecstechtask/internal/wallet"
+	"infotecstechtask/internal/webhook"
 	"infotecstechtask/test/testutils"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
+const testAPIKey = "test-api-key"
+
 type TestInfrastructure struct {
 	suite.Suite
 	rGroup     *gin.Engine
@@ -41,6 +55,17 @@ func (tf *TestInfrastructure) AfterTest(_, _ string) {
 	tf.rGroup = gin.Default()
 }
 
+// Функция настраивает мок фасада так, чтобы тестовый API ключ принадлежал владельцу с указанной ролью
+func (tf *TestInfrastructure) authenticate(mockFacade *facade.MockFacade, role models.Role) *models.Principal {
+	principal := &models.Principal{
+		ID:   uuid.MustParse("5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e01"),
+		Role: role,
+	}
+	mockFacade.On("Authenticate", mock.Anything, testAPIKey).Return(principal, nil)
+
+	return principal
+}
+
 func (tf *TestInfrastructure) TestCreateTransactionSuccess() {
 	var request models.CreateTransactionRequest
 	err := tf.dataLoader.LoadJSONFixture("transactions/request/create_transaction_request.json", &request)
@@ -51,9 +76,11 @@ func (tf *TestInfrastructure) TestCreateTransactionSuccess() {
 	tf.Require().NoError(err)
 
 	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
 	mockFacade.On(
 		"CreateTransaction",
 		mock.Anything,
+		principal,
 		&request,
 	).Return(&response, nil)
 
@@ -65,6 +92,7 @@ func (tf *TestInfrastructure) TestCreateTransactionSuccess() {
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodPost, FULL_SEND, body)
+	req.Header.Add("X-API-Key", testAPIKey)
 	req.Header.Add("Content-Type", "application/json")
 
 	tf.rGroup.ServeHTTP(w, req)
@@ -76,6 +104,31 @@ func (tf *TestInfrastructure) TestCreateTransactionSuccess() {
 	tf.Assert().Equal(string(expectedResponseBody), w.Body.String())
 }
 
+func (tf *TestInfrastructure) TestCreateTransactionFromForeignWallet() {
+	var request models.CreateTransactionRequest
+	err := tf.dataLoader.LoadJSONFixture("transactions/request/create_transaction_request.json", &request)
+	tf.Require().NoError(err)
+
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
+	mockFacade.On("CreateTransaction", mock.Anything, principal, &request).Return(nil, wallet.ErrWalletNotOwned)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	jsonRequest, err := json.Marshal(request)
+	tf.Require().NoError(err)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, FULL_SEND, bytes.NewBuffer(jsonRequest))
+	req.Header.Add("X-API-Key", testAPIKey)
+	req.Header.Add("Content-Type", "application/json")
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	tf.Assert().Equal(403, w.Code)
+	tf.Assert().JSONEq(`{"Error":"Wallet does not belong to the caller"}`, w.Body.String())
+}
+
 func (tf *TestInfrastructure) TestCreateTransactionErrSenderWalletNotFound() {
 	var request models.CreateTransactionRequest
 	err := tf.dataLoader.LoadJSONFixture("transactions/request/create_transaction_request.json", &request)
@@ -86,9 +139,11 @@ func (tf *TestInfrastructure) TestCreateTransactionErrSenderWalletNotFound() {
 	tf.Require().NoError(err)
 
 	mockFacade := new(facade.MockFacade)
+	tf.authenticate(mockFacade, models.RoleClient)
 	mockFacade.On(
 		"CreateTransaction",
 		mock.Anything,
+		mock.Anything,
 		&request,
 	).Return(nil, payment.ErrSenderWalletNotFound)
 
@@ -100,6 +155,7 @@ func (tf *TestInfrastructure) TestCreateTransactionErrSenderWalletNotFound() {
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodPost, FULL_SEND, body)
+	req.Header.Add("X-API-Key", testAPIKey)
 	req.Header.Add("Content-Type", "application/json")
 
 	tf.rGroup.ServeHTTP(w, req)
@@ -121,9 +177,11 @@ func (tf *TestInfrastructure) TestCreateTransactionErrRecipientWalletNotFound()
 	tf.Require().NoError(err)
 
 	mockFacade := new(facade.MockFacade)
+	tf.authenticate(mockFacade, models.RoleClient)
 	mockFacade.On(
 		"CreateTransaction",
 		mock.Anything,
+		mock.Anything,
 		&request,
 	).Return(nil, payment.ErrRecipientWalletNotFound)
 
@@ -135,6 +193,7 @@ func (tf *TestInfrastructure) TestCreateTransactionErrRecipientWalletNotFound()
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodPost, FULL_SEND, body)
+	req.Header.Add("X-API-Key", testAPIKey)
 	req.Header.Add("Content-Type", "application/json")
 
 	tf.rGroup.ServeHTTP(w, req)
@@ -156,9 +215,11 @@ func (tf *TestInfrastructure) TestCreateTransactionSenderNotHaveEnoughBalance()
 	tf.Require().NoError(err)
 
 	mockFacade := new(facade.MockFacade)
+	tf.authenticate(mockFacade, models.RoleClient)
 	mockFacade.On(
 		"CreateTransaction",
 		mock.Anything,
+		mock.Anything,
 		&request,
 	).Return(&expectedResp, nil)
 
@@ -170,6 +231,7 @@ func (tf *TestInfrastructure) TestCreateTransactionSenderNotHaveEnoughBalance()
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodPost, FULL_SEND, body)
+	req.Header.Add("X-API-Key", testAPIKey)
 	req.Header.Add("Content-Type", "application/json")
 
 	tf.rGroup.ServeHTTP(w, req)
@@ -187,16 +249,19 @@ func (tf *TestInfrastructure) TestGetAllTransactionSuccess() {
 	tf.Require().NoError(err)
 
 	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
 
 	mockFacade.On(
-		"GetAllTransactions",
+		"GetAllTransactionsByOwner",
 		mock.Anything,
+		principal.ID,
 	).Return(models.ToTransactionResponses(allTransactions), nil)
 
 	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
 
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, FULL_TRANSACTIONS, nil)
+	req.Header.Add("X-API-Key", testAPIKey)
 
 	tf.rGroup.ServeHTTP(w, req)
 
@@ -219,6 +284,7 @@ func (tf *TestInfrastructure) TestGetTransactions() {
 	tf.Require().NoError(err)
 
 	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
 	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
 
 	testCases := []struct {
@@ -235,8 +301,9 @@ func (tf *TestInfrastructure) TestGetTransactions() {
 
 	for _, tc := range testCases {
 		mockFacade.On(
-			"GetTransactions",
+			"GetTransactionsByOwner",
 			mock.Anything,
+			principal.ID,
 			tc.count,
 		).Return(models.ToTransactionResponses(tc.expected), nil)
 
@@ -244,6 +311,7 @@ func (tf *TestInfrastructure) TestGetTransactions() {
 		builder.Write([]byte(fmt.Sprintf("?count=%d", tc.count)))
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodGet, builder.String(), nil)
+		req.Header.Add("X-API-Key", testAPIKey)
 
 		tf.rGroup.ServeHTTP(w, req)
 
@@ -256,15 +324,449 @@ func (tf *TestInfrastructure) TestGetTransactions() {
 	}
 }
 
+func (tf *TestInfrastructure) TestExportTransactions() {
+	var oneTransaction []*models.Transaction
+	err := tf.dataLoader.LoadJSONFixture("transactions/one_transaction.json", &oneTransaction)
+	tf.Require().NoError(err)
+
+	from := time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
+
+	csvBody := "id,type,from,to,amount,status,message_code,message,created_at\n" +
+		"033a1b17-c706-46f4-b024-49af5ad5a764,transfer,b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10,b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12," +
+		"10.00,pending,TRANSACTION_PENDING,%s,2025-08-04T00:00:00Z\n"
+	ndjsonBody := `{"id":"033a1b17-c706-46f4-b024-49af5ad5a764","type":"transfer","from":"b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10",` +
+		`"to":"b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12","amount":10,"status":"pending","message":"Transaction pending",` +
+		`"message_code":"TRANSACTION_PENDING","created_at":"2025-08-04T00:00:00Z"}` + "\n"
+
+	tests := []struct {
+		name        string
+		query       string
+		accept      string
+		language    string
+		filter      func(filter *models.TransactionFilter)
+		contentType string
+		body        string
+	}{
+		{
+			name:        "csv by query",
+			query:       "?format=csv",
+			contentType: "text/csv; charset=utf-8",
+			body:        fmt.Sprintf(csvBody, "Transaction pending"),
+		},
+		{
+			name:        "csv by accept header",
+			accept:      "text/csv",
+			contentType: "text/csv; charset=utf-8",
+			body:        fmt.Sprintf(csvBody, "Transaction pending"),
+		},
+		{
+			name:        "query overrides accept header",
+			query:       "?format=ndjson",
+			accept:      "text/csv",
+			contentType: "application/x-ndjson",
+			body:        ndjsonBody,
+		},
+		{
+			name:  "filters by date range and count",
+			query: "?format=csv&count=10&from=2025-08-01T00:00:00Z&to=2025-09-01T00:00:00Z",
+			filter: func(filter *models.TransactionFilter) {
+				count := 10
+				filter.Limit, filter.From, filter.To = &count, &from, &to
+			},
+			contentType: "text/csv; charset=utf-8",
+			body:        fmt.Sprintf(csvBody, "Transaction pending"),
+		},
+		{
+			name:        "localized messages",
+			query:       "?format=csv",
+			language:    "ru",
+			contentType: "text/csv; charset=utf-8",
+			body:        fmt.Sprintf(csvBody, "Транзакция в обработке"),
+		},
+	}
+
+	for _, tt := range tests {
+		tf.Run(tt.name, func() {
+			tf.rGroup = gin.New()
+			tf.rGroup.Use(middleware.Localization())
+			mockFacade := new(facade.MockFacade)
+			principal := tf.authenticate(mockFacade, models.RoleClient)
+
+			expectedFilter := models.TransactionFilter{OwnerID: &principal.ID}
+			if tt.filter != nil {
+				tt.filter(&expectedFilter)
+			}
+			mockFacade.On("StreamTransactions", mock.Anything, expectedFilter).Return(models.ToTransactionResponses(oneTransaction), nil)
+
+			RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, FULL_TRANSACTIONS+tt.query, nil)
+			req.Header.Add("X-API-Key", testAPIKey)
+			if tt.accept != "" {
+				req.Header.Add("Accept", tt.accept)
+			}
+			if tt.language != "" {
+				req.Header.Add("Accept-Language", tt.language)
+			}
+
+			tf.rGroup.ServeHTTP(w, req)
+
+			tf.Assert().Equal(200, w.Code)
+			tf.Assert().Equal(tt.contentType, w.Header().Get("Content-Type"))
+			tf.Assert().Contains(w.Header().Get("Content-Disposition"), "attachment")
+			tf.Assert().Equal(tt.body, w.Body.String())
+			mockFacade.AssertExpectations(tf.T())
+		})
+	}
+}
+
+// У корректировки отсутствующая сторона отдается как null в JSON и пустой ячейкой в CSV
+func (tf *TestInfrastructure) TestAdjustmentTransactionWithoutSender() {
+	transactions := []*models.Transaction{{
+		ID:        uuid.MustParse("033a1b17-c706-46f4-b024-49af5ad5a765"),
+		Type:      models.TypeAdjustment,
+		ToAddress: uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10"),
+		Amount:    2550,
+		Status:    models.Completed,
+		Message:   models.TRANSACTION_COMPLETED,
+		CreatedAt: time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC),
+	}}
+
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
+	mockFacade.On("GetTransactionsByOwner", mock.Anything, principal.ID, 1).Return(models.ToTransactionResponses(transactions), nil)
+	mockFacade.On("StreamTransactions", mock.Anything, models.TransactionFilter{OwnerID: &principal.ID}).Return(models.ToTransactionResponses(transactions), nil)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, FULL_TRANSACTIONS+"?count=1", nil)
+	req.Header.Add("X-API-Key", testAPIKey)
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	tf.Assert().Equal(200, w.Code)
+	tf.Assert().Contains(w.Body.String(), `"from":null,"to":"b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10"`)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, FULL_TRANSACTIONS+"?format=csv", nil)
+	req.Header.Add("X-API-Key", testAPIKey)
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	tf.Assert().Equal(200, w.Code)
+	tf.Assert().Contains(w.Body.String(), "033a1b17-c706-46f4-b024-49af5ad5a765,adjustment,,b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10,25.50,")
+}
+
+func (tf *TestInfrastructure) TestExportEmptyTransactions() {
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
+	mockFacade.On("StreamTransactions", mock.Anything, models.TransactionFilter{OwnerID: &principal.ID}).Return(nil, nil)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, FULL_TRANSACTIONS+"?format=csv", nil)
+	req.Header.Add("X-API-Key", testAPIKey)
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	tf.Assert().Equal(200, w.Code)
+	tf.Assert().Equal("id,type,from,to,amount,status,message_code,message,created_at\n", w.Body.String())
+}
+
+// Ошибку после начала выгрузки можно передать только разрывом соединения
+func (tf *TestInfrastructure) TestExportInterrupted() {
+	var allTransactions []*models.Transaction
+	err := tf.dataLoader.LoadJSONFixture("transactions/all_transactions.json", &allTransactions)
+	tf.Require().NoError(err)
+
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
+	mockFacade.On("StreamTransactions", mock.Anything, models.TransactionFilter{OwnerID: &principal.ID}).
+		Return(models.ToTransactionResponses(allTransactions), errors.New("connection reset"))
+
+	router := gin.New()
+	router.Use(middleware.Recovery())
+	RegisterHTTPEndpoints(&router.RouterGroup, mockFacade, tf.validate)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, FULL_TRANSACTIONS+"?format=ndjson", nil)
+	req.Header.Add("X-API-Key", testAPIKey)
+
+	tf.Assert().PanicsWithValue(http.ErrAbortHandler, func() { router.ServeHTTP(w, req) })
+}
+
+func (tf *TestInfrastructure) TestGetTransactionsWithInvalidDateRange() {
+	mockFacade := new(facade.MockFacade)
+	tf.authenticate(mockFacade, models.RoleClient)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	for _, query := range []string{
+		"?from=2025-09-01T00:00:00Z&to=2025-08-01T00:00:00Z",
+		"?from=2025-08-01",
+		"?format=xlsx",
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, FULL_TRANSACTIONS+query, nil)
+		req.Header.Add("X-API-Key", testAPIKey)
+
+		tf.rGroup.ServeHTTP(w, req)
+
+		tf.Assert().Equal(400, w.Code, query)
+	}
+	mockFacade.AssertNotCalled(tf.T(), "StreamTransactions", mock.Anything, mock.Anything)
+}
+
+func (tf *TestInfrastructure) TestGetWalletTransactions() {
+	var twoTransactions []*models.Transaction
+	err := tf.dataLoader.LoadJSONFixture("transactions/two_transactions.json", &twoTransactions)
+	tf.Require().NoError(err)
+	walletId := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10")
+	path := strings.Replace(FULL_WALLET_HISTORY, ":walletId", walletId.String(), 1)
+
+	tests := []struct {
+		name     string
+		role     models.Role
+		ownerId  bool
+		err      error
+		status   int
+		expected any
+	}{
+		{name: "owner", role: models.RoleClient, ownerId: true, status: 200, expected: models.ToTransactionResponses(twoTransactions)},
+		{name: "viewer sees any wallet", role: models.RoleViewer, status: 200, expected: models.ToTransactionResponses(twoTransactions)},
+		{name: "foreign wallet", role: models.RoleClient, ownerId: true, err: wallet.ErrWalletNotOwned, status: 403, expected: models.Error{Error: "Wallet does not belong to the caller"}},
+		{name: "unknown wallet", role: models.RoleViewer, err: wallet.ErrWalletNotFound, status: 404, expected: models.Error{Error: "Wallet not found"}},
+	}
+
+	for _, tt := range tests {
+		tf.Run(tt.name, func() {
+			tf.rGroup = gin.Default()
+			mockFacade := new(facade.MockFacade)
+			principal := tf.authenticate(mockFacade, tt.role)
+
+			expectedFilter := models.TransactionFilter{WalletID: &walletId}
+			if tt.ownerId {
+				expectedFilter.OwnerID = &principal.ID
+			}
+			var transactions []*models.TransactionResponse
+			if tt.err == nil {
+				transactions = models.ToTransactionResponses(twoTransactions)
+			}
+			mockFacade.On("StreamTransactions", mock.Anything, expectedFilter).Return(transactions, tt.err)
+
+			RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+			req.Header.Add("X-API-Key", testAPIKey)
+
+			tf.rGroup.ServeHTTP(w, req)
+
+			expectedResponseBody, err := json.Marshal(tt.expected)
+			tf.Require().NoError(err)
+
+			tf.Assert().Equal(tt.status, w.Code)
+			tf.Assert().Equal(string(expectedResponseBody), w.Body.String())
+			mockFacade.AssertExpectations(tf.T())
+		})
+	}
+}
+
+func (tf *TestInfrastructure) TestGetStatement() {
+	walletId := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10")
+	from := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+	path := strings.Replace(FULL_WALLET_STATEMENTS, ":walletId", walletId.String(), 1) + "?from=2026-02-01T00:00:00Z&to=2026-03-01T00:00:00Z"
+
+	tests := []struct {
+		name        string
+		query       string
+		accept      string
+		contentType string
+		body        string
+	}{
+		{name: "json by default", contentType: "application/json; charset=utf-8", body: `"opening_balance":100`},
+		{name: "html by query", query: "&format=html", contentType: "text/html; charset=utf-8", body: "Wallet statement"},
+		{name: "pdf by accept", accept: "application/pdf", contentType: "application/pdf", body: "%PDF-"},
+	}
+
+	for _, tt := range tests {
+		tf.Run(tt.name, func() {
+			tf.rGroup = gin.Default()
+			mockFacade := new(facade.MockFacade)
+			principal := tf.authenticate(mockFacade, models.RoleClient)
+
+			statement := models.ToStatementResponse(models.NewStatement(walletId, from, to, 10000, []models.StatementLine{
+				{TransactionID: uuid.New(), Type: models.TypeTransfer, Counterparty: uuid.New(), Amount: -2500, MessageCode: "TRANSACTION_COMPLETED", CreatedAt: from.Add(time.Hour)},
+			}, to))
+			mockFacade.On("GetStatement", mock.Anything, principal, walletId, from, to).Return(statement, nil)
+
+			RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path+tt.query, nil)
+			req.Header.Add("X-API-Key", testAPIKey)
+			if tt.accept != "" {
+				req.Header.Add("Accept", tt.accept)
+			}
+
+			tf.rGroup.ServeHTTP(w, req)
+
+			tf.Assert().Equal(200, w.Code)
+			tf.Assert().Equal(tt.contentType, w.Header().Get("Content-Type"))
+			tf.Assert().Contains(w.Body.String(), tt.body)
+			mockFacade.AssertExpectations(tf.T())
+		})
+	}
+}
+
+func (tf *TestInfrastructure) TestGetStatementErrors() {
+	walletId := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10")
+	path := strings.Replace(FULL_WALLET_STATEMENTS, ":walletId", walletId.String(), 1)
+
+	tests := []struct {
+		name   string
+		query  string
+		err    error
+		status int
+	}{
+		{name: "only from", query: "?from=2026-02-01T00:00:00Z", status: 400},
+		{name: "reversed period", query: "?from=2026-03-01T00:00:00Z&to=2026-02-01T00:00:00Z", status: 400},
+		{name: "unknown format", query: "?format=xlsx", status: 400},
+		{name: "period too long", query: "?from=2024-01-01T00:00:00Z&to=2026-01-01T00:00:00Z", err: statement.ErrPeriodTooLong, status: 400},
+		{name: "foreign wallet", err: wallet.ErrWalletNotOwned, status: 403},
+	}
+
+	for _, tt := range tests {
+		tf.Run(tt.name, func() {
+			tf.rGroup = gin.Default()
+			mockFacade := new(facade.MockFacade)
+			tf.authenticate(mockFacade, models.RoleClient)
+			if tt.err != nil {
+				mockFacade.On("GetStatement", mock.Anything, mock.Anything, walletId, mock.Anything, mock.Anything).Return(nil, tt.err)
+			}
+
+			RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path+tt.query, nil)
+			req.Header.Add("X-API-Key", testAPIKey)
+
+			tf.rGroup.ServeHTTP(w, req)
+
+			tf.Assert().Equal(tt.status, w.Code)
+			if tt.err == nil {
+				mockFacade.AssertNotCalled(tf.T(), "GetStatement", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func (tf *TestInfrastructure) TestGetAdminTransactionsSuccess() {
+	var allTransactions []*models.Transaction
+	err := tf.dataLoader.LoadJSONFixture("transactions/all_transactions.json", &allTransactions)
+	tf.Require().NoError(err)
+	var twoTransactions []*models.Transaction
+	err = tf.dataLoader.LoadJSONFixture("transactions/two_transactions.json", &twoTransactions)
+	tf.Require().NoError(err)
+
+	mockFacade := new(facade.MockFacade)
+	tf.authenticate(mockFacade, models.RoleViewer)
+
+	mockFacade.On(
+		"GetAllTransactions",
+		mock.Anything,
+	).Return(models.ToTransactionResponses(allTransactions), nil)
+	mockFacade.On(
+		"GetTransactions",
+		mock.Anything,
+		2,
+	).Return(models.ToTransactionResponses(twoTransactions), nil)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	testCases := []struct {
+		path     string
+		expected []*models.Transaction
+	}{
+		{FULL_ADMIN_TRANSACTIONS, allTransactions},
+		{FULL_ADMIN_TRANSACTIONS + "?count=2", twoTransactions},
+	}
+
+	for _, tc := range testCases {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, tc.path, nil)
+		req.Header.Add("X-API-Key", testAPIKey)
+
+		tf.rGroup.ServeHTTP(w, req)
+
+		expectedResponseBody, err := json.Marshal(models.ToTransactionResponses(tc.expected))
+		tf.Require().NoError(err)
+
+		tf.Assert().Equal(200, w.Code)
+		tf.Assert().Equal(string(expectedResponseBody), w.Body.String())
+	}
+}
+
+func (tf *TestInfrastructure) TestGetAdminTransactionsForbiddenForClient() {
+	mockFacade := new(facade.MockFacade)
+	tf.authenticate(mockFacade, models.RoleClient)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, FULL_ADMIN_TRANSACTIONS, nil)
+	req.Header.Add("X-API-Key", testAPIKey)
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	tf.Assert().Equal(403, w.Code)
+	mockFacade.AssertNotCalled(tf.T(), "GetAllTransactions", mock.Anything)
+}
+
+func (tf *TestInfrastructure) TestRequestWithoutAPIKey() {
+	mockFacade := new(facade.MockFacade)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, FULL_TRANSACTIONS, nil)
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	tf.Assert().Equal(401, w.Code)
+}
+
+func (tf *TestInfrastructure) TestRequestWithInvalidAPIKey() {
+	mockFacade := new(facade.MockFacade)
+	mockFacade.On("Authenticate", mock.Anything, "unknown-key").Return(nil, auth.ErrInvalidAPIKey)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, FULL_TRANSACTIONS, nil)
+	req.Header.Add("Authorization", "Bearer unknown-key")
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	tf.Assert().Equal(401, w.Code)
+}
+
 func (tf *TestInfrastructure) TestGetWalletSuccess() {
 	var expectedResp models.WalletResponse
 	err := tf.dataLoader.LoadJSONFixture("wallets/wallet.json", &expectedResp)
 	tf.Require().NoError(err)
 
 	mockFacade := new(facade.MockFacade)
+	tf.authenticate(mockFacade, models.RoleClient)
 	mockFacade.On(
 		"GetWallet",
 		mock.Anything,
+		mock.Anything,
 		expectedResp.ID,
 	).Return(&expectedResp, nil)
 
@@ -275,6 +777,7 @@ func (tf *TestInfrastructure) TestGetWalletSuccess() {
 	path := strings.Replace(FULL_GET_WALLET_BALANCE, ":walletId", expectedResp.ID.String(), 1)
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	req.Header.Add("X-API-Key", testAPIKey)
 
 	tf.rGroup.ServeHTTP(w, req)
 
@@ -291,9 +794,11 @@ func (tf *TestInfrastructure) TestGetWalletNotFound() {
 	tf.Require().NoError(err)
 
 	mockFacade := new(facade.MockFacade)
+	tf.authenticate(mockFacade, models.RoleClient)
 	mockFacade.On(
 		"GetWallet",
 		mock.Anything,
+		mock.Anything,
 		expectedResp.ID,
 	).Return(nil, wallet.ErrWalletNotFound)
 
@@ -302,6 +807,7 @@ func (tf *TestInfrastructure) TestGetWalletNotFound() {
 	path := strings.Replace(FULL_GET_WALLET_BALANCE, ":walletId", expectedResp.ID.String(), 1)
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	req.Header.Add("X-API-Key", testAPIKey)
 
 	tf.rGroup.ServeHTTP(w, req)
 
@@ -310,62 +816,466 @@ func (tf *TestInfrastructure) TestGetWalletNotFound() {
 	tf.Assert().Equal(404, w.Code)
 }
 
-func (tf *TestInfrastructure) TestCreateTransactionWithNonValidRequest() {
-	var requestWithoutFromAddress models.CreateTransactionRequest
-	err := tf.dataLoader.LoadJSONFixture("transactions/request/create_transaction_request_without_from_address.json", &requestWithoutFromAddress)
-	tf.Require().NoError(err)
-	var requestWithNonValidFromAddress models.CreateTransactionRequest
-	err = tf.dataLoader.LoadJSONFixture("transactions/request/create_transaction_request_with_non_valid_from_address.json", &requestWithNonValidFromAddress)
-	tf.Require().NoError(err)
-	var requestWithoutToAddress models.CreateTransactionRequest
-	err = tf.dataLoader.LoadJSONFixture("transactions/request/create_transaction_request_without_to_address.json", &requestWithoutToAddress)
-	tf.Require().NoError(err)
-	var requestWithNonValidToAddress models.CreateTransactionRequest
-	err = tf.dataLoader.LoadJSONFixture("transactions/request/create_transaction_request_with_non_valid_to_address.json", &requestWithNonValidToAddress)
-	tf.Require().NoError(err)
-	var requestWithoutAmount models.CreateTransactionRequest
-	err = tf.dataLoader.LoadJSONFixture("transactions/request/create_transaction_request_without_amount.json", &requestWithoutAmount)
-	tf.Require().NoError(err)
-	var requestWithNegativeAmount models.CreateTransactionRequest
-	err = tf.dataLoader.LoadJSONFixture("transactions/request/create_transaction_request_with_negative_amount.json", &requestWithNegativeAmount)
-	tf.Require().NoError(err)
+func (tf *TestInfrastructure) TestGetForeignWallet() {
+	walletId := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a13")
 
-	var fromAddressRequired models.ValidationError
-	err = tf.dataLoader.LoadJSONFixture("errors/from_address_required.json", &fromAddressRequired)
-	tf.Require().NoError(err)
-	var fromAddressNonValid models.ValidationError
-	err = tf.dataLoader.LoadJSONFixture("errors/from_address_non_valid.json", &fromAddressNonValid)
-	tf.Require().NoError(err)
-	var toAddressRequired models.ValidationError
-	err = tf.dataLoader.LoadJSONFixture("errors/to_address_required.json", &toAddressRequired)
-	tf.Require().NoError(err)
-	var toAddressNonValid models.ValidationError
-	err = tf.dataLoader.LoadJSONFixture("errors/to_address_non_valid.json", &toAddressNonValid)
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
+	mockFacade.On("GetWallet", mock.Anything, principal, walletId).Return(nil, wallet.ErrWalletNotOwned)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	path := strings.Replace(FULL_GET_WALLET_BALANCE, ":walletId", walletId.String(), 1)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	req.Header.Add("X-API-Key", testAPIKey)
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	tf.Assert().Equal(403, w.Code)
+	mockFacade.AssertExpectations(tf.T())
+}
+
+func (tf *TestInfrastructure) TestGetWalletVersions() {
+	var wallet models.WalletResponse
+	err := tf.dataLoader.LoadJSONFixture("wallets/wallet.json", &wallet)
 	tf.Require().NoError(err)
-	var amountRequired models.ValidationError
-	err = tf.dataLoader.LoadJSONFixture("errors/amount_required.json", &amountRequired)
+
+	mockFacade := new(facade.MockFacade)
+	tf.authenticate(mockFacade, models.RoleClient)
+	mockFacade.On("GetWallet", mock.Anything, mock.Anything, wallet.ID).Return(&wallet, nil)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	v1Body, err := json.Marshal(wallet)
 	tf.Require().NoError(err)
-	var amountNegative models.ValidationError
-	err = tf.dataLoader.LoadJSONFixture("errors/amount_negative.json", &amountNegative)
+	v2Body, err := json.Marshal(models.DataResponse{Data: models.ToWalletResponseV2(&wallet)})
 	tf.Require().NoError(err)
 
-	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, nil, tf.validate)
-
-	testCases := []struct {
-		request          models.CreateTransactionRequest
-		expectedResponse models.ValidationError
+	tests := []struct {
+		name         string
+		basePath     string
+		expectedBody string
+		deprecated   bool
 	}{
-		{
-			request:          requestWithoutFromAddress,
-			expectedResponse: fromAddressRequired,
-		},
-		{
-			request:          requestWithNonValidFromAddress,
-			expectedResponse: fromAddressNonValid,
-		},
-		{
-			request:          requestWithoutToAddress,
-			expectedResponse: toAddressRequired,
+		{name: "legacy", basePath: BASED_PATH, expectedBody: string(v1Body), deprecated: true},
+		{name: "v1", basePath: API_V1_PATH, expectedBody: string(v1Body)},
+		{name: "v2", basePath: API_V2_PATH, expectedBody: string(v2Body)},
+	}
+
+	for _, tt := range tests {
+		tf.Run(tt.name, func() {
+			path := tt.basePath + strings.Replace(GET_WALLET_BALANCE, ":walletId", wallet.ID.String(), 1)
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path, nil)
+			req.Header.Add("X-API-Key", testAPIKey)
+
+			tf.rGroup.ServeHTTP(w, req)
+
+			tf.Assert().Equal(200, w.Code)
+			tf.Assert().Equal(tt.expectedBody, w.Body.String())
+			if tt.deprecated {
+				tf.Assert().Equal("@1792368000", w.Header().Get("Deprecation"))
+				tf.Assert().Equal("Thu, 01 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+				tf.Assert().Equal(`<`+API_V1_PATH+strings.Replace(GET_WALLET_BALANCE, ":walletId", wallet.ID.String(), 1)+`>; rel="successor-version"`, w.Header().Get("Link"))
+			} else {
+				tf.Assert().Empty(w.Header().Get("Deprecation"))
+				tf.Assert().Empty(w.Header().Get("Sunset"))
+			}
+		})
+	}
+}
+
+func (tf *TestInfrastructure) TestGetWalletBalanceAt() {
+	walletId := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10")
+	at := time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
+	expectedResp := &models.WalletResponse{ID: walletId, Balance: 113.3}
+
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
+	mockFacade.On("GetWalletBalanceAt", mock.Anything, principal, walletId, at).Return(expectedResp, nil)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	path := strings.Replace(FULL_GET_WALLET_BALANCE, ":walletId", walletId.String(), 1) + "?at=2025-08-01T00:00:00Z"
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	req.Header.Add("X-API-Key", testAPIKey)
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	expectedResponseBody, err := json.Marshal(expectedResp)
+	tf.Require().NoError(err)
+
+	tf.Assert().Equal(200, w.Code)
+	tf.Assert().Equal(string(expectedResponseBody), w.Body.String())
+	mockFacade.AssertNotCalled(tf.T(), "GetWallet", mock.Anything, mock.Anything, mock.Anything)
+	mockFacade.AssertExpectations(tf.T())
+}
+
+func (tf *TestInfrastructure) TestGetForeignWalletBalanceAt() {
+	walletId := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a13")
+	at := time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
+	mockFacade.On("GetWalletBalanceAt", mock.Anything, principal, walletId, at).Return(nil, wallet.ErrWalletNotOwned)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	path := strings.Replace(FULL_GET_WALLET_BALANCE, ":walletId", walletId.String(), 1) + "?at=2025-08-01T00:00:00Z"
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	req.Header.Add("X-API-Key", testAPIKey)
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	expectedResponseBody, err := json.Marshal(models.Error{Error: wallet.ErrWalletNotOwned.Error()})
+	tf.Require().NoError(err)
+
+	tf.Assert().Equal(403, w.Code)
+	tf.Assert().Equal(string(expectedResponseBody), w.Body.String())
+	mockFacade.AssertExpectations(tf.T())
+}
+
+func (tf *TestInfrastructure) TestGetBalanceHistory() {
+	walletId := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10")
+	from := time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.August, 3, 0, 0, 0, 0, time.UTC)
+	path := strings.Replace(FULL_BALANCE_HISTORY, ":walletId", walletId.String(), 1) + "?from=2025-08-01T00:00:00Z&to=2025-08-03T00:00:00Z"
+
+	tests := []struct {
+		name     string
+		query    string
+		interval models.BalanceInterval
+	}{
+		{name: "default interval", interval: ""},
+		{name: "hourly", query: "&interval=hour", interval: models.IntervalHour},
+	}
+
+	for _, tt := range tests {
+		tf.Run(tt.name, func() {
+			tf.rGroup = gin.Default()
+			mockFacade := new(facade.MockFacade)
+			principal := tf.authenticate(mockFacade, models.RoleClient)
+
+			history := models.ToBalanceHistoryResponse(walletId, models.IntervalDay,
+				[]time.Time{from, from.AddDate(0, 0, 1), to}, []int{11330, 11330, 10000})
+			mockFacade.On("GetBalanceHistory", mock.Anything, principal, walletId, from, to, tt.interval).Return(history, nil)
+
+			RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path+tt.query, nil)
+			req.Header.Add("X-API-Key", testAPIKey)
+
+			tf.rGroup.ServeHTTP(w, req)
+
+			expectedResponseBody, err := json.Marshal(history)
+			tf.Require().NoError(err)
+
+			tf.Assert().Equal(200, w.Code)
+			tf.Assert().Equal(string(expectedResponseBody), w.Body.String())
+			tf.Assert().Contains(w.Body.String(), `{"at":"2025-08-01T00:00:00Z","balance":113.3}`)
+			mockFacade.AssertExpectations(tf.T())
+		})
+	}
+}
+
+func (tf *TestInfrastructure) TestGetBalanceHistoryErrors() {
+	walletId := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10")
+	path := strings.Replace(FULL_BALANCE_HISTORY, ":walletId", walletId.String(), 1)
+	period := "?from=2025-08-01T00:00:00Z&to=2025-09-01T00:00:00Z"
+
+	tests := []struct {
+		name   string
+		query  string
+		err    error
+		status int
+	}{
+		{name: "without period", status: 400},
+		{name: "only from", query: "?from=2025-08-01T00:00:00Z", status: 400},
+		{name: "reversed period", query: "?from=2025-09-01T00:00:00Z&to=2025-08-01T00:00:00Z", status: 400},
+		{name: "unknown interval", query: period + "&interval=minute", status: 400},
+		{name: "too many points", query: period + "&interval=hour", err: balance.ErrTooManyPoints, status: 400},
+		{name: "foreign wallet", query: period, err: wallet.ErrWalletNotOwned, status: 403},
+		{name: "unknown wallet", query: period, err: wallet.ErrWalletNotFound, status: 404},
+	}
+
+	for _, tt := range tests {
+		tf.Run(tt.name, func() {
+			tf.rGroup = gin.Default()
+			mockFacade := new(facade.MockFacade)
+			tf.authenticate(mockFacade, models.RoleClient)
+			if tt.err != nil {
+				mockFacade.On("GetBalanceHistory", mock.Anything, mock.Anything, walletId, mock.Anything, mock.Anything, mock.Anything).Return(nil, tt.err)
+			}
+
+			RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, path+tt.query, nil)
+			req.Header.Add("X-API-Key", testAPIKey)
+
+			tf.rGroup.ServeHTTP(w, req)
+
+			tf.Assert().Equal(tt.status, w.Code)
+			if tt.err == nil {
+				mockFacade.AssertNotCalled(tf.T(), "GetBalanceHistory", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func (tf *TestInfrastructure) TestProblemErrors() {
+	walletId := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a14")
+	adjustmentId := uuid.MustParse("c1eebc99-9c0b-4ef8-bb6d-6bb9bd380a15")
+
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleAdmin)
+	mockFacade.On("GetWallet", mock.Anything, mock.Anything, walletId).Return(nil, wallet.ErrWalletNotFound)
+	mockFacade.On("ApproveAdjustment", mock.Anything, adjustmentId, principal.ID).Return(nil, adjustment.ErrInsufficientBalance)
+	mockFacade.On("GetWebhooks", mock.Anything, principal.ID).Return(nil, errors.New("connection refused"))
+
+	tf.rGroup.Use(middleware.RequestID())
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	walletPath := strings.Replace(GET_WALLET_BALANCE, ":walletId", walletId.String(), 1)
+	approvePath := strings.Replace(APPROVE_ADJUSTMENT, ":adjustmentId", adjustmentId.String(), 1)
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		accept          string
+		apiKey          string
+		body            string
+		expectedStatus  int
+		expectedProblem models.Problem
+	}{
+		{
+			name:            "wallet not found",
+			method:          http.MethodGet,
+			path:            API_V2_PATH + walletPath,
+			apiKey:          testAPIKey,
+			expectedStatus:  404,
+			expectedProblem: models.Problem{Code: models.CodeWalletNotFound, Detail: wallet.ErrWalletNotFound.Error()},
+		},
+		{
+			name:            "v1 with problem accept",
+			method:          http.MethodGet,
+			path:            API_V1_PATH + walletPath,
+			accept:          "application/json, application/problem+json",
+			apiKey:          testAPIKey,
+			expectedStatus:  404,
+			expectedProblem: models.Problem{Code: models.CodeWalletNotFound, Detail: wallet.ErrWalletNotFound.Error()},
+		},
+		{
+			name:            "insufficient funds",
+			method:          http.MethodPost,
+			path:            API_V2_PATH + ADMIN_PATH + approvePath,
+			apiKey:          testAPIKey,
+			expectedStatus:  409,
+			expectedProblem: models.Problem{Code: models.CodeInsufficientFunds, Detail: adjustment.ErrInsufficientBalance.Error()},
+		},
+		{
+			name:            "internal error",
+			method:          http.MethodGet,
+			path:            API_V2_PATH + WEBHOOKS,
+			apiKey:          testAPIKey,
+			expectedStatus:  500,
+			expectedProblem: models.Problem{Code: models.CodeInternalError, Detail: "Internal error"},
+		},
+		{
+			name:            "missing api key",
+			method:          http.MethodGet,
+			path:            API_V2_PATH + TRANSACTIONS,
+			expectedStatus:  401,
+			expectedProblem: models.Problem{Code: models.CodeAPIKeyRequired, Detail: "API key is required"},
+		},
+		{
+			name:            "invalid json",
+			method:          http.MethodPost,
+			path:            API_V2_PATH + SEND,
+			apiKey:          testAPIKey,
+			body:            `{"from":`,
+			expectedStatus:  400,
+			expectedProblem: models.Problem{Code: models.CodeInvalidJSON, Detail: "Invalid JSON body: unexpected end of input"},
+		},
+		{
+			name:            "invalid json field type",
+			method:          http.MethodPost,
+			path:            API_V2_PATH + SEND,
+			apiKey:          testAPIKey,
+			body:            `{"from":"b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a14","to":"b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a15","amount":"10"}`,
+			expectedStatus:  400,
+			expectedProblem: models.Problem{Code: models.CodeInvalidJSON, Detail: "Invalid JSON body: field amount must not be a string"},
+		},
+		{
+			name:           "validation failed",
+			method:         http.MethodPost,
+			path:           API_V2_PATH + SEND,
+			apiKey:         testAPIKey,
+			body:           `{"to":"b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a14","amount":10}`,
+			expectedStatus: 400,
+			expectedProblem: models.Problem{
+				Code:   models.CodeValidationFailed,
+				Detail: "Validation failed",
+				Errors: []models.FieldError{{Field: "from", Message: "Field is required"}},
+			},
+		},
+		{
+			name:            "invalid query parameters",
+			method:          http.MethodGet,
+			path:            API_V2_PATH + TRANSACTIONS + "?count=abc",
+			apiKey:          testAPIKey,
+			expectedStatus:  400,
+			expectedProblem: models.Problem{Code: models.CodeInvalidParameters, Detail: `Invalid query parameters: value "abc" is not a valid number`},
+		},
+		{
+			name:           "query parameters validation failed",
+			method:         http.MethodGet,
+			path:           API_V2_PATH + TRANSACTIONS + "?count=0",
+			apiKey:         testAPIKey,
+			expectedStatus: 400,
+			expectedProblem: models.Problem{
+				Code:   models.CodeValidationFailed,
+				Detail: "Validation failed",
+				Errors: []models.FieldError{{Field: "count", Message: "Field must be greater than 1"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tf.Run(tt.name, func() {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.apiKey != "" {
+				req.Header.Add("X-API-Key", tt.apiKey)
+			}
+			if tt.accept != "" {
+				req.Header.Add("Accept", tt.accept)
+			}
+			req.Header.Add("Content-Type", "application/json")
+			requestId := strings.ReplaceAll(tt.name, " ", "-")
+			req.Header.Add(middleware.RequestIDHeader, requestId)
+
+			tf.rGroup.ServeHTTP(w, req)
+
+			expectedProblem := tt.expectedProblem
+			expectedProblem.Type = "about:blank"
+			expectedProblem.Title = http.StatusText(tt.expectedStatus)
+			expectedProblem.Status = tt.expectedStatus
+			expectedProblem.Instance = req.URL.Path
+			expectedProblem.RequestID = requestId
+			expectedBody, err := json.Marshal(expectedProblem)
+			tf.Require().NoError(err)
+
+			tf.Assert().Equal(tt.expectedStatus, w.Code)
+			tf.Assert().Equal(middleware.ProblemContentType, w.Header().Get("Content-Type"))
+			tf.Assert().JSONEq(string(expectedBody), w.Body.String())
+		})
+	}
+}
+
+func (tf *TestInfrastructure) TestLocalizedResponses() {
+	var allTransactions []*models.Transaction
+	err := tf.dataLoader.LoadJSONFixture("transactions/all_transactions.json", &allTransactions)
+	tf.Require().NoError(err)
+	walletId := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a14")
+
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
+	mockFacade.On("GetAllTransactionsByOwner", mock.Anything, principal.ID).Return(models.ToTransactionResponses(allTransactions), nil)
+	mockFacade.On("GetWallet", mock.Anything, mock.Anything, walletId).Return(nil, wallet.ErrWalletNotFound)
+
+	tf.rGroup.Use(middleware.Localization())
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, API_V1_PATH+TRANSACTIONS, nil)
+	req.Header.Add("X-API-Key", testAPIKey)
+	req.Header.Add("Accept-Language", "ru")
+	tf.rGroup.ServeHTTP(w, req)
+
+	var transactions []*models.TransactionResponse
+	tf.Require().NoError(json.Unmarshal(w.Body.Bytes(), &transactions))
+	tf.Require().Len(transactions, 3)
+	tf.Assert().Equal("ru", w.Header().Get("Content-Language"))
+	tf.Assert().Equal("Транзакция в обработке", transactions[0].Message)
+	tf.Assert().Equal(models.TRANSACTION_PENDING, transactions[0].MessageCode)
+	tf.Assert().Equal("У отправителя недостаточно средств", transactions[1].Message)
+	tf.Assert().Equal("Транзакция выполнена", transactions[2].Message)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, API_V1_PATH+strings.Replace(GET_WALLET_BALANCE, ":walletId", walletId.String(), 1), nil)
+	req.Header.Add("X-API-Key", testAPIKey)
+	req.Header.Add("Accept-Language", "ru")
+	tf.rGroup.ServeHTTP(w, req)
+
+	tf.Assert().Equal(404, w.Code)
+	tf.Assert().Equal(`{"Error":"Кошелек не найден"}`, w.Body.String())
+}
+
+func (tf *TestInfrastructure) TestCreateTransactionWithNonValidRequest() {
+	var requestWithoutFromAddress models.CreateTransactionRequest
+	err := tf.dataLoader.LoadJSONFixture("transactions/request/create_transaction_request_without_from_address.json", &requestWithoutFromAddress)
+	tf.Require().NoError(err)
+	var requestWithNonValidFromAddress models.CreateTransactionRequest
+	err = tf.dataLoader.LoadJSONFixture("transactions/request/create_transaction_request_with_non_valid_from_address.json", &requestWithNonValidFromAddress)
+	tf.Require().NoError(err)
+	var requestWithoutToAddress models.CreateTransactionRequest
+	err = tf.dataLoader.LoadJSONFixture("transactions/request/create_transaction_request_without_to_address.json", &requestWithoutToAddress)
+	tf.Require().NoError(err)
+	var requestWithNonValidToAddress models.CreateTransactionRequest
+	err = tf.dataLoader.LoadJSONFixture("transactions/request/create_transaction_request_with_non_valid_to_address.json", &requestWithNonValidToAddress)
+	tf.Require().NoError(err)
+	var requestWithoutAmount models.CreateTransactionRequest
+	err = tf.dataLoader.LoadJSONFixture("transactions/request/create_transaction_request_without_amount.json", &requestWithoutAmount)
+	tf.Require().NoError(err)
+	var requestWithNegativeAmount models.CreateTransactionRequest
+	err = tf.dataLoader.LoadJSONFixture("transactions/request/create_transaction_request_with_negative_amount.json", &requestWithNegativeAmount)
+	tf.Require().NoError(err)
+
+	var fromAddressRequired models.ValidationError
+	err = tf.dataLoader.LoadJSONFixture("errors/from_address_required.json", &fromAddressRequired)
+	tf.Require().NoError(err)
+	var fromAddressNonValid models.ValidationError
+	err = tf.dataLoader.LoadJSONFixture("errors/from_address_non_valid.json", &fromAddressNonValid)
+	tf.Require().NoError(err)
+	var toAddressRequired models.ValidationError
+	err = tf.dataLoader.LoadJSONFixture("errors/to_address_required.json", &toAddressRequired)
+	tf.Require().NoError(err)
+	var toAddressNonValid models.ValidationError
+	err = tf.dataLoader.LoadJSONFixture("errors/to_address_non_valid.json", &toAddressNonValid)
+	tf.Require().NoError(err)
+	var amountRequired models.ValidationError
+	err = tf.dataLoader.LoadJSONFixture("errors/amount_required.json", &amountRequired)
+	tf.Require().NoError(err)
+	var amountNegative models.ValidationError
+	err = tf.dataLoader.LoadJSONFixture("errors/amount_negative.json", &amountNegative)
+	tf.Require().NoError(err)
+
+	mockFacade := new(facade.MockFacade)
+	tf.authenticate(mockFacade, models.RoleClient)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	testCases := []struct {
+		request          models.CreateTransactionRequest
+		expectedResponse models.ValidationError
+	}{
+		{
+			request:          requestWithoutFromAddress,
+			expectedResponse: fromAddressRequired,
+		},
+		{
+			request:          requestWithNonValidFromAddress,
+			expectedResponse: fromAddressNonValid,
+		},
+		{
+			request:          requestWithoutToAddress,
+			expectedResponse: toAddressRequired,
 		},
 		{
 			request:          requestWithNonValidToAddress,
@@ -388,6 +1298,7 @@ func (tf *TestInfrastructure) TestCreateTransactionWithNonValidRequest() {
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodPost, FULL_SEND, body)
+		req.Header.Add("X-API-Key", testAPIKey)
 		req.Header.Add("Content-Type", "application/json")
 
 		tf.rGroup.ServeHTTP(w, req)
@@ -399,3 +1310,581 @@ func (tf *TestInfrastructure) TestCreateTransactionWithNonValidRequest() {
 		tf.Assert().Equal(string(expectedResponseBody), w.Body.String())
 	}
 }
+
+func (tf *TestInfrastructure) TestCreateAdjustmentSuccess() {
+	var request models.CreateAdjustmentRequest
+	err := tf.dataLoader.LoadJSONFixture("adjustments/request/create_adjustment_request.json", &request)
+	tf.Require().NoError(err)
+	var response models.AdjustmentResponse
+	err = tf.dataLoader.LoadJSONFixture("adjustments/response/adjustment_response.json", &response)
+	tf.Require().NoError(err)
+
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleOperator)
+	mockFacade.On(
+		"CreateAdjustment",
+		mock.Anything,
+		principal.ID,
+		&request,
+	).Return(&response, nil)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	jsonRequest, err := json.Marshal(request)
+	tf.Require().NoError(err)
+	body := bytes.NewBuffer(jsonRequest)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, FULL_ADJUSTMENTS, body)
+	req.Header.Add("X-API-Key", testAPIKey)
+	req.Header.Add("Content-Type", "application/json")
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	expectedResponseBody, err := json.Marshal(response)
+	tf.Require().NoError(err)
+
+	tf.Assert().Equal(201, w.Code)
+	tf.Assert().Equal(string(expectedResponseBody), w.Body.String())
+}
+
+func (tf *TestInfrastructure) TestCreateAdjustmentWithNonValidReasonCode() {
+	var request models.CreateAdjustmentRequest
+	err := tf.dataLoader.LoadJSONFixture("adjustments/request/create_adjustment_request_with_unknown_reason.json", &request)
+	tf.Require().NoError(err)
+	var expectedErr models.ValidationError
+	err = tf.dataLoader.LoadJSONFixture("errors/reason_code_non_valid.json", &expectedErr)
+	tf.Require().NoError(err)
+
+	mockFacade := new(facade.MockFacade)
+	tf.authenticate(mockFacade, models.RoleOperator)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	jsonRequest, err := json.Marshal(request)
+	tf.Require().NoError(err)
+	body := bytes.NewBuffer(jsonRequest)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, FULL_ADJUSTMENTS, body)
+	req.Header.Add("X-API-Key", testAPIKey)
+	req.Header.Add("Content-Type", "application/json")
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	expectedResponseBody, err := json.Marshal(expectedErr)
+	tf.Require().NoError(err)
+
+	tf.Assert().Equal(400, w.Code)
+	tf.Assert().Equal(string(expectedResponseBody), w.Body.String())
+}
+
+func (tf *TestInfrastructure) TestCreateAdjustmentWithTooSmallAmount() {
+	var request models.CreateAdjustmentRequest
+	err := tf.dataLoader.LoadJSONFixture("adjustments/request/create_adjustment_request_with_too_small_amount.json", &request)
+	tf.Require().NoError(err)
+
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleOperator)
+	mockFacade.On("CreateAdjustment", mock.Anything, principal.ID, &request).Return(nil, adjustment.ErrAmountTooSmall)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	jsonRequest, err := json.Marshal(request)
+	tf.Require().NoError(err)
+	body := bytes.NewBuffer(jsonRequest)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, FULL_ADJUSTMENTS, body)
+	req.Header.Add("X-API-Key", testAPIKey)
+	req.Header.Add("Content-Type", "application/json")
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	expectedResponseBody, err := json.Marshal(models.Error{Error: adjustment.ErrAmountTooSmall.Error()})
+	tf.Require().NoError(err)
+
+	tf.Assert().Equal(400, w.Code)
+	tf.Assert().Equal(string(expectedResponseBody), w.Body.String())
+}
+
+func (tf *TestInfrastructure) TestCreateAdjustmentForbiddenForViewer() {
+	var request models.CreateAdjustmentRequest
+	err := tf.dataLoader.LoadJSONFixture("adjustments/request/create_adjustment_request.json", &request)
+	tf.Require().NoError(err)
+
+	mockFacade := new(facade.MockFacade)
+	tf.authenticate(mockFacade, models.RoleViewer)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	jsonRequest, err := json.Marshal(request)
+	tf.Require().NoError(err)
+	body := bytes.NewBuffer(jsonRequest)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, FULL_ADJUSTMENTS, body)
+	req.Header.Add("X-API-Key", testAPIKey)
+	req.Header.Add("Content-Type", "application/json")
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	tf.Assert().Equal(403, w.Code)
+}
+
+func (tf *TestInfrastructure) TestReviewAdjustment() {
+	var response models.AdjustmentResponse
+	err := tf.dataLoader.LoadJSONFixture("adjustments/response/adjustment_response.json", &response)
+	tf.Require().NoError(err)
+
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleAdmin)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	testCases := []struct {
+		method       string
+		path         string
+		err          error
+		expectedCode int
+	}{
+		{"ApproveAdjustment", FULL_APPROVE_ADJUSTMENT, nil, 200},
+		{"RejectAdjustment", FULL_REJECT_ADJUSTMENT, nil, 200},
+		{"ApproveAdjustment", FULL_APPROVE_ADJUSTMENT, adjustment.ErrSelfReview, 403},
+		{"ApproveAdjustment", FULL_APPROVE_ADJUSTMENT, adjustment.ErrAdjustmentNotFound, 404},
+		{"RejectAdjustment", FULL_REJECT_ADJUSTMENT, adjustment.ErrAdjustmentAlreadyReviewed, 409},
+		{"ApproveAdjustment", FULL_APPROVE_ADJUSTMENT, adjustment.ErrInsufficientBalance, 409},
+	}
+
+	for _, tc := range testCases {
+		mockFacade.ExpectedCalls = mockFacade.ExpectedCalls[:1]
+		if tc.err != nil {
+			mockFacade.On(tc.method, mock.Anything, response.ID, principal.ID).Return(nil, tc.err)
+		} else {
+			mockFacade.On(tc.method, mock.Anything, response.ID, principal.ID).Return(&response, nil)
+		}
+
+		path := strings.Replace(tc.path, ":adjustmentId", response.ID.String(), 1)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, path, nil)
+		req.Header.Add("X-API-Key", testAPIKey)
+
+		tf.rGroup.ServeHTTP(w, req)
+
+		tf.Assert().Equal(tc.expectedCode, w.Code)
+		if tc.err != nil {
+			expectedResponseBody, err := json.Marshal(models.Error{Error: tc.err.Error()})
+			tf.Require().NoError(err)
+			tf.Assert().Equal(string(expectedResponseBody), w.Body.String())
+		}
+	}
+}
+
+func (tf *TestInfrastructure) TestReviewAdjustmentForbiddenForOperator() {
+	mockFacade := new(facade.MockFacade)
+	tf.authenticate(mockFacade, models.RoleOperator)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	for _, path := range []string{FULL_APPROVE_ADJUSTMENT, FULL_REJECT_ADJUSTMENT} {
+		path = strings.Replace(path, ":adjustmentId", uuid.NewString(), 1)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, path, nil)
+		req.Header.Add("X-API-Key", testAPIKey)
+
+		tf.rGroup.ServeHTTP(w, req)
+
+		tf.Assert().Equal(403, w.Code)
+	}
+	mockFacade.AssertNotCalled(tf.T(), "ApproveAdjustment", mock.Anything, mock.Anything, mock.Anything)
+	mockFacade.AssertNotCalled(tf.T(), "RejectAdjustment", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (tf *TestInfrastructure) TestCreateWebhookSuccess() {
+	var request models.CreateWebhookRequest
+	err := tf.dataLoader.LoadJSONFixture("webhooks/request/create_webhook_request.json", &request)
+	tf.Require().NoError(err)
+	var response models.WebhookResponse
+	err = tf.dataLoader.LoadJSONFixture("webhooks/response/webhook_response.json", &response)
+	tf.Require().NoError(err)
+
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
+	mockFacade.On(
+		"CreateWebhook",
+		mock.Anything,
+		principal.ID,
+		&request,
+	).Return(&response, nil)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	jsonRequest, err := json.Marshal(request)
+	tf.Require().NoError(err)
+	body := bytes.NewBuffer(jsonRequest)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, FULL_WEBHOOKS, body)
+	req.Header.Add("X-API-Key", testAPIKey)
+	req.Header.Add("Content-Type", "application/json")
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	expectedResponseBody, err := json.Marshal(response)
+	tf.Require().NoError(err)
+
+	tf.Assert().Equal(201, w.Code)
+	tf.Assert().Equal(string(expectedResponseBody), w.Body.String())
+}
+
+func (tf *TestInfrastructure) TestCreateWebhookForForeignWallet() {
+	var request models.CreateWebhookRequest
+	err := tf.dataLoader.LoadJSONFixture("webhooks/request/create_webhook_request.json", &request)
+	tf.Require().NoError(err)
+
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
+	mockFacade.On(
+		"CreateWebhook",
+		mock.Anything,
+		principal.ID,
+		&request,
+	).Return(nil, wallet.ErrWalletNotOwned)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	jsonRequest, err := json.Marshal(request)
+	tf.Require().NoError(err)
+	body := bytes.NewBuffer(jsonRequest)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, FULL_WEBHOOKS, body)
+	req.Header.Add("X-API-Key", testAPIKey)
+	req.Header.Add("Content-Type", "application/json")
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	expectedResponseBody, err := json.Marshal(models.Error{Error: wallet.ErrWalletNotOwned.Error()})
+	tf.Require().NoError(err)
+
+	tf.Assert().Equal(403, w.Code)
+	tf.Assert().Equal(string(expectedResponseBody), w.Body.String())
+}
+
+func (tf *TestInfrastructure) TestCreateWebhookWithNotAllowedURL() {
+	var request models.CreateWebhookRequest
+	err := tf.dataLoader.LoadJSONFixture("webhooks/request/create_webhook_request.json", &request)
+	tf.Require().NoError(err)
+	request.URL = "https://169.254.169.254/latest/meta-data"
+
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
+	mockFacade.On("CreateWebhook", mock.Anything, principal.ID, &request).Return(nil, webhook.ErrURLNotAllowed)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	jsonRequest, err := json.Marshal(request)
+	tf.Require().NoError(err)
+	body := bytes.NewBuffer(jsonRequest)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, FULL_WEBHOOKS, body)
+	req.Header.Add("X-API-Key", testAPIKey)
+	req.Header.Add("Content-Type", "application/json")
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	expectedResponseBody, err := json.Marshal(models.Error{Error: webhook.ErrURLNotAllowed.Error()})
+	tf.Require().NoError(err)
+
+	tf.Assert().Equal(400, w.Code)
+	tf.Assert().Equal(string(expectedResponseBody), w.Body.String())
+}
+
+func (tf *TestInfrastructure) TestRedeliverWebhook() {
+	var response models.WebhookDeliveryResponse
+	err := tf.dataLoader.LoadJSONFixture("webhooks/response/webhook_delivery_response.json", &response)
+	tf.Require().NoError(err)
+	webhookId := uuid.MustParse("7c1e4b2a-9d3f-4a6e-8b5c-1f2d3e4a5b6c")
+
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	testCases := []struct {
+		err          error
+		expectedCode int
+	}{
+		{nil, 202},
+		{webhook.ErrWebhookNotFound, 404},
+		{webhook.ErrDeliveryNotFound, 404},
+	}
+
+	for _, tc := range testCases {
+		mockFacade.ExpectedCalls = mockFacade.ExpectedCalls[:1]
+		if tc.err != nil {
+			mockFacade.On("RedeliverWebhook", mock.Anything, principal.ID, webhookId, response.ID).Return(nil, tc.err)
+		} else {
+			mockFacade.On("RedeliverWebhook", mock.Anything, principal.ID, webhookId, response.ID).Return(&response, nil)
+		}
+
+		path := strings.Replace(FULL_REDELIVER_WEBHOOK, ":webhookId", webhookId.String(), 1)
+		path = strings.Replace(path, ":deliveryId", response.ID.String(), 1)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, path, nil)
+		req.Header.Add("X-API-Key", testAPIKey)
+
+		tf.rGroup.ServeHTTP(w, req)
+
+		tf.Assert().Equal(tc.expectedCode, w.Code)
+		if tc.err != nil {
+			expectedResponseBody, err := json.Marshal(models.Error{Error: tc.err.Error()})
+			tf.Require().NoError(err)
+			tf.Assert().Equal(string(expectedResponseBody), w.Body.String())
+		}
+	}
+}
+
+func (tf *TestInfrastructure) TestGetWebhooksTimeout() {
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
+	mockFacade.On("GetWebhooks", mock.Anything, principal.ID).Return(nil, fmt.Errorf("failed to get webhooks: %w", context.DeadlineExceeded))
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, FULL_WEBHOOKS, nil)
+	req.Header.Add("X-API-Key", testAPIKey)
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	tf.Assert().Equal(http.StatusGatewayTimeout, w.Code)
+	tf.Assert().JSONEq(`{"Error":"Request timed out"}`, w.Body.String())
+}
+
+func (tf *TestInfrastructure) TestGetWebhooksClientClosedRequest() {
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
+	mockFacade.On("GetWebhooks", mock.Anything, principal.ID).Return(nil, context.Canceled)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, FULL_WEBHOOKS, nil)
+	req.Header.Add("X-API-Key", testAPIKey)
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	tf.Assert().Equal(middleware.StatusClientClosedRequest, w.Code)
+}
+
+type testSubscription struct {
+	events chan *models.Event
+	closed atomic.Bool
+}
+
+func (s *testSubscription) Events() <-chan *models.Event {
+	return s.events
+}
+
+func (s *testSubscription) Close() {
+	s.closed.Store(true)
+}
+
+func (tf *TestInfrastructure) TestStreamWalletEvents() {
+	walletId := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a14")
+	subscription := &testSubscription{events: make(chan *models.Event, 1)}
+	subscription.events <- &models.Event{
+		ID:       42,
+		WalletID: walletId,
+		Type:     models.EventWalletBalanceChanged,
+		Payload:  json.RawMessage(`{"balance":115}`),
+	}
+	close(subscription.events)
+
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
+	mockFacade.On("SubscribeWalletEvents", mock.Anything, principal, walletId, int64(41)).Return(subscription, nil)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, strings.Replace(FULL_WALLET_EVENTS, ":walletId", walletId.String(), 1), nil)
+	req.Header.Add("X-API-Key", testAPIKey)
+	req.Header.Add("Last-Event-ID", "41")
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	tf.Assert().Equal(200, w.Code)
+	tf.Assert().Equal("text/event-stream;charset=utf-8", w.Header().Get("Content-Type"))
+	tf.Assert().Equal("id:42\nevent:wallet.balance_changed\ndata:{\"balance\":115}\n\n", w.Body.String())
+	tf.Assert().True(subscription.closed.Load())
+}
+
+func (tf *TestInfrastructure) TestStreamWalletEventsForForeignWallet() {
+	walletId := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a14")
+
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
+	mockFacade.On("SubscribeWalletEvents", mock.Anything, principal, walletId, int64(0)).Return(nil, wallet.ErrWalletNotOwned)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, strings.Replace(FULL_WALLET_EVENTS, ":walletId", walletId.String(), 1), nil)
+	req.Header.Add("X-API-Key", testAPIKey)
+
+	tf.rGroup.ServeHTTP(w, req)
+
+	tf.Assert().Equal(403, w.Code)
+}
+
+// Функция открывает WebSocket-ленту на тестовом сервере
+func (tf *TestInfrastructure) dialTransactionFeed(server *httptest.Server, apiKey string) (*websocket.Conn, *http.Response, error) {
+	header := http.Header{}
+	if apiKey != "" {
+		header.Add("X-API-Key", apiKey)
+	}
+
+	return websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+FULL_TRANSACTION_FEED, header)
+}
+
+func (tf *TestInfrastructure) TestTransactionFeed() {
+	ownWallet := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a14")
+	foreignWallet := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a15")
+	subscription := &testSubscription{events: make(chan *models.Event, 1)}
+	subscription.events <- &models.Event{
+		ID:       42,
+		WalletID: ownWallet,
+		Type:     models.EventTransactionCompleted,
+		Payload:  json.RawMessage(`{"amount":15}`),
+	}
+
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
+	mockFacade.On("SubscribeWalletEvents", mock.Anything, principal, ownWallet, int64(41)).Return(subscription, nil)
+	mockFacade.On("SubscribeWalletEvents", mock.Anything, principal, foreignWallet, int64(41)).Return(nil, wallet.ErrWalletNotOwned)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+	server := httptest.NewServer(tf.rGroup)
+	defer server.Close()
+
+	conn, _, err := tf.dialTransactionFeed(server, testAPIKey)
+	tf.Require().NoError(err)
+	defer conn.Close()
+
+	err = conn.WriteJSON(models.FeedRequest{
+		Action:      models.FeedSubscribe,
+		WalletIDs:   []string{ownWallet.String(), foreignWallet.String()},
+		LastEventID: 41,
+	})
+	tf.Require().NoError(err)
+
+	received := make(map[models.FeedMessageType]models.FeedMessage)
+	for range 3 {
+		var message models.FeedMessage
+		tf.Require().NoError(conn.ReadJSON(&message))
+		received[message.Type] = message
+	}
+
+	tf.Assert().Equal([]uuid.UUID{ownWallet}, received[models.FeedSubscribed].WalletIDs)
+	tf.Assert().Equal(&foreignWallet, received[models.FeedError].WalletID)
+	tf.Assert().Equal(models.CodeWalletNotOwned, received[models.FeedError].Code)
+	tf.Assert().Equal(wallet.ErrWalletNotOwned.Error(), received[models.FeedError].Error)
+	tf.Require().NotNil(received[models.FeedEvent].Event)
+	tf.Assert().Equal(int64(42), received[models.FeedEvent].Event.ID)
+
+	err = conn.WriteJSON(models.FeedRequest{Action: models.FeedUnsubscribe, WalletIDs: []string{ownWallet.String()}})
+	tf.Require().NoError(err)
+
+	var message models.FeedMessage
+	tf.Require().NoError(conn.ReadJSON(&message))
+	tf.Assert().Equal(models.FeedUnsubscribed, message.Type)
+	tf.Assert().True(subscription.closed.Load())
+}
+
+func (tf *TestInfrastructure) TestTransactionFeedInterruptedSubscription() {
+	walletId := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a14")
+	subscription := &testSubscription{events: make(chan *models.Event)}
+
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
+	mockFacade.On("SubscribeWalletEvents", mock.Anything, principal, walletId, int64(0)).Return(subscription, nil)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+	server := httptest.NewServer(tf.rGroup)
+	defer server.Close()
+
+	conn, _, err := tf.dialTransactionFeed(server, testAPIKey)
+	tf.Require().NoError(err)
+	defer conn.Close()
+
+	err = conn.WriteJSON(models.FeedRequest{Action: models.FeedSubscribe, WalletIDs: []string{walletId.String()}})
+	tf.Require().NoError(err)
+
+	var message models.FeedMessage
+	tf.Require().NoError(conn.ReadJSON(&message))
+	tf.Assert().Equal(models.FeedSubscribed, message.Type)
+
+	close(subscription.events)
+
+	_, _, err = conn.ReadMessage()
+	tf.Assert().True(websocket.IsCloseError(err, websocket.CloseTryAgainLater))
+	tf.Assert().Eventually(subscription.closed.Load, time.Second, 10*time.Millisecond)
+}
+
+// Ошибки ленты отправляются с кодом и переводятся на язык из Accept-Language
+func (tf *TestInfrastructure) TestTransactionFeedLocalizedErrors() {
+	walletId := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a14")
+
+	mockFacade := new(facade.MockFacade)
+	principal := tf.authenticate(mockFacade, models.RoleClient)
+	mockFacade.On("SubscribeWalletEvents", mock.Anything, principal, walletId, int64(0)).Return(nil, wallet.ErrWalletNotFound)
+
+	tf.rGroup.Use(middleware.Localization())
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+	server := httptest.NewServer(tf.rGroup)
+	defer server.Close()
+
+	header := http.Header{}
+	header.Add("X-API-Key", testAPIKey)
+	header.Add("Accept-Language", "ru")
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+FULL_TRANSACTION_FEED, header)
+	tf.Require().NoError(err)
+	defer conn.Close()
+
+	tf.Require().NoError(conn.WriteMessage(websocket.TextMessage, []byte("not json")))
+	var message models.FeedMessage
+	tf.Require().NoError(conn.ReadJSON(&message))
+	tf.Assert().Equal(models.FeedError, message.Type)
+	tf.Assert().Equal(models.CodeInvalidJSON, message.Code)
+	tf.Assert().Equal("Некорректное сообщение", message.Error)
+
+	tf.Require().NoError(conn.WriteJSON(models.FeedRequest{Action: "watch", WalletIDs: []string{walletId.String()}}))
+	tf.Require().NoError(conn.ReadJSON(&message))
+	tf.Assert().Equal(models.CodeValidationFailed, message.Code)
+	tf.Assert().Equal("Поле action должно принимать одно из значений: subscribe unsubscribe", message.Error)
+
+	tf.Require().NoError(conn.WriteJSON(models.FeedRequest{Action: models.FeedSubscribe, WalletIDs: []string{walletId.String()}}))
+	message = models.FeedMessage{}
+	tf.Require().NoError(conn.ReadJSON(&message))
+	tf.Assert().Equal(&walletId, message.WalletID)
+	tf.Assert().Equal(models.CodeWalletNotFound, message.Code)
+	tf.Assert().Equal("Кошелек не найден", message.Error)
+}
+
+func (tf *TestInfrastructure) TestTransactionFeedWithoutAPIKey() {
+	mockFacade := new(facade.MockFacade)
+
+	RegisterHTTPEndpoints(&tf.rGroup.RouterGroup, mockFacade, tf.validate)
+	server := httptest.NewServer(tf.rGroup)
+	defer server.Close()
+
+	_, response, err := tf.dialTransactionFeed(server, "")
+	tf.Require().ErrorIs(err, websocket.ErrBadHandshake)
+	tf.Assert().Equal(401, response.StatusCode)
+}
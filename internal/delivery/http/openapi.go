@@ -0,0 +1,53 @@
+package http
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Спецификация API в формате OpenAPI 3
+// При добавлении эндпоинта в RegisterHTTPEndpoints его нужно описать в openapi.json, это проверяется тестом
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// Страница Swagger UI, статика загружается с CDN, спецификация - с OPENAPI
+const swaggerPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Infotecs TechTask API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({url: "` + OPENAPI + `", dom_id: "#swagger-ui"});
+    };
+  </script>
+</body>
+</html>
+`
+
+// Функция для регистрации документации API
+// Документация доступна без аутентификации
+func RegisterDocsEndpoints(router *gin.RouterGroup) {
+	router.GET(OPENAPI, func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", openAPISpec)
+	})
+	router.GET(SWAGGER, swaggerUI)
+}
+
+// Отдает Swagger UI по адресам /swagger/ и /swagger/index.html
+func swaggerUI(c *gin.Context) {
+	switch c.Param("any") {
+	case "/", "/index.html":
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerPage))
+	default:
+		c.AbortWithStatus(http.StatusNotFound)
+	}
+}
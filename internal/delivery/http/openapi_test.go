@@ -0,0 +1,168 @@
+package http
+
+import (
+	"encoding/json"
+	"infotecstechtask/internal/facade"
+	"infotecstechtask/internal/models"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type openAPIDocument struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Components struct {
+		Schemas map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"schemas"`
+	} `json:"components"`
+}
+
+func loadOpenAPI(t *testing.T) openAPIDocument {
+	var document openAPIDocument
+	require.NoError(t, json.Unmarshal(openAPISpec, &document))
+	return document
+}
+
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	document := loadOpenAPI(t)
+
+	router := gin.New()
+	RegisterHTTPEndpoints(&router.RouterGroup, new(facade.MockFacade), validator.New())
+
+	registered := make(map[string]bool)
+	for _, route := range router.Routes() {
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+		operation := strings.ToLower(route.Method) + " " + path
+		registered[operation] = true
+
+		assert.Contains(t, document.Paths[path], strings.ToLower(route.Method), "route %s %s is missing from openapi.json", route.Method, route.Path)
+	}
+
+	for path, operations := range document.Paths {
+		for method := range operations {
+			assert.True(t, registered[method+" "+path], "openapi.json describes unknown route %s %s", strings.ToUpper(method), path)
+		}
+	}
+}
+
+func TestOpenAPISchemasMatchModels(t *testing.T) {
+	document := loadOpenAPI(t)
+
+	schemas := map[string]any{
+		"Error":                    models.Error{},
+		"FieldError":               models.FieldError{},
+		"ValidationError":          models.ValidationError{},
+		"Problem":                  models.Problem{},
+		"CreateTransactionRequest": models.CreateTransactionRequest{},
+		"TransactionResponse":      models.TransactionResponse{},
+		"WalletResponse":           models.WalletResponse{},
+		"WalletResponseV2":         models.WalletResponseV2{},
+		"CreateAdjustmentRequest":  models.CreateAdjustmentRequest{},
+		"AdjustmentResponse":       models.AdjustmentResponse{},
+		"CreateWebhookRequest":     models.CreateWebhookRequest{},
+		"WebhookResponse":          models.WebhookResponse{},
+		"WebhookDeliveryResponse":  models.WebhookDeliveryResponse{},
+		"Event":                    models.Event{},
+		"BalanceChangedPayload":    models.BalanceChangedPayload{},
+		"FeedRequest":              models.FeedRequest{},
+		"FeedMessage":              models.FeedMessage{},
+	}
+
+	for name, model := range schemas {
+		schema, ok := document.Components.Schemas[name]
+		if !assert.True(t, ok, "schema %s is missing", name) {
+			continue
+		}
+
+		var documented []string
+		for property := range schema.Properties {
+			documented = append(documented, property)
+		}
+		sort.Strings(documented)
+		assert.Equal(t, jsonFields(reflect.TypeOf(model)), documented, "schema %s", name)
+	}
+}
+
+// Функция возвращает имена полей структуры в JSON так, как их сериализует encoding/json
+func jsonFields(modelType reflect.Type) []string {
+	var fields []string
+	for i := range modelType.NumField() {
+		field := modelType.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func TestOpenAPIReferencesResolve(t *testing.T) {
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(openAPISpec, &document))
+
+	var walk func(node any)
+	walk = func(node any) {
+		switch node := node.(type) {
+		case map[string]any:
+			if ref, ok := node["$ref"].(string); ok {
+				var target any = document
+				for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+					object, _ := target.(map[string]any)
+					target = object[part]
+				}
+				assert.NotNil(t, target, "unresolved reference %s", ref)
+			}
+			for _, child := range node {
+				walk(child)
+			}
+		case []any:
+			for _, child := range node {
+				walk(child)
+			}
+		}
+	}
+	walk(document)
+}
+
+func TestDocsEndpoints(t *testing.T) {
+	router := gin.New()
+	RegisterDocsEndpoints(&router.RouterGroup)
+
+	tests := []struct {
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{path: OPENAPI, expectedStatus: http.StatusOK, expectedBody: `"openapi": "3.0.3"`},
+		{path: "/swagger/index.html", expectedStatus: http.StatusOK, expectedBody: OPENAPI},
+		{path: "/swagger/", expectedStatus: http.StatusOK, expectedBody: "swagger-ui"},
+		{path: "/swagger/unknown.js", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedBody)
+		})
+	}
+}
@@ -4,19 +4,55 @@ import (
 	"infotecstechtask/internal/facade"
 	"infotecstechtask/internal/middleware"
 	"infotecstechtask/internal/models"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
+// Даты, которые передаются в заголовках Deprecation и Sunset ответов API без версии
+// После LegacySunsetAt пути /api/... могут быть удалены, клиентам нужно перейти на /api/v1 или /api/v2
+var (
+	LegacyDeprecatedAt = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	LegacySunsetAt     = time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC)
+)
+
 // Функция для регистрации эндпоинтов и соответствующих функций хендлера
+// Эндпоинты регистрируются в группах /api/v1 и /api/v2, пути без версии (/api/...) остаются псевдонимом v1
+// В v2 ответы оборачиваются в конверт {"data": ...}, ошибки возвращаются с кодом, поля называются в snake_case
 func RegisterHTTPEndpoints(router *gin.RouterGroup, facade facade.Facade, validate *validator.Validate) {
 	h := NewHandler(facade)
 
-	api := router.Group(BASED_PATH)
+	registerAPI(router.Group(BASED_PATH, middleware.Deprecated(LegacyDeprecatedAt, LegacySunsetAt, BASED_PATH, API_V1_PATH)), h, facade, validate)
+	registerAPI(router.Group(API_V1_PATH), h, facade, validate)
+	registerAPI(router.Group(API_V2_PATH), h, facade, validate)
+}
+
+// Все эндпоинты требуют аутентификации, эндпоинты группы admin дополнительно проверяют роль
+// Заявки на корректировку создает оператор, а рассматривает только администратор
+func registerAPI(group *gin.RouterGroup, h *Handler, facade facade.Facade, validate *validator.Validate) {
+	api := group.Group("", middleware.Authentication(facade), middleware.AuditMetadata())
 	{
 		api.POST(SEND, middleware.JSONValidation(models.CreateTransactionRequest{}, validate), h.CreateTransaction)
-		api.GET(TRANSACTIONS, middleware.ParamsValidation(models.GetTransactionWithCountRequest{}, validate), h.GetTransactions)
+		api.GET(TRANSACTIONS, middleware.ParamsValidation(models.GetTransactionHistoryRequest{}, validate), h.GetTransactions)
+		api.GET(TRANSACTION_FEED, h.TransactionFeed)
 		api.GET(GET_WALLET_BALANCE, middleware.ParamsValidation(models.GetWalletBalanceRequest{}, validate), h.GetWallet)
+		api.GET(BALANCE_HISTORY, middleware.ParamsValidation(models.GetBalanceHistoryRequest{}, validate), h.GetBalanceHistory)
+		api.GET(WALLET_HISTORY, middleware.ParamsValidation(models.GetWalletTransactionsRequest{}, validate), h.GetWalletTransactions)
+		api.GET(WALLET_EVENTS, middleware.ParamsValidation(models.GetWalletEventsRequest{}, validate), h.StreamWalletEvents)
+		api.GET(WALLET_STATEMENTS, middleware.ParamsValidation(models.GetStatementRequest{}, validate), h.GetStatement)
+		api.POST(WEBHOOKS, middleware.JSONValidation(models.CreateWebhookRequest{}, validate), h.CreateWebhook)
+		api.GET(WEBHOOKS, h.GetWebhooks)
+		api.GET(WEBHOOK_DELIVERIES, middleware.ParamsValidation(models.GetWebhookDeliveriesRequest{}, validate), h.GetWebhookDeliveries)
+		api.POST(REDELIVER_WEBHOOK, middleware.ParamsValidation(models.RedeliverWebhookRequest{}, validate), h.RedeliverWebhook)
+	}
+
+	admin := api.Group(ADMIN_PATH, middleware.RequireRole(models.RoleViewer))
+	{
+		admin.GET(TRANSACTIONS, middleware.ParamsValidation(models.GetTransactionWithCountRequest{}, validate), h.GetAdminTransactions)
+		admin.GET(ADJUSTMENTS, middleware.ParamsValidation(models.GetAdjustmentsRequest{}, validate), h.GetAdjustments)
+		admin.POST(ADJUSTMENTS, middleware.RequireRole(models.RoleOperator), middleware.JSONValidation(models.CreateAdjustmentRequest{}, validate), h.CreateAdjustment)
+		admin.POST(APPROVE_ADJUSTMENT, middleware.RequireRole(models.RoleAdmin), middleware.ParamsValidation(models.ReviewAdjustmentRequest{}, validate), h.ApproveAdjustment)
+		admin.POST(REJECT_ADJUSTMENT, middleware.RequireRole(models.RoleAdmin), middleware.ParamsValidation(models.ReviewAdjustmentRequest{}, validate), h.RejectAdjustment)
 	}
 }
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"bytes"
+	"infotecstechtask/internal/i18n"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/statement/render"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Форматы выписки по кошельку, JSON совпадает с форматом истории транзакций
+const (
+	formatHTML = "html"
+	formatPDF  = "pdf"
+
+	mimePDF = "application/pdf"
+)
+
+// Функция выбирает формат выписки: параметр format важнее заголовка Accept, по умолчанию отдается JSON
+func statementFormat(c *gin.Context, format string) string {
+	if format != "" {
+		return format
+	}
+
+	switch c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML, mimePDF) {
+	case gin.MIMEHTML:
+		return formatHTML
+	case mimePDF:
+		return formatPDF
+	default:
+		return formatJSON
+	}
+}
+
+// Возвращает выписку по кошельку за период
+// Владельцу доступны свои кошельки, ролям viewer и выше - все. HTML и PDF собираются целиком до отправки ответа
+func (h *Handler) GetStatement(c *gin.Context) {
+	params := c.MustGet("validatedParams").(*models.GetStatementRequest)
+	principal := c.MustGet("principal").(*models.Principal)
+	walletId := uuid.MustParse(params.ID)
+	ctx := c.Request.Context()
+
+	statementToReturn, err := h.facade.GetStatement(ctx, principal, walletId, params.From, params.To)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	lang := i18n.FromContext(ctx)
+	format := statementFormat(c, params.Format)
+	if format == formatJSON {
+		statementToReturn.Localize(lang)
+		respond(c, http.StatusOK, statementToReturn)
+		return
+	}
+
+	var buf bytes.Buffer
+	contentType := gin.MIMEHTML + "; charset=utf-8"
+	if format == formatPDF {
+		contentType = mimePDF
+		err = render.PDF(&buf, statementToReturn, lang)
+	} else {
+		err = render.HTML(&buf, statementToReturn, lang)
+	}
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	filename := "statement-" + statementToReturn.From.UTC().Format("2006-01-02") + "." + format
+	c.Header("Content-Disposition", `inline; filename="`+filename+`"`)
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
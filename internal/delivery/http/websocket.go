@@ -0,0 +1,294 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"infotecstechtask/internal/facade"
+	"infotecstechtask/internal/i18n"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/stream"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Время на отправку одного сообщения клиенту
+	feedWriteTimeout = 10 * time.Second
+	// Если за это время от клиента не пришло ни одного сообщения или pong, соединение закрывается
+	feedPongTimeout = 60 * time.Second
+	// Интервал отправки ping, должен быть меньше feedPongTimeout
+	feedPingInterval = 30 * time.Second
+
+	// Размер очереди исходящих сообщений, при переполнении клиент считается медленным и отключается
+	feedSendQueueSize = 64
+
+	feedMaxMessageSize = 64 * 1024
+	feedMaxWallets     = 100
+)
+
+// Проверка Origin не выполняется: аутентификация идет по API ключу из заголовка, а не по cookie
+var feedUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin: func(*http.Request) bool {
+		return true
+	},
+}
+
+// Открывает WebSocket-ленту событий по кошелькам
+//
+// Аутентификация выполняется миддлваром до переключения протокола, поэтому без ключа соединение не устанавливается.
+// Подписки на кошельки добавляются и удаляются сообщениями клиента без переподключения
+func (h *Handler) TransactionFeed(c *gin.Context) {
+	principal := c.MustGet("principal").(*models.Principal)
+
+	conn, err := feedUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrader уже отправил клиенту ответ с ошибкой
+		return
+	}
+
+	newFeedSession(h.facade, principal, conn).run(c.Request.Context())
+}
+
+// Сессия WebSocket-ленты
+//
+// Все записи в соединение выполняет writeLoop, чтение - readLoop.
+// События от подписок складываются в очередь без блокировки: если клиент не успевает их читать,
+// сессия закрывается, а брокер событий продолжает работать для остальных подписчиков
+type feedSession struct {
+	facade    facade.Facade
+	principal *models.Principal
+	conn      *websocket.Conn
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	send   chan *models.FeedMessage
+
+	mu            sync.Mutex
+	subscriptions map[uuid.UUID]stream.Subscription
+
+	closeOnce   sync.Once
+	closeCode   int
+	closeReason string
+}
+
+func newFeedSession(facade facade.Facade, principal *models.Principal, conn *websocket.Conn) *feedSession {
+	return &feedSession{
+		facade:        facade,
+		principal:     principal,
+		conn:          conn,
+		send:          make(chan *models.FeedMessage, feedSendQueueSize),
+		subscriptions: make(map[uuid.UUID]stream.Subscription),
+	}
+}
+
+func (s *feedSession) run(ctx context.Context) {
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	defer s.unsubscribeAll()
+
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		s.writeLoop()
+	}()
+
+	s.readLoop()
+	s.close(websocket.CloseNormalClosure, "")
+	<-writerDone
+}
+
+func (s *feedSession) readLoop() {
+	s.conn.SetReadLimit(feedMaxMessageSize)
+	_ = s.conn.SetReadDeadline(time.Now().Add(feedPongTimeout))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(feedPongTimeout))
+	})
+
+	for {
+		_, message, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		_ = s.conn.SetReadDeadline(time.Now().Add(feedPongTimeout))
+
+		var request models.FeedRequest
+		if err := json.Unmarshal(message, &request); err != nil {
+			s.enqueueError(nil, models.CodeInvalidJSON, "feed.invalid_message")
+			continue
+		}
+		s.handle(&request)
+	}
+}
+
+func (s *feedSession) writeLoop() {
+	ping := time.NewTicker(feedPingInterval)
+	defer ping.Stop()
+	defer s.conn.Close()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			_ = s.conn.WriteControl(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(s.closeCode, s.closeReason),
+				time.Now().Add(feedWriteTimeout),
+			)
+			return
+		case <-ping.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(feedWriteTimeout)); err != nil {
+				s.close(websocket.CloseGoingAway, "")
+				return
+			}
+		case message := <-s.send:
+			_ = s.conn.SetWriteDeadline(time.Now().Add(feedWriteTimeout))
+			if err := s.conn.WriteJSON(message); err != nil {
+				s.close(websocket.CloseGoingAway, "")
+				return
+			}
+		}
+	}
+}
+
+func (s *feedSession) handle(request *models.FeedRequest) {
+	if len(request.WalletIDs) == 0 || len(request.WalletIDs) > feedMaxWallets {
+		s.enqueueError(nil, models.CodeValidationFailed, "feed.wallet_ids_count", feedMaxWallets)
+		return
+	}
+
+	walletIds := make([]uuid.UUID, 0, len(request.WalletIDs))
+	for _, rawId := range request.WalletIDs {
+		walletId, err := uuid.Parse(rawId)
+		if err != nil {
+			s.enqueueError(nil, models.CodeValidationFailed, "feed.wallet_ids_uuid")
+			return
+		}
+		walletIds = append(walletIds, walletId)
+	}
+
+	switch request.Action {
+	case models.FeedSubscribe:
+		s.subscribe(walletIds, request.LastEventID)
+	case models.FeedUnsubscribe:
+		s.unsubscribe(walletIds)
+	default:
+		s.enqueueError(nil, models.CodeValidationFailed, "feed.invalid_action", "subscribe unsubscribe")
+	}
+}
+
+// Функция подписывает сессию на кошельки
+// Ошибка подписки на один кошелек не отменяет подписку на остальные и возвращается клиенту отдельным сообщением
+func (s *feedSession) subscribe(walletIds []uuid.UUID, lastEventId int64) {
+	subscribed := make([]uuid.UUID, 0, len(walletIds))
+
+	for _, walletId := range walletIds {
+		s.mu.Lock()
+		_, exists := s.subscriptions[walletId]
+		count := len(s.subscriptions)
+		s.mu.Unlock()
+
+		if exists {
+			subscribed = append(subscribed, walletId)
+			continue
+		}
+		if count >= feedMaxWallets {
+			s.enqueueError(&walletId, models.CodeValidationFailed, "feed.too_many_subscriptions")
+			continue
+		}
+
+		subscription, err := s.facade.SubscribeWalletEvents(s.ctx, s.principal, walletId, lastEventId)
+		if err != nil {
+			if code, ok := domainErrorCode(err); ok {
+				s.enqueueError(&walletId, code, string(code))
+			} else {
+				slog.ErrorContext(s.ctx, "transaction feed failed to subscribe", "wallet_id", walletId, "error", err)
+				s.enqueueError(&walletId, models.CodeInternalError, "feed.subscribe_failed")
+			}
+			continue
+		}
+
+		s.mu.Lock()
+		s.subscriptions[walletId] = subscription
+		s.mu.Unlock()
+
+		go s.forward(walletId, subscription)
+		subscribed = append(subscribed, walletId)
+	}
+
+	if len(subscribed) > 0 {
+		s.enqueue(&models.FeedMessage{Type: models.FeedSubscribed, WalletIDs: subscribed})
+	}
+}
+
+func (s *feedSession) unsubscribe(walletIds []uuid.UUID) {
+	s.mu.Lock()
+	for _, walletId := range walletIds {
+		if subscription, ok := s.subscriptions[walletId]; ok {
+			delete(s.subscriptions, walletId)
+			subscription.Close()
+		}
+	}
+	s.mu.Unlock()
+
+	s.enqueue(&models.FeedMessage{Type: models.FeedUnsubscribed, WalletIDs: walletIds})
+}
+
+func (s *feedSession) unsubscribeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for walletId, subscription := range s.subscriptions {
+		delete(s.subscriptions, walletId)
+		subscription.Close()
+	}
+}
+
+// Функция пересылает события подписки в очередь сессии
+// Если брокер закрыл подписку сам (клиент отстал или поток событий прервался), сессия закрывается,
+// чтобы клиент переподключился и восстановил пропущенные события через last_event_id
+func (s *feedSession) forward(walletId uuid.UUID, subscription stream.Subscription) {
+	for event := range subscription.Events() {
+		s.enqueue(&models.FeedMessage{Type: models.FeedEvent, Event: event})
+	}
+
+	s.mu.Lock()
+	current, ok := s.subscriptions[walletId]
+	s.mu.Unlock()
+
+	if ok && current == subscription {
+		s.close(websocket.CloseTryAgainLater, s.translate("feed.stream_interrupted"))
+	}
+}
+
+func (s *feedSession) enqueue(message *models.FeedMessage) {
+	select {
+	case s.send <- message:
+	default:
+		s.close(websocket.ClosePolicyViolation, s.translate("feed.slow_consumer"))
+	}
+}
+
+// Функция отправляет клиенту ошибку с кодом, walletId указывается, если ошибка относится к кошельку
+func (s *feedSession) enqueueError(walletId *uuid.UUID, code models.ErrorCode, key string, args ...any) {
+	s.enqueue(&models.FeedMessage{Type: models.FeedError, WalletID: walletId, Code: code, Error: s.translate(key, args...)})
+}
+
+// Функция возвращает сообщение на языке, выбранном при подключении
+func (s *feedSession) translate(key string, args ...any) string {
+	return i18n.Translate(i18n.FromContext(s.ctx), key, args...)
+}
+
+// Функция завершает сессию с указанным кодом, учитывается только первый вызов
+func (s *feedSession) close(code int, reason string) {
+	s.closeOnce.Do(func() {
+		s.closeCode = code
+		s.closeReason = reason
+		s.cancel()
+	})
+}
@@ -3,6 +3,8 @@ package facade
 import (
 	"context"
 	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/stream"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -11,8 +13,24 @@ import (
 // Необходим для того, чтобы хендлер не думал к какому сервису бежать при получении того или иного запроса
 // Предоставляет единый объект для работы со всей системой
 type Facade interface {
-	CreateTransaction(ctx context.Context, createTransactionRequest *models.CreateTransactionRequest) (*models.TransactionResponse, error)
+	Authenticate(ctx context.Context, apiKey string) (*models.Principal, error)
+	CreateTransaction(ctx context.Context, principal *models.Principal, createTransactionRequest *models.CreateTransactionRequest) (*models.TransactionResponse, error)
 	GetTransactions(ctx context.Context, count int) ([]*models.TransactionResponse, error)
 	GetAllTransactions(ctx context.Context) ([]*models.TransactionResponse, error)
-	GetWallet(ctx context.Context, walletId uuid.UUID) (*models.WalletResponse, error)
+	GetTransactionsByOwner(ctx context.Context, ownerId uuid.UUID, count int) ([]*models.TransactionResponse, error)
+	GetAllTransactionsByOwner(ctx context.Context, ownerId uuid.UUID) ([]*models.TransactionResponse, error)
+	StreamTransactions(ctx context.Context, filter models.TransactionFilter, fn func(*models.TransactionResponse) error) error
+	GetWallet(ctx context.Context, principal *models.Principal, walletId uuid.UUID) (*models.WalletResponse, error)
+	GetWalletBalanceAt(ctx context.Context, principal *models.Principal, walletId uuid.UUID, at time.Time) (*models.WalletResponse, error)
+	GetBalanceHistory(ctx context.Context, principal *models.Principal, walletId uuid.UUID, from time.Time, to time.Time, interval models.BalanceInterval) (*models.BalanceHistoryResponse, error)
+	GetStatement(ctx context.Context, principal *models.Principal, walletId uuid.UUID, from time.Time, to time.Time) (*models.StatementResponse, error)
+	CreateAdjustment(ctx context.Context, createdBy uuid.UUID, request *models.CreateAdjustmentRequest) (*models.AdjustmentResponse, error)
+	GetAdjustments(ctx context.Context, status *models.AdjustmentStatus) ([]*models.AdjustmentResponse, error)
+	ApproveAdjustment(ctx context.Context, adjustmentId uuid.UUID, reviewerId uuid.UUID) (*models.AdjustmentResponse, error)
+	RejectAdjustment(ctx context.Context, adjustmentId uuid.UUID, reviewerId uuid.UUID) (*models.AdjustmentResponse, error)
+	CreateWebhook(ctx context.Context, ownerId uuid.UUID, request *models.CreateWebhookRequest) (*models.WebhookResponse, error)
+	GetWebhooks(ctx context.Context, ownerId uuid.UUID) ([]*models.WebhookResponse, error)
+	GetWebhookDeliveries(ctx context.Context, ownerId uuid.UUID, webhookId uuid.UUID) ([]*models.WebhookDeliveryResponse, error)
+	RedeliverWebhook(ctx context.Context, ownerId uuid.UUID, webhookId uuid.UUID, deliveryId uuid.UUID) (*models.WebhookDeliveryResponse, error)
+	SubscribeWalletEvents(ctx context.Context, principal *models.Principal, walletId uuid.UUID, lastEventId int64) (stream.Subscription, error)
 }
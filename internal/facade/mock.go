@@ -3,6 +3,8 @@ package facade
 import (
 	"context"
 	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/stream"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
@@ -13,8 +15,19 @@ type MockFacade struct {
 	mock.Mock
 }
 
-func (m *MockFacade) CreateTransaction(ctx context.Context, createTransactionRequest *models.CreateTransactionRequest) (*models.TransactionResponse, error) {
-	args := m.Called(ctx, createTransactionRequest)
+func (m *MockFacade) Authenticate(ctx context.Context, apiKey string) (*models.Principal, error) {
+	args := m.Called(ctx, apiKey)
+
+	var principal *models.Principal
+	if args.Get(0) != nil {
+		principal = args.Get(0).(*models.Principal)
+	}
+
+	return principal, args.Error(1)
+}
+
+func (m *MockFacade) CreateTransaction(ctx context.Context, principal *models.Principal, createTransactionRequest *models.CreateTransactionRequest) (*models.TransactionResponse, error) {
+	args := m.Called(ctx, principal, createTransactionRequest)
 
 	var resp *models.TransactionResponse
 	if args.Get(0) != nil {
@@ -46,8 +59,56 @@ func (m *MockFacade) GetAllTransactions(ctx context.Context) ([]*models.Transact
 	return resp, args.Error(1)
 }
 
-func (m *MockFacade) GetWallet(ctx context.Context, walletId uuid.UUID) (*models.WalletResponse, error) {
-	args := m.Called(ctx, walletId)
+func (m *MockFacade) GetTransactionsByOwner(ctx context.Context, ownerId uuid.UUID, count int) ([]*models.TransactionResponse, error) {
+	args := m.Called(ctx, ownerId, count)
+
+	var resp []*models.TransactionResponse
+	if args.Get(0) != nil {
+		resp = args.Get(0).([]*models.TransactionResponse)
+	}
+
+	return resp, args.Error(1)
+}
+
+func (m *MockFacade) GetAllTransactionsByOwner(ctx context.Context, ownerId uuid.UUID) ([]*models.TransactionResponse, error) {
+	args := m.Called(ctx, ownerId)
+
+	var resp []*models.TransactionResponse
+	if args.Get(0) != nil {
+		resp = args.Get(0).([]*models.TransactionResponse)
+	}
+
+	return resp, args.Error(1)
+}
+
+// Транзакции из первого значения передаются в fn по очереди, ошибка из второго возвращается после них
+func (m *MockFacade) StreamTransactions(ctx context.Context, filter models.TransactionFilter, fn func(*models.TransactionResponse) error) error {
+	args := m.Called(ctx, filter)
+
+	if args.Get(0) != nil {
+		for _, transaction := range args.Get(0).([]*models.TransactionResponse) {
+			if err := fn(transaction); err != nil {
+				return err
+			}
+		}
+	}
+
+	return args.Error(1)
+}
+
+func (m *MockFacade) GetWallet(ctx context.Context, principal *models.Principal, walletId uuid.UUID) (*models.WalletResponse, error) {
+	args := m.Called(ctx, principal, walletId)
+
+	var wallet *models.WalletResponse
+	if args.Get(0) != nil {
+		wallet = args.Get(0).(*models.WalletResponse)
+	}
+
+	return wallet, args.Error(1)
+}
+
+func (m *MockFacade) GetWalletBalanceAt(ctx context.Context, principal *models.Principal, walletId uuid.UUID, at time.Time) (*models.WalletResponse, error) {
+	args := m.Called(ctx, principal, walletId, at)
 
 	var wallet *models.WalletResponse
 	if args.Get(0) != nil {
@@ -56,3 +117,124 @@ func (m *MockFacade) GetWallet(ctx context.Context, walletId uuid.UUID) (*models
 
 	return wallet, args.Error(1)
 }
+
+func (m *MockFacade) GetBalanceHistory(ctx context.Context, principal *models.Principal, walletId uuid.UUID, from time.Time, to time.Time, interval models.BalanceInterval) (*models.BalanceHistoryResponse, error) {
+	args := m.Called(ctx, principal, walletId, from, to, interval)
+
+	var history *models.BalanceHistoryResponse
+	if args.Get(0) != nil {
+		history = args.Get(0).(*models.BalanceHistoryResponse)
+	}
+
+	return history, args.Error(1)
+}
+
+func (m *MockFacade) GetStatement(ctx context.Context, principal *models.Principal, walletId uuid.UUID, from time.Time, to time.Time) (*models.StatementResponse, error) {
+	args := m.Called(ctx, principal, walletId, from, to)
+
+	var statement *models.StatementResponse
+	if args.Get(0) != nil {
+		statement = args.Get(0).(*models.StatementResponse)
+	}
+
+	return statement, args.Error(1)
+}
+
+func (m *MockFacade) CreateAdjustment(ctx context.Context, createdBy uuid.UUID, request *models.CreateAdjustmentRequest) (*models.AdjustmentResponse, error) {
+	args := m.Called(ctx, createdBy, request)
+
+	var resp *models.AdjustmentResponse
+	if args.Get(0) != nil {
+		resp = args.Get(0).(*models.AdjustmentResponse)
+	}
+
+	return resp, args.Error(1)
+}
+
+func (m *MockFacade) GetAdjustments(ctx context.Context, status *models.AdjustmentStatus) ([]*models.AdjustmentResponse, error) {
+	args := m.Called(ctx, status)
+
+	var resp []*models.AdjustmentResponse
+	if args.Get(0) != nil {
+		resp = args.Get(0).([]*models.AdjustmentResponse)
+	}
+
+	return resp, args.Error(1)
+}
+
+func (m *MockFacade) ApproveAdjustment(ctx context.Context, adjustmentId uuid.UUID, reviewerId uuid.UUID) (*models.AdjustmentResponse, error) {
+	args := m.Called(ctx, adjustmentId, reviewerId)
+
+	var resp *models.AdjustmentResponse
+	if args.Get(0) != nil {
+		resp = args.Get(0).(*models.AdjustmentResponse)
+	}
+
+	return resp, args.Error(1)
+}
+
+func (m *MockFacade) RejectAdjustment(ctx context.Context, adjustmentId uuid.UUID, reviewerId uuid.UUID) (*models.AdjustmentResponse, error) {
+	args := m.Called(ctx, adjustmentId, reviewerId)
+
+	var resp *models.AdjustmentResponse
+	if args.Get(0) != nil {
+		resp = args.Get(0).(*models.AdjustmentResponse)
+	}
+
+	return resp, args.Error(1)
+}
+
+func (m *MockFacade) CreateWebhook(ctx context.Context, ownerId uuid.UUID, request *models.CreateWebhookRequest) (*models.WebhookResponse, error) {
+	args := m.Called(ctx, ownerId, request)
+
+	var resp *models.WebhookResponse
+	if args.Get(0) != nil {
+		resp = args.Get(0).(*models.WebhookResponse)
+	}
+
+	return resp, args.Error(1)
+}
+
+func (m *MockFacade) GetWebhooks(ctx context.Context, ownerId uuid.UUID) ([]*models.WebhookResponse, error) {
+	args := m.Called(ctx, ownerId)
+
+	var resp []*models.WebhookResponse
+	if args.Get(0) != nil {
+		resp = args.Get(0).([]*models.WebhookResponse)
+	}
+
+	return resp, args.Error(1)
+}
+
+func (m *MockFacade) GetWebhookDeliveries(ctx context.Context, ownerId uuid.UUID, webhookId uuid.UUID) ([]*models.WebhookDeliveryResponse, error) {
+	args := m.Called(ctx, ownerId, webhookId)
+
+	var resp []*models.WebhookDeliveryResponse
+	if args.Get(0) != nil {
+		resp = args.Get(0).([]*models.WebhookDeliveryResponse)
+	}
+
+	return resp, args.Error(1)
+}
+
+func (m *MockFacade) RedeliverWebhook(ctx context.Context, ownerId uuid.UUID, webhookId uuid.UUID, deliveryId uuid.UUID) (*models.WebhookDeliveryResponse, error) {
+	args := m.Called(ctx, ownerId, webhookId, deliveryId)
+
+	var resp *models.WebhookDeliveryResponse
+	if args.Get(0) != nil {
+		resp = args.Get(0).(*models.WebhookDeliveryResponse)
+	}
+
+	return resp, args.Error(1)
+}
+
+func (m *MockFacade) SubscribeWalletEvents(ctx context.Context, principal *models.Principal, walletId uuid.UUID, lastEventId int64) (stream.Subscription, error) {
+	args := m.Called(ctx, principal, walletId, lastEventId)
+
+	var subscription stream.Subscription
+	if args.Get(0) != nil {
+		subscription = args.Get(0).(stream.Subscription)
+	}
+
+	return subscription, args.Error(1)
+}
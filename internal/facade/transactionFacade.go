@@ -2,32 +2,85 @@ package facade
 
 import (
 	"context"
+	"errors"
+	"infotecstechtask/internal/adjustment"
+	"infotecstechtask/internal/auth"
+	"infotecstechtask/internal/balance"
+	"infotecstechtask/internal/metrics"
 	"infotecstechtask/internal/models"
 	"infotecstechtask/internal/payment"
+	"infotecstechtask/internal/statement"
+	"infotecstechtask/internal/stream"
+	"infotecstechtask/internal/tracing"
 	"infotecstechtask/internal/transaction"
 	"infotecstechtask/internal/wallet"
+	"infotecstechtask/internal/webhook"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 // Реализация интерфейса Facade
-// Содержит в себе WalletService, TransactionService и PaymentRepository
+// Содержит в себе AuthService, WalletService, TransactionService, AdjustmentService, WebhookService, StreamService, StatementService, BalanceService и PaymentRepository
 type TransactionFacade struct {
+	authService        auth.Service
 	walletService      wallet.Service
 	transactionService transaction.Service
+	adjustmentService  adjustment.Service
+	webhookService     webhook.Service
+	streamService      stream.Service
+	statementService   statement.Service
+	balanceService     balance.Service
 	paymentRepository  payment.Repository
 }
 
-func NewFacade(walletService wallet.Service, transactionService transaction.Service, paymentRepository payment.Repository) *TransactionFacade {
+func NewFacade(authService auth.Service, walletService wallet.Service, transactionService transaction.Service, adjustmentService adjustment.Service, webhookService webhook.Service, streamService stream.Service, statementService statement.Service, balanceService balance.Service, paymentRepository payment.Repository) *TransactionFacade {
 	return &TransactionFacade{
+		authService:        authService,
 		walletService:      walletService,
 		transactionService: transactionService,
+		adjustmentService:  adjustmentService,
+		webhookService:     webhookService,
+		streamService:      streamService,
+		statementService:   statementService,
+		balanceService:     balanceService,
 		paymentRepository:  paymentRepository,
 	}
 }
 
-func (f TransactionFacade) CreateTransaction(ctx context.Context, createTransactionRequest *models.CreateTransactionRequest) (*models.TransactionResponse, error) {
-	return f.paymentRepository.CreatePayment(ctx, createTransactionRequest)
+func (f TransactionFacade) Authenticate(ctx context.Context, apiKey string) (*models.Principal, error) {
+	return f.authService.Authenticate(ctx, apiKey)
+}
+
+// Итог перевода учитывается в метриках: отклоненные до создания транзакции переводы получают статус rejected
+func (f TransactionFacade) CreateTransaction(ctx context.Context, principal *models.Principal, createTransactionRequest *models.CreateTransactionRequest) (*models.TransactionResponse, error) {
+	ctx, span := tracing.Start(ctx, "TransactionFacade.CreateTransaction")
+	transaction, err := f.paymentRepository.CreatePayment(ctx, principal, createTransactionRequest)
+	tracing.End(span, err)
+	if err != nil {
+		metrics.Transfers.WithLabelValues("rejected", transferRejectReason(err)).Inc()
+		return nil, err
+	}
+
+	metrics.Transfers.WithLabelValues(string(transaction.Status), transaction.MessageCode).Inc()
+	return transaction, nil
+}
+
+// Причиной отказа служит код ошибки бизнес-логики, остальные ошибки объединяются в INTERNAL_ERROR,
+// чтобы количество рядов метрики не росло
+func transferRejectReason(err error) string {
+	switch {
+	case errors.Is(err, payment.ErrSenderWalletNotFound):
+		return string(models.CodeSenderWalletNotFound)
+	case errors.Is(err, payment.ErrRecipientWalletNotFound):
+		return string(models.CodeRecipientWalletNotFound)
+	case errors.Is(err, payment.ErrSenderAndRecipientSame):
+		return string(models.CodeSameWallet)
+	case errors.Is(err, wallet.ErrWalletNotOwned):
+		return string(models.CodeWalletNotOwned)
+	default:
+		return string(models.CodeInternalError)
+	}
 }
 
 func (f TransactionFacade) GetTransactions(ctx context.Context, count int) ([]*models.TransactionResponse, error) {
@@ -38,6 +91,66 @@ func (f TransactionFacade) GetAllTransactions(ctx context.Context) ([]*models.Tr
 	return f.transactionService.GetAllTransactions(ctx)
 }
 
-func (f TransactionFacade) GetWallet(ctx context.Context, walletId uuid.UUID) (*models.WalletResponse, error) {
-	return f.walletService.GetWallet(ctx, walletId)
+func (f TransactionFacade) GetTransactionsByOwner(ctx context.Context, ownerId uuid.UUID, count int) ([]*models.TransactionResponse, error) {
+	return f.transactionService.GetTransactionsByOwner(ctx, ownerId, count)
+}
+
+func (f TransactionFacade) GetAllTransactionsByOwner(ctx context.Context, ownerId uuid.UUID) ([]*models.TransactionResponse, error) {
+	return f.transactionService.GetAllTransactionsByOwner(ctx, ownerId)
+}
+
+func (f TransactionFacade) StreamTransactions(ctx context.Context, filter models.TransactionFilter, fn func(*models.TransactionResponse) error) error {
+	return f.transactionService.StreamTransactions(ctx, filter, fn)
+}
+
+func (f TransactionFacade) GetWallet(ctx context.Context, principal *models.Principal, walletId uuid.UUID) (*models.WalletResponse, error) {
+	return f.walletService.GetWallet(ctx, principal, walletId)
+}
+
+func (f TransactionFacade) GetWalletBalanceAt(ctx context.Context, principal *models.Principal, walletId uuid.UUID, at time.Time) (*models.WalletResponse, error) {
+	return f.balanceService.GetBalanceAt(ctx, principal, walletId, at)
+}
+
+func (f TransactionFacade) GetBalanceHistory(ctx context.Context, principal *models.Principal, walletId uuid.UUID, from time.Time, to time.Time, interval models.BalanceInterval) (*models.BalanceHistoryResponse, error) {
+	return f.balanceService.GetBalanceHistory(ctx, principal, walletId, from, to, interval)
+}
+
+func (f TransactionFacade) GetStatement(ctx context.Context, principal *models.Principal, walletId uuid.UUID, from time.Time, to time.Time) (*models.StatementResponse, error) {
+	return f.statementService.GetStatement(ctx, principal, walletId, from, to)
+}
+
+func (f TransactionFacade) CreateAdjustment(ctx context.Context, createdBy uuid.UUID, request *models.CreateAdjustmentRequest) (*models.AdjustmentResponse, error) {
+	return f.adjustmentService.CreateAdjustment(ctx, createdBy, request)
+}
+
+func (f TransactionFacade) GetAdjustments(ctx context.Context, status *models.AdjustmentStatus) ([]*models.AdjustmentResponse, error) {
+	return f.adjustmentService.GetAdjustments(ctx, status)
+}
+
+func (f TransactionFacade) ApproveAdjustment(ctx context.Context, adjustmentId uuid.UUID, reviewerId uuid.UUID) (*models.AdjustmentResponse, error) {
+	return f.adjustmentService.ApproveAdjustment(ctx, adjustmentId, reviewerId)
+}
+
+func (f TransactionFacade) RejectAdjustment(ctx context.Context, adjustmentId uuid.UUID, reviewerId uuid.UUID) (*models.AdjustmentResponse, error) {
+	return f.adjustmentService.RejectAdjustment(ctx, adjustmentId, reviewerId)
+}
+
+func (f TransactionFacade) CreateWebhook(ctx context.Context, ownerId uuid.UUID, request *models.CreateWebhookRequest) (*models.WebhookResponse, error) {
+	return f.webhookService.CreateWebhook(ctx, ownerId, request)
+}
+
+func (f TransactionFacade) GetWebhooks(ctx context.Context, ownerId uuid.UUID) ([]*models.WebhookResponse, error) {
+	return f.webhookService.GetWebhooks(ctx, ownerId)
+}
+
+func (f TransactionFacade) GetWebhookDeliveries(ctx context.Context, ownerId uuid.UUID, webhookId uuid.UUID) ([]*models.WebhookDeliveryResponse, error) {
+	return f.webhookService.GetDeliveries(ctx, ownerId, webhookId)
+}
+
+func (f TransactionFacade) RedeliverWebhook(ctx context.Context, ownerId uuid.UUID, webhookId uuid.UUID, deliveryId uuid.UUID) (*models.WebhookDeliveryResponse, error) {
+	return f.webhookService.Redeliver(ctx, ownerId, webhookId, deliveryId)
+}
+
+func (f TransactionFacade) SubscribeWalletEvents(ctx context.Context, principal *models.Principal, walletId uuid.UUID, lastEventId int64) (stream.Subscription, error) {
+	return f.streamService.SubscribeWallet(ctx, principal, walletId, lastEventId)
 }
@@ -0,0 +1,29 @@
+package facade
+
+import (
+	"errors"
+	"fmt"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/payment"
+	"infotecstechtask/internal/wallet"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferRejectReason(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected models.ErrorCode
+	}{
+		{payment.ErrSenderWalletNotFound, models.CodeSenderWalletNotFound},
+		{payment.ErrRecipientWalletNotFound, models.CodeRecipientWalletNotFound},
+		{payment.ErrSenderAndRecipientSame, models.CodeSameWallet},
+		{fmt.Errorf("failed to create payment: %w", wallet.ErrWalletNotOwned), models.CodeWalletNotOwned},
+		{errors.New("connection refused"), models.CodeInternalError},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, string(tt.expected), transferRejectReason(tt.err), tt.err.Error())
+	}
+}
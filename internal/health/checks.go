@@ -0,0 +1,38 @@
+package health
+
+import (
+	"context"
+	"fmt"
+)
+
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type MigrationVersioner interface {
+	MigrationVersion(ctx context.Context) (version int64, dirty bool, err error)
+}
+
+// Проверка доступности БД
+func DatabaseCheck(db Pinger) CheckFunc {
+	return db.Ping
+}
+
+// Проверка версии схемы БД
+// Более новая версия допускается, так как миграции применяются до обновления приложения и должны быть обратно совместимы
+func MigrationsCheck(db MigrationVersioner, expected int64) CheckFunc {
+	return func(ctx context.Context) error {
+		version, dirty, err := db.MigrationVersion(ctx)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("migration %d is dirty", version)
+		}
+		if version < expected {
+			return fmt.Errorf("schema version %d is behind expected %d", version, expected)
+		}
+
+		return nil
+	}
+}
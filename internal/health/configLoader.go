@@ -0,0 +1,11 @@
+package health
+
+import "time"
+
+// Структура, хранящая в себе параметры проверок
+// Значения собираются пакетом internal/config
+//
+// DrainDelay - время между переключением готовности в fail и остановкой http сервера
+type Config struct {
+	DrainDelay time.Duration
+}
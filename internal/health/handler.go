@@ -0,0 +1,32 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	LivenessPath  = "/healthz"
+	ReadinessPath = "/readyz"
+)
+
+// Функция регистрирует эндпоинты проверок
+// /healthz отвечает 200, пока процесс способен обрабатывать запросы, и не зависит от внешних систем,
+// /readyz выполняет все проверки и отвечает 503, если хотя бы одна из них не прошла
+func Register(router gin.IRoutes, checker *Checker) {
+	router.GET(LivenessPath, func(c *gin.Context) {
+		c.JSON(http.StatusOK, Report{Status: StatusOK})
+	})
+
+	router.GET(ReadinessPath, func(c *gin.Context) {
+		report := checker.Ready(c.Request.Context())
+
+		status := http.StatusOK
+		if report.Status != StatusOK {
+			status = http.StatusServiceUnavailable
+		}
+		c.Header("Cache-Control", "no-store")
+		c.JSON(status, report)
+	})
+}
@@ -0,0 +1,106 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Таймаут одной проверки готовности
+const checkTimeout = 2 * time.Second
+
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+var ErrShuttingDown = errors.New("Application is shutting down")
+
+type CheckFunc func(ctx context.Context) error
+
+// Результат одной проверки
+type CheckResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Итог проверки готовности, Status равен ok, только если успешны все проверки
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+type namedCheck struct {
+	name  string
+	check CheckFunc
+}
+
+// Набор проверок готовности приложения принимать трафик
+// После вызова Shutdown готовность всегда отрицательна, чтобы балансировщик перестал направлять запросы до остановки сервера
+type Checker struct {
+	checks       []namedCheck
+	shuttingDown atomic.Bool
+}
+
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// Функция добавляет проверку, должна вызываться до начала обработки запросов
+func (c *Checker) Add(name string, check CheckFunc) {
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+func (c *Checker) Shutdown() {
+	c.shuttingDown.Store(true)
+}
+
+// Функция выполняет все проверки параллельно
+func (c *Checker) Ready(ctx context.Context) Report {
+	report := Report{
+		Status: StatusOK,
+		Checks: make(map[string]CheckResult, len(c.checks)+1),
+	}
+
+	shutdown := CheckResult{Status: StatusOK, Duration: "0s"}
+	if c.shuttingDown.Load() {
+		shutdown = CheckResult{Status: StatusFail, Error: ErrShuttingDown.Error(), Duration: "0s"}
+		report.Status = StatusFail
+	}
+	report.Checks["shutdown"] = shutdown
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, nc := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := nc.check(checkCtx)
+			result := CheckResult{Status: StatusOK, Duration: time.Since(start).Round(time.Microsecond).String()}
+			if err != nil {
+				result.Status = StatusFail
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[nc.name] = result
+			if err != nil {
+				report.Status = StatusFail
+			}
+		}()
+	}
+	wg.Wait()
+
+	return report
+}
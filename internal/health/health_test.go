@@ -0,0 +1,99 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMigrations struct {
+	version int64
+	dirty   bool
+	err     error
+}
+
+func (f fakeMigrations) MigrationVersion(context.Context) (int64, bool, error) {
+	return f.version, f.dirty, f.err
+}
+
+func readiness(t *testing.T, checker *Checker) (int, Report) {
+	router := gin.New()
+	Register(router, checker)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+
+	var report Report
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+
+	return w.Code, report
+}
+
+func TestReadiness(t *testing.T) {
+	workers := NewWorkers()
+	workers.Register("relay")
+
+	checker := NewChecker()
+	checker.Add("database", func(context.Context) error { return nil })
+	checker.Add("migrations", MigrationsCheck(fakeMigrations{version: 8}, 8))
+	checker.Add("workers", workers.Check)
+
+	code, report := readiness(t, checker)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusOK, report.Checks["database"].Status)
+	assert.Equal(t, StatusFail, report.Checks["workers"].Status)
+	assert.Equal(t, "workers not running: relay", report.Checks["workers"].Error)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		workers.Run(ctx, "relay", func(ctx context.Context) {
+			close(started)
+			<-ctx.Done()
+		})
+	}()
+	<-started
+
+	code, report = readiness(t, checker)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusOK, report.Status)
+
+	checker.Shutdown()
+	code, report = readiness(t, checker)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusFail, report.Checks["shutdown"].Status)
+
+	cancel()
+	<-done
+	assert.Error(t, workers.Check(context.Background()))
+}
+
+func TestMigrationsCheck(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations fakeMigrations
+		wantErr    bool
+	}{
+		{name: "expected version", migrations: fakeMigrations{version: 8}},
+		{name: "newer version", migrations: fakeMigrations{version: 9}},
+		{name: "older version", migrations: fakeMigrations{version: 7}, wantErr: true},
+		{name: "dirty", migrations: fakeMigrations{version: 8, dirty: true}, wantErr: true},
+		{name: "query error", migrations: fakeMigrations{err: errors.New("boom")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := MigrationsCheck(tt.migrations, 8)(context.Background())
+			assert.Equal(t, tt.wantErr, err != nil)
+		})
+	}
+}
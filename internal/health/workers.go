@@ -0,0 +1,66 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Реестр фоновых процессов приложения
+// Процесс считается работающим с момента запуска через Run и до выхода из его функции
+type Workers struct {
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+func NewWorkers() *Workers {
+	return &Workers{
+		running: make(map[string]bool),
+	}
+}
+
+// Функция регистрирует процесс, чтобы проверка видела его, даже если он еще не запущен
+func (w *Workers) Register(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.running[name]; !ok {
+		w.running[name] = false
+	}
+}
+
+// Функция запускает процесс и отмечает его работающим, пока run не вернет управление
+func (w *Workers) Run(ctx context.Context, name string, run func(ctx context.Context)) {
+	w.set(name, true)
+	defer w.set(name, false)
+
+	run(ctx)
+}
+
+// Проверка готовности: ошибка, если хотя бы один зарегистрированный процесс не работает
+func (w *Workers) Check(context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var stopped []string
+	for name, running := range w.running {
+		if !running {
+			stopped = append(stopped, name)
+		}
+	}
+	if len(stopped) == 0 {
+		return nil
+	}
+
+	sort.Strings(stopped)
+	return fmt.Errorf("workers not running: %s", strings.Join(stopped, ", "))
+}
+
+func (w *Workers) set(name string, running bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.running[name] = running
+}
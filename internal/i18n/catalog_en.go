@@ -0,0 +1,105 @@
+package i18n
+
+// Каталог сообщений на английском
+// Тексты ошибок предметной области совпадают с текстами ошибок сервисов, чтобы ответы v1 не изменились
+var catalogEn = map[string]string{
+	// Ошибки API, ключ - код ошибки
+	"VALIDATION_FAILED":           "Validation failed",
+	"INVALID_JSON":                "Invalid JSON body: %s",
+	"INVALID_HEADER":              "Invalid %s header",
+	"UNSUPPORTED_MEDIA_TYPE":      "Only application/json content type is accepted for POST requests",
+	"API_KEY_REQUIRED":            "API key is required",
+	"INVALID_API_KEY":             "Invalid API key",
+	"INSUFFICIENT_ROLE":           "Insufficient role",
+	"RATE_LIMITED":                "Too many requests",
+	"REQUEST_TIMEOUT":             "Request timed out",
+	"INTERNAL_ERROR":              "Internal error",
+	"SENDER_WALLET_NOT_FOUND":     "Sender wallet not found",
+	"RECIPIENT_WALLET_NOT_FOUND":  "Recipient wallet not found",
+	"SAME_WALLET":                 "Sender and recipient are the same",
+	"WALLET_NOT_FOUND":            "Wallet not found",
+	"WALLET_NOT_OWNED":            "Wallet does not belong to the caller",
+	"ADJUSTMENT_NOT_FOUND":        "Adjustment not found",
+	"ADJUSTMENT_SELF_REVIEW":      "Adjustment must be reviewed by a different operator",
+	"ADJUSTMENT_ALREADY_REVIEWED": "Adjustment has already been reviewed",
+	"INSUFFICIENT_FUNDS":          "Wallet does not have enough balance for debit",
+	"ADJUSTMENT_AMOUNT_TOO_SMALL": "Adjustment amount must be at least 0.01",
+	"WEBHOOK_NOT_FOUND":           "Webhook not found",
+	"DELIVERY_NOT_FOUND":          "Webhook delivery not found",
+	"WEBHOOK_URL_NOT_ALLOWED":     "Webhook URL must use https and point to a public address",
+	"STATEMENT_PERIOD_TOO_LONG":   "Statement period must not exceed 366 days",
+	"BALANCE_HISTORY_TOO_LONG":    "Balance history must not exceed 1000 points",
+	"STREAM_CLOSED":               "Event stream is shutting down",
+	"ROUTE_NOT_FOUND":             "Route %s not found",
+
+	// Уточнения ошибок
+	"params.invalid_path":        "Invalid path parameters",
+	"params.invalid_query":       "Invalid query parameters",
+	"params.negative_count":      "Field count must not be negative",
+	"params.invalid_id":          "Field id must be a valid UUID",
+	"header.invalid_consistency": "Invalid %s header, expected strong or eventual",
+	"bind.empty_body":            "body is empty",
+	"bind.unexpected_end":        "unexpected end of input",
+	"bind.syntax_error":          "syntax error at offset %d",
+	"bind.field_type":            "field %s must not be a %s",
+	"bind.body_type":             "body must not be a %s",
+	"bind.invalid_number":        "value %q is not a valid number",
+	"bind.invalid_time":          "value %q is not a valid RFC 3339 time",
+	"request.canceled":           "Request canceled",
+
+	// Ошибки WebSocket-ленты и потоков событий
+	"feed.invalid_message":        "Invalid message",
+	"feed.wallet_ids_count":       "Field wallet_ids must contain from 1 to %d items",
+	"feed.wallet_ids_uuid":        "Field wallet_ids must contain valid UUIDs",
+	"feed.invalid_wallet_id":      "Field wallet_ids contains invalid UUID %q",
+	"feed.invalid_action":         "Field action must be one of: %s",
+	"feed.too_many_subscriptions": "Too many subscriptions",
+	"feed.subscribe_failed":       "Failed to subscribe",
+	"feed.slow_consumer":          "Slow consumer",
+	"feed.stream_interrupted":     "Event stream interrupted",
+	"feed.resubscribe":            "Event stream interrupted, resubscribe with last_event_id",
+
+	// Ошибки валидации, ключ - тег валидатора
+	"validation.required":      "Field is required",
+	"validation.required_with": "Field is required when %s is set",
+	"validation.uuid":          "Field must be a valid UUID",
+	"validation.url":           "Field must be a valid URL",
+	"validation.email":         "Field must be a valid email",
+	"validation.numeric":       "Field must be numeric",
+	"validation.min":           "Field must be greater than %s",
+	"validation.gt":            "Field must be greater than %s",
+	"validation.gte":           "Field must be at least %s",
+	"validation.gtfield":       "Field must be greater than field %s",
+	"validation.max":           "Field must be at most %s",
+	"validation.lt":            "Field must be less than %s",
+	"validation.lte":           "Field must be at most %s",
+	"validation.len":           "Field must have length %s",
+	"validation.oneof":         "Field must be one of: %s",
+	"validation.unknown":       "Field failed the %s check",
+
+	// Сообщения транзакций, ключ - код сообщения в transactions.message
+	"TRANSACTION_PENDING":            "Transaction pending",
+	"TRANSACTION_COMPLETED":          "Transaction completed",
+	"TRANSACTION_FAILED":             "Transaction failed",
+	"SENDER_NOT_HAVE_ENOUGH_BALANCE": "Sender does not have enough balance",
+
+	// Подписи выписки по кошельку
+	"statement.title":           "Wallet statement",
+	"statement.wallet":          "Wallet",
+	"statement.period":          "Period",
+	"statement.opening_balance": "Opening balance",
+	"statement.total_in":        "Total in",
+	"statement.total_out":       "Total out",
+	"statement.closing_balance": "Closing balance",
+	"statement.date":            "Date",
+	"statement.type":            "Type",
+	"statement.counterparty":    "Counterparty",
+	"statement.amount":          "Amount",
+	"statement.balance":         "Balance",
+	"statement.message":         "Message",
+	"statement.transaction":     "Transaction",
+	"statement.type.transfer":   "Transfer",
+	"statement.type.adjustment": "Adjustment",
+	"statement.empty":           "No transactions in the period",
+	"statement.generated_at":    "Generated at",
+}
@@ -0,0 +1,104 @@
+package i18n
+
+// Каталог сообщений на русском
+var catalogRu = map[string]string{
+	// Ошибки API, ключ - код ошибки
+	"VALIDATION_FAILED":           "Ошибка валидации",
+	"INVALID_JSON":                "Некорректное JSON тело запроса: %s",
+	"INVALID_HEADER":              "Некорректный заголовок %s",
+	"UNSUPPORTED_MEDIA_TYPE":      "POST запросы принимаются только с типом содержимого application/json",
+	"API_KEY_REQUIRED":            "Требуется API ключ",
+	"INVALID_API_KEY":             "Неизвестный API ключ",
+	"INSUFFICIENT_ROLE":           "Недостаточно прав",
+	"RATE_LIMITED":                "Слишком много запросов",
+	"REQUEST_TIMEOUT":             "Истекло время ожидания запроса",
+	"INTERNAL_ERROR":              "Внутренняя ошибка",
+	"SENDER_WALLET_NOT_FOUND":     "Кошелек отправителя не найден",
+	"RECIPIENT_WALLET_NOT_FOUND":  "Кошелек получателя не найден",
+	"SAME_WALLET":                 "Отправитель и получатель совпадают",
+	"WALLET_NOT_FOUND":            "Кошелек не найден",
+	"WALLET_NOT_OWNED":            "Кошелек не принадлежит владельцу ключа",
+	"ADJUSTMENT_NOT_FOUND":        "Заявка на корректировку не найдена",
+	"ADJUSTMENT_SELF_REVIEW":      "Заявку должен рассмотреть другой оператор",
+	"ADJUSTMENT_ALREADY_REVIEWED": "Заявка уже рассмотрена",
+	"INSUFFICIENT_FUNDS":          "На балансе кошелька недостаточно средств для списания",
+	"ADJUSTMENT_AMOUNT_TOO_SMALL": "Сумма корректировки должна быть не меньше 0.01",
+	"WEBHOOK_NOT_FOUND":           "Подписка не найдена",
+	"DELIVERY_NOT_FOUND":          "Доставка не найдена",
+	"WEBHOOK_URL_NOT_ALLOWED":     "Адрес подписки должен использовать https и указывать на публичный хост",
+	"STATEMENT_PERIOD_TOO_LONG":   "Период выписки не должен превышать 366 дней",
+	"BALANCE_HISTORY_TOO_LONG":    "Ряд баланса не должен превышать 1000 точек",
+	"STREAM_CLOSED":               "Поток событий закрывается",
+	"ROUTE_NOT_FOUND":             "Маршрут %s не найден",
+
+	// Уточнения ошибок
+	"params.invalid_path":        "Некорректные параметры пути",
+	"params.invalid_query":       "Некорректные параметры запроса",
+	"params.negative_count":      "Поле count не может быть отрицательным",
+	"params.invalid_id":          "Поле id должно быть корректным UUID",
+	"header.invalid_consistency": "Некорректный заголовок %s, ожидается strong или eventual",
+	"bind.empty_body":            "тело запроса пустое",
+	"bind.unexpected_end":        "неожиданный конец данных",
+	"bind.syntax_error":          "синтаксическая ошибка в позиции %d",
+	"bind.field_type":            "поле %s не может иметь тип %s",
+	"bind.body_type":             "тело запроса не может иметь тип %s",
+	"bind.invalid_number":        "значение %q не является числом",
+	"bind.invalid_time":          "значение %q не является временем в формате RFC 3339",
+	"request.canceled":           "Запрос отменен",
+
+	// Ошибки WebSocket-ленты и потоков событий
+	"feed.invalid_message":        "Некорректное сообщение",
+	"feed.wallet_ids_count":       "Поле wallet_ids должно содержать от 1 до %d элементов",
+	"feed.wallet_ids_uuid":        "Поле wallet_ids должно содержать корректные UUID",
+	"feed.invalid_wallet_id":      "Поле wallet_ids содержит некорректный UUID %q",
+	"feed.invalid_action":         "Поле action должно принимать одно из значений: %s",
+	"feed.too_many_subscriptions": "Слишком много подписок",
+	"feed.subscribe_failed":       "Не удалось оформить подписку",
+	"feed.slow_consumer":          "Клиент не успевает читать события",
+	"feed.stream_interrupted":     "Поток событий прерван",
+	"feed.resubscribe":            "Поток событий прерван, подпишитесь повторно с last_event_id",
+
+	// Ошибки валидации, ключ - тег валидатора
+	"validation.required":      "Поле обязательно",
+	"validation.required_with": "Поле обязательно, если задано поле %s",
+	"validation.uuid":          "Поле должно быть корректным UUID",
+	"validation.url":           "Поле должно быть корректным URL",
+	"validation.email":         "Поле должно быть корректным email",
+	"validation.numeric":       "Поле должно быть числом",
+	"validation.min":           "Значение поля должно быть не меньше %s",
+	"validation.gt":            "Значение поля должно быть больше %s",
+	"validation.gte":           "Значение поля должно быть не меньше %s",
+	"validation.gtfield":       "Значение поля должно быть больше поля %s",
+	"validation.max":           "Значение поля должно быть не больше %s",
+	"validation.lt":            "Значение поля должно быть меньше %s",
+	"validation.lte":           "Значение поля должно быть не больше %s",
+	"validation.len":           "Длина поля должна быть равна %s",
+	"validation.oneof":         "Поле должно принимать одно из значений: %s",
+	"validation.unknown":       "Поле не прошло проверку %s",
+
+	// Сообщения транзакций, ключ - код сообщения в transactions.message
+	"TRANSACTION_PENDING":            "Транзакция в обработке",
+	"TRANSACTION_COMPLETED":          "Транзакция выполнена",
+	"TRANSACTION_FAILED":             "Транзакция не выполнена",
+	"SENDER_NOT_HAVE_ENOUGH_BALANCE": "У отправителя недостаточно средств",
+
+	// Подписи выписки по кошельку
+	"statement.title":           "Выписка по кошельку",
+	"statement.wallet":          "Кошелек",
+	"statement.period":          "Период",
+	"statement.opening_balance": "Входящий остаток",
+	"statement.total_in":        "Зачислено",
+	"statement.total_out":       "Списано",
+	"statement.closing_balance": "Исходящий остаток",
+	"statement.date":            "Дата",
+	"statement.type":            "Тип",
+	"statement.counterparty":    "Контрагент",
+	"statement.amount":          "Сумма",
+	"statement.balance":         "Остаток",
+	"statement.message":         "Сообщение",
+	"statement.transaction":     "Транзакция",
+	"statement.type.transfer":   "Перевод",
+	"statement.type.adjustment": "Корректировка",
+	"statement.empty":           "Нет операций за период",
+	"statement.generated_at":    "Сформирована",
+}
@@ -0,0 +1,81 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// Язык сообщений
+type Language string
+
+const (
+	English Language = "en"
+	Russian Language = "ru"
+)
+
+// Язык по умолчанию: на нем отвечают клиентам без заголовка Accept-Language,
+// на нем же сообщения записываются в события и webhook
+const DefaultLanguage = English
+
+var catalogs = map[Language]map[string]string{
+	English: catalogEn,
+	Russian: catalogRu,
+}
+
+// Первый язык в списке используется, если ни один из запрошенных клиентом не поддерживается
+var matcher = language.NewMatcher([]language.Tag{language.English, language.Russian})
+
+type contextKey struct{}
+
+// Функция выбирает язык по значению заголовка Accept-Language (RFC 9110) с учетом весов q
+func Match(acceptLanguage string) Language {
+	tag, _ := language.MatchStrings(matcher, acceptLanguage)
+	base, _ := tag.Base()
+	if _, ok := catalogs[Language(base.String())]; ok {
+		return Language(base.String())
+	}
+
+	return DefaultLanguage
+}
+
+// Функция возвращает контекст с выбранным языком
+func WithLanguage(ctx context.Context, lang Language) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// Функция возвращает язык из контекста, если язык не выбран - язык по умолчанию
+func FromContext(ctx context.Context) Language {
+	if lang, ok := ctx.Value(contextKey{}).(Language); ok {
+		return lang
+	}
+
+	return DefaultLanguage
+}
+
+// Функция возвращает сообщение по ключу на указанном языке
+// Если в каталоге языка сообщения нет, используется язык по умолчанию
+func Lookup(lang Language, key string, args ...any) (string, bool) {
+	message, ok := catalogs[lang][key]
+	if !ok {
+		message, ok = catalogs[DefaultLanguage][key]
+	}
+	if !ok {
+		return "", false
+	}
+	if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+
+	return message, true
+}
+
+// Функция возвращает сообщение по ключу на указанном языке, неизвестный ключ возвращается как есть
+func Translate(lang Language, key string, args ...any) string {
+	if message, ok := Lookup(lang, key, args...); ok {
+		return message
+	}
+
+	return key
+}
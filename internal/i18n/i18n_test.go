@@ -0,0 +1,59 @@
+package i18n
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var formatVerb = regexp.MustCompile(`%[a-z]`)
+
+func TestCatalogsHaveSameKeysAndVerbs(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for key, message := range catalogEn {
+			translated, ok := catalog[key]
+			if assert.True(t, ok, "%s: message %s is missing", lang, key) {
+				assert.Equal(t, formatVerb.FindAllString(message, -1), formatVerb.FindAllString(translated, -1), "%s: message %s", lang, key)
+			}
+		}
+		for key := range catalog {
+			assert.Contains(t, catalogEn, key, "%s: message %s is missing in en catalog", lang, key)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		expected       Language
+	}{
+		{acceptLanguage: "", expected: English},
+		{acceptLanguage: "ru", expected: Russian},
+		{acceptLanguage: "ru-RU,ru;q=0.9,en-US;q=0.8", expected: Russian},
+		{acceptLanguage: "en-US,en;q=0.9,ru;q=0.8", expected: English},
+		{acceptLanguage: "de, ru;q=0.5", expected: Russian},
+		{acceptLanguage: "de", expected: English},
+		{acceptLanguage: "*", expected: English},
+		{acceptLanguage: "not a language", expected: English},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Match(tt.acceptLanguage))
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	assert.Equal(t, "Кошелек не найден", Translate(Russian, "WALLET_NOT_FOUND"))
+	assert.Equal(t, "Field must be greater than 1", Translate(English, "validation.min", "1"))
+	assert.Equal(t, "Wallet not found", Translate(Language("de"), "WALLET_NOT_FOUND"))
+	assert.Equal(t, "UNKNOWN_KEY", Translate(Russian, "UNKNOWN_KEY"))
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, DefaultLanguage, FromContext(context.Background()))
+	assert.Equal(t, Russian, FromContext(WithLanguage(context.Background(), Russian)))
+}
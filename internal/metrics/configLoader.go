@@ -0,0 +1,9 @@
+package metrics
+
+// Структура, хранящая в себе параметры отдачи метрик
+// Значения собираются пакетом internal/config
+//
+// Если Addr пустой, /metrics отдается основным http сервером, иначе отдельным сервером на этом адресе
+type Config struct {
+	Addr string
+}
@@ -0,0 +1,93 @@
+package metrics
+
+import (
+	"infotecstechtask/pkg/database"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Интерфейс источника статистики БД, реализуется database.Client
+type DatabaseStats interface {
+	Stat() *pgxpool.Stat
+	RetryStats() database.RetryStats
+	ReplicaStats() []database.ReplicaStat
+}
+
+// Коллектор статистики пула соединений и повторов транзакций
+// Значения читаются из клиента в момент сбора метрик
+type DatabaseCollector struct {
+	stats DatabaseStats
+
+	acquiredConns        *prometheus.Desc
+	idleConns            *prometheus.Desc
+	totalConns           *prometheus.Desc
+	maxConns             *prometheus.Desc
+	acquireCount         *prometheus.Desc
+	emptyAcquireCount    *prometheus.Desc
+	canceledAcquireCount *prometheus.Desc
+	acquireDuration      *prometheus.Desc
+	txRetries            *prometheus.Desc
+	replicaHealthy       *prometheus.Desc
+	replicaLag           *prometheus.Desc
+}
+
+func NewDatabaseCollector(stats DatabaseStats) *DatabaseCollector {
+	desc := func(name string, help string, labels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", name), help, labels, nil)
+	}
+
+	return &DatabaseCollector{
+		stats:                stats,
+		acquiredConns:        desc("pool_acquired_connections", "Количество занятых соединений"),
+		idleConns:            desc("pool_idle_connections", "Количество свободных соединений"),
+		totalConns:           desc("pool_total_connections", "Общее количество соединений"),
+		maxConns:             desc("pool_max_connections", "Максимальный размер пула"),
+		acquireCount:         desc("pool_acquire_total", "Количество успешных получений соединения"),
+		emptyAcquireCount:    desc("pool_empty_acquire_total", "Количество получений соединения, которым пришлось ждать"),
+		canceledAcquireCount: desc("pool_canceled_acquire_total", "Количество ожиданий соединения, отмененных контекстом"),
+		acquireDuration:      desc("pool_acquire_wait_seconds_total", "Суммарное время ожидания соединения"),
+		txRetries:            desc("transaction_retries_total", "Количество повторов транзакций", "reason"),
+		replicaHealthy:       desc("replica_healthy", "Доступность реплики для чтения по последней проверке", "host"),
+		replicaLag:           desc("replica_lag_seconds", "Отставание реплики от основной БД", "host"),
+	}
+}
+
+func (c *DatabaseCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquireCount
+	ch <- c.emptyAcquireCount
+	ch <- c.canceledAcquireCount
+	ch <- c.acquireDuration
+	ch <- c.txRetries
+	ch <- c.replicaHealthy
+	ch <- c.replicaLag
+}
+
+func (c *DatabaseCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.stats.Stat()
+	retries := c.stats.RetryStats()
+
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquireCount, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquireCount, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.txRetries, prometheus.CounterValue, float64(retries.SerializationFailures), "serialization_failure")
+	ch <- prometheus.MustNewConstMetric(c.txRetries, prometheus.CounterValue, float64(retries.Deadlocks), "deadlock")
+
+	for _, replica := range c.stats.ReplicaStats() {
+		healthy := 0.0
+		if replica.Healthy {
+			healthy = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.replicaHealthy, prometheus.GaugeValue, healthy, replica.Host)
+		ch <- prometheus.MustNewConstMetric(c.replicaLag, prometheus.GaugeValue, replica.Lag.Seconds(), replica.Host)
+	}
+}
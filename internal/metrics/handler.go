@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const Path = "/metrics"
+
+// Функция возвращает обработчик, отдающий метрики в текстовом формате Prometheus
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Функция создает отдельный http сервер для метрик
+func NewServer(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle(Path, Handler())
+
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "wallet"
+
+// Метрики HTTP запросов
+// route - шаблон пути gin (например /api/wallet/:walletId/balance), чтобы количество рядов не зависело от параметров
+var (
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Количество обработанных HTTP запросов",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Время обработки HTTP запросов",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+)
+
+// Метрики переводов
+// reason - код сообщения транзакции или код ошибки отказа (models.ErrorCode), набор значений ограничен
+var Transfers = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "transfers_total",
+	Help:      "Количество переводов по итоговому статусу и причине",
+}, []string{"status", "reason"})
+
+var RateLimitRejections = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "rate_limit_rejections_total",
+	Help:      "Количество запросов, отклоненных рейт лимитером",
+})
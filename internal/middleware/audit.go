@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"infotecstechtask/internal/audit"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Миддлвар для сбора метаданных журнала аудита
+// Сохраняет в контексте запроса владельца ключа, IP клиента и идентификатор запроса
+// Должен использоваться после миддлваров RequestID и Authentication
+func AuditMetadata() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := c.MustGet("principal").(*models.Principal)
+
+		ctx := audit.WithMetadata(c.Request.Context(), models.AuditMetadata{
+			ActorID:   principal.ID,
+			ActorRole: principal.Role,
+			IP:        c.ClientIP(),
+			RequestID: logger.RequestID(c.Request.Context()),
+		})
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
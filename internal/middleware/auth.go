@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"infotecstechtask/internal/auth"
+	"infotecstechtask/internal/models"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Интерфейс для аутентификации вызывающей стороны по API ключу
+type Authenticator interface {
+	Authenticate(ctx context.Context, apiKey string) (*models.Principal, error)
+}
+
+// Миддлвар для аутентификации
+// Ключ принимается из заголовка X-API-Key или Authorization: Bearer <key>
+// Найденный владелец ключа сохраняется в контексте запроса под ключом principal
+func Authentication(authenticator Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey := extractAPIKey(c)
+		if apiKey == "" {
+			AbortWithError(c, http.StatusUnauthorized, models.CodeAPIKeyRequired)
+			return
+		}
+
+		principal, err := authenticator.Authenticate(c.Request.Context(), apiKey)
+		if err != nil {
+			if errors.Is(err, auth.ErrInvalidAPIKey) {
+				AbortWithError(c, http.StatusUnauthorized, models.CodeInvalidAPIKey)
+				return
+			}
+			Abort(c, http.StatusInternalServerError, InternalError, nil)
+			return
+		}
+
+		c.Set("principal", principal)
+		c.Next()
+	}
+}
+
+// Миддлвар для проверки роли
+// Пропускает запрос дальше, только если роль вызывающей стороны не ниже требуемой
+// Должен использоваться после миддлвара Authentication
+func RequireRole(role models.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := c.MustGet("principal").(*models.Principal)
+
+		if !principal.Role.Allows(role) {
+			AbortWithError(c, http.StatusForbidden, models.CodeInsufficientRole)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Функция для извлечения API ключа из заголовков запроса
+func extractAPIKey(c *gin.Context) string {
+	if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+		return apiKey
+	}
+
+	authorization := c.GetHeader("Authorization")
+	if token, ok := strings.CutPrefix(authorization, "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+
+	return ""
+}
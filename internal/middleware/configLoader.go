@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"strings"
+	"time"
+)
+
+// Структура, хранящая в себе таймауты обработки запросов
+// Routes задает таймауты отдельных маршрутов по ключу "METHOD /full/path", нулевое значение отключает таймаут
+// Значения собираются пакетом internal/config из файла конфигурации, переменных окружения и флагов
+type TimeoutConfig struct {
+	Default time.Duration
+	Routes  map[string]time.Duration
+}
+
+// Функция задает таймаут маршрута
+func (c *TimeoutConfig) SetRoute(method string, path string, timeout time.Duration) {
+	if c.Routes == nil {
+		c.Routes = make(map[string]time.Duration)
+	}
+	c.Routes[routeKey(method, path)] = timeout
+}
+
+// Функция отключает таймаут для маршрута, например для потоков, которые живут до закрытия соединения клиентом
+func (c *TimeoutConfig) Disable(method string, path string) {
+	c.SetRoute(method, path, 0)
+}
+
+// Функция возвращает таймаут маршрута, если он не задан отдельно - таймаут по умолчанию
+func (c TimeoutConfig) Route(method string, path string) time.Duration {
+	if timeout, ok := c.Routes[routeKey(method, path)]; ok {
+		return timeout
+	}
+	return c.Default
+}
+
+func routeKey(method string, path string) string {
+	return strings.ToUpper(method) + " " + strings.TrimSpace(path)
+}
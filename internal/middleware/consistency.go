@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"infotecstechtask/internal/models"
+	"infotecstechtask/pkg/database"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Заголовок, которым клиент выбирает согласованность чтения
+// "strong" - чтение с основной БД, клиент гарантированно видит результат своих предыдущих запросов (read-your-writes)
+// "eventual" или отсутствие заголовка - чтение может выполняться на реплике, которая отстает не больше чем на max_replica_lag
+const ReadConsistencyHeader = "X-Read-Consistency"
+
+const (
+	ReadConsistencyStrong   = "strong"
+	ReadConsistencyEventual = "eventual"
+)
+
+// Миддлвар переносит выбранную клиентом согласованность чтения в контекст запроса
+func ReadConsistency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch strings.ToLower(c.GetHeader(ReadConsistencyHeader)) {
+		case "", ReadConsistencyEventual:
+		case ReadConsistencyStrong:
+			c.Request = c.Request.WithContext(database.WithPrimary(c.Request.Context()))
+		default:
+			AbortWithMessage(c, http.StatusBadRequest, models.CodeInvalidHeader, "header.invalid_consistency", ReadConsistencyHeader)
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"infotecstechtask/pkg/database"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadConsistency(t *testing.T) {
+	tests := []struct {
+		name            string
+		header          string
+		expectedStatus  int
+		expectedPrimary bool
+	}{
+		{name: "replicas by default", header: "", expectedStatus: http.StatusOK},
+		{name: "eventual", header: "eventual", expectedStatus: http.StatusOK},
+		{name: "strong", header: "Strong", expectedStatus: http.StatusOK, expectedPrimary: true},
+		{name: "invalid value", header: "linearizable", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var primary bool
+			router := gin.New()
+			router.Use(ReadConsistency())
+			router.GET("/", func(c *gin.Context) {
+				primary = database.PrimaryRequired(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(ReadConsistencyHeader, tt.header)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedPrimary, primary)
+		})
+	}
+}
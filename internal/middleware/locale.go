@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"infotecstechtask/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Миддлвар выбирает язык сообщений по заголовку Accept-Language и сохраняет его в контексте запроса
+// Выбранный язык возвращается в заголовке Content-Language. Должен использоваться до миддлваров,
+// которые могут прервать запрос, чтобы их ошибки тоже отдавались на языке клиента
+func Localization() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.Match(c.GetHeader("Accept-Language"))
+
+		c.Header("Content-Language", string(lang))
+		c.Header("Vary", "Accept-Language")
+		c.Request = c.Request.WithContext(i18n.WithLanguage(c.Request.Context(), lang))
+
+		c.Next()
+	}
+}
+
+// Функция возвращает сообщение по ключу на языке запроса
+func Translate(c *gin.Context, key string, args ...any) string {
+	return i18n.Translate(i18n.FromContext(c.Request.Context()), key, args...)
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+type localizedRequest struct {
+	Amount float64 `json:"amount" validate:"required,gt=0"`
+	Kind   string  `json:"kind" validate:"omitempty,alpha"`
+}
+
+func TestLocalization(t *testing.T) {
+	tests := []struct {
+		name            string
+		acceptLanguage  string
+		body            string
+		expectedLang    string
+		expectedProblem string
+	}{
+		{
+			name:         "default language",
+			body:         `{"amount":-1}`,
+			expectedLang: "en",
+			expectedProblem: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Validation failed","instance":"/api/v2/send",` +
+				`"code":"VALIDATION_FAILED","errors":[{"field":"amount","message":"Field must be greater than 0"}]}`,
+		},
+		{
+			name:           "russian",
+			acceptLanguage: "ru-RU,ru;q=0.9,en;q=0.8",
+			body:           `{"amount":-1}`,
+			expectedLang:   "ru",
+			expectedProblem: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Ошибка валидации","instance":"/api/v2/send",` +
+				`"code":"VALIDATION_FAILED","errors":[{"field":"amount","message":"Значение поля должно быть больше 0"}]}`,
+		},
+		{
+			name:           "tag without message",
+			acceptLanguage: "ru",
+			body:           `{"amount":1,"kind":"42"}`,
+			expectedLang:   "ru",
+			expectedProblem: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Ошибка валидации","instance":"/api/v2/send",` +
+				`"code":"VALIDATION_FAILED","errors":[{"field":"kind","message":"Поле не прошло проверку alpha"}]}`,
+		},
+		{
+			name:           "bind error reason",
+			acceptLanguage: "ru",
+			body:           `{"amount":"1"}`,
+			expectedLang:   "ru",
+			expectedProblem: `{"type":"about:blank","title":"Bad Request","status":400,` +
+				`"detail":"Некорректное JSON тело запроса: поле amount не может иметь тип string","instance":"/api/v2/send","code":"INVALID_JSON"}`,
+		},
+	}
+
+	router := gin.New()
+	router.Use(Localization())
+	router.POST("/api/v2/send", JSONValidation(localizedRequest{}, validator.New()), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/api/v2/send", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tt.expectedLang, w.Header().Get("Content-Language"))
+			assert.JSONEq(t, tt.expectedProblem, w.Body.String())
+		})
+	}
+}
+
+func TestLocalizationLegacyBody(t *testing.T) {
+	router := gin.New()
+	router.Use(Localization(), ReadConsistency())
+	router.GET("/api/transactions", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/transactions", nil)
+	req.Header.Set("Accept-Language", "ru")
+	req.Header.Set(ReadConsistencyHeader, "always")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, `{"Error":"Некорректный заголовок X-Read-Consistency, ожидается strong или eventual"}`, w.Body.String())
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Миддлвар для логирования запросов, заменяет gin.Logger
+// Запросы, завершившиеся ошибкой сервера, логируются с уровнем error, ошибкой клиента - warn
+// Должен использоваться после миддлвара RequestID, чтобы запись содержала идентификатор запроса
+func Logger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		slog.LogAttrs(c.Request.Context(), level, "http request",
+			slog.String("method", c.Request.Method),
+			slog.String("route", c.FullPath()),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("size", c.Writer.Size()),
+		)
+	}
+}
+
+// Миддлвар для восстановления после паники, заменяет gin.Recovery
+// Паника логируется вместе со стеком, клиент получает ответ 500
+// http.ErrAbortHandler пробрасывается дальше: им обработчик разрывает соединение, если ответ уже начат
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		if recovered == http.ErrAbortHandler {
+			panic(recovered)
+		}
+		slog.ErrorContext(c.Request.Context(), "panic recovered",
+			slog.String("error", fmt.Sprint(recovered)),
+			slog.String("stack", string(debug.Stack())),
+		)
+		Abort(c, http.StatusInternalServerError, InternalError, nil)
+	})
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"infotecstechtask/internal/metrics"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsUsesRouteTemplate(t *testing.T) {
+	router := gin.New()
+	router.Use(Metrics())
+	router.GET("/api/wallet/:walletId/balance", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	before := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/api/wallet/:walletId/balance", "200"))
+	beforeUnmatched := testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, "unmatched", "404"))
+
+	for _, path := range []string{"/api/wallet/1/balance", "/api/wallet/2/balance", "/unknown"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Equal(t, before+2, testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, "/api/wallet/:walletId/balance", "200")))
+	assert.Equal(t, beforeUnmatched+1, testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(http.MethodGet, "unmatched", "404")))
+}
@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"infotecstechtask/internal/models"
+	"infotecstechtask/pkg/logger"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ProblemContentType = "application/problem+json"
+
+	problemType = "about:blank"
+)
+
+// Функция проверяет, нужно ли отдавать ошибку в формате RFC 7807
+// API v2 всегда отвечает problem+json, API v1 - только если клиент явно запросил его в заголовке Accept
+func WantsProblem(c *gin.Context) bool {
+	if APIVersion(c) == APIVersion2 {
+		return true
+	}
+
+	for _, accepted := range strings.Split(c.GetHeader("Accept"), ",") {
+		if mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted)); err == nil && mediaType == ProblemContentType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Функция прерывает запрос с ошибкой
+// В формате problem+json заполняются статус, instance и идентификатор запроса, код и detail берутся из problem,
+// пустой detail берется из каталога сообщений по коду ошибки на языке запроса.
+// Иначе отдается тело API v1 legacy, при nil ответ отдается без тела, как до появления problem+json
+func Abort(c *gin.Context, status int, problem models.Problem, legacy any) {
+	switch {
+	case WantsProblem(c):
+		if problem.Detail == "" {
+			problem.Detail = Translate(c, string(problem.Code))
+		}
+		problem.Type = problemType
+		problem.Title = http.StatusText(status)
+		problem.Status = status
+		problem.Instance = c.Request.URL.Path
+		problem.RequestID = logger.RequestID(c.Request.Context())
+
+		// gin не перезаписывает Content-Type, если он уже установлен
+		c.Header("Content-Type", ProblemContentType)
+		c.AbortWithStatusJSON(status, problem)
+	case legacy == nil:
+		c.AbortWithStatus(status)
+	default:
+		c.AbortWithStatusJSON(status, legacy)
+	}
+}
+
+// Функция прерывает запрос с ошибкой, detail берется из каталога сообщений по коду ошибки
+// args подставляются в сообщение каталога, в API v1 тело ответа - {"Error": detail}
+func AbortWithError(c *gin.Context, status int, code models.ErrorCode, args ...any) {
+	AbortWithMessage(c, status, code, string(code), args...)
+}
+
+// Функция прерывает запрос с ошибкой, detail берется из каталога сообщений по ключу key
+// Используется, когда для одного кода ошибки нужны разные сообщения
+func AbortWithMessage(c *gin.Context, status int, code models.ErrorCode, key string, args ...any) {
+	detail := Translate(c, key, args...)
+	Abort(c, status, models.Problem{Code: code, Detail: detail}, models.Error{Error: detail})
+}
+
+// Ошибка для непредвиденных сбоев, подробности клиенту не передаются
+var InternalError = models.Problem{Code: models.CodeInternalError}
+
+// Обработчик запросов к несуществующим маршрутам
+// Клиентам без problem+json отдается стандартный ответ gin: он пишется, только если обработчик ничего не записал
+func NoRoute() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !WantsProblem(c) {
+			return
+		}
+		AbortWithError(c, http.StatusNotFound, models.CodeRouteNotFound, c.Request.URL.Path)
+	}
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAbortProblem(t *testing.T) {
+	tests := []struct {
+		name                string
+		path                string
+		accept              string
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:                "legacy",
+			path:                "/api/send",
+			expectedContentType: "application/json; charset=utf-8",
+			expectedBody:        `{"Error":"Invalid X-Request-Timeout header"}`,
+		},
+		{
+			name:                "v1",
+			path:                "/api/v1/send",
+			accept:              "application/json",
+			expectedContentType: "application/json; charset=utf-8",
+			expectedBody:        `{"Error":"Invalid X-Request-Timeout header"}`,
+		},
+		{
+			name:                "v1 with problem accept",
+			path:                "/api/v1/send",
+			accept:              "application/json, application/problem+json;q=0.9",
+			expectedContentType: ProblemContentType,
+			expectedBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid X-Request-Timeout header",` +
+				`"instance":"/api/v1/send","code":"INVALID_HEADER","request_id":"req-1"}`,
+		},
+		{
+			name:                "v2",
+			path:                "/api/v2/send",
+			expectedContentType: ProblemContentType,
+			expectedBody: `{"type":"about:blank","title":"Bad Request","status":400,"detail":"Invalid X-Request-Timeout header",` +
+				`"instance":"/api/v2/send","code":"INVALID_HEADER","request_id":"req-1"}`,
+		},
+	}
+
+	router := gin.New()
+	router.Use(RequestID(), Timeout(TimeoutConfig{Default: time.Second}))
+	for _, path := range []string{"/api/send", "/api/v1/send", "/api/v2/send"} {
+		router.POST(path, func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			req.Header.Set(RequestTimeoutHeader, "soon")
+			req.Header.Set(RequestIDHeader, "req-1")
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
+
+func TestNoRoute(t *testing.T) {
+	router := gin.New()
+	router.NoRoute(NoRoute())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v2/unknown", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"type":"about:blank","title":"Not Found","status":404,"detail":"Route /api/v2/unknown not found",`+
+		`"instance":"/api/v2/unknown","code":"ROUTE_NOT_FOUND"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/unknown", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, "404 page not found", w.Body.String())
+}
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"infotecstechtask/internal/metrics"
+	"infotecstechtask/internal/models"
 	"net/http"
 	"strconv"
 
@@ -15,7 +17,7 @@ func RateLimiter(limiter *limiter.Limiter) gin.HandlerFunc {
 
 		context, err := limiter.Get(c, key)
 		if err != nil {
-			c.AbortWithStatus(http.StatusInternalServerError)
+			Abort(c, http.StatusInternalServerError, InternalError, nil)
 			return
 		}
 
@@ -24,7 +26,8 @@ func RateLimiter(limiter *limiter.Limiter) gin.HandlerFunc {
 		c.Header("X-RateLimit-Reset", strconv.FormatInt(context.Reset, 10))
 
 		if context.Reached {
-			c.AbortWithStatus(http.StatusTooManyRequests)
+			metrics.RateLimitRejections.Inc()
+			Abort(c, http.StatusTooManyRequests, models.Problem{Code: models.CodeRateLimited}, nil)
 			return
 		}
 
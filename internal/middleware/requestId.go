@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"infotecstechtask/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+
+	maxRequestIdLength = 128
+)
+
+// Миддлвар для идентификатора запроса
+// Берет идентификатор из заголовка X-Request-ID или генерирует новый, если заголовка нет или он некорректен,
+// возвращает его в том же заголовке ответа и сохраняет в контексте запроса для логов и журнала аудита
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestId := c.GetHeader(RequestIDHeader)
+		if !ValidRequestID(requestId) {
+			requestId = uuid.NewString()
+		}
+
+		c.Header(RequestIDHeader, requestId)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), requestId))
+
+		c.Next()
+	}
+}
+
+// Функция проверяет идентификатор запроса, полученный от клиента
+// Допускаются только печатные ASCII символы, чтобы идентификатор нельзя было использовать для подделки записей лога
+func ValidRequestID(requestId string) bool {
+	if requestId == "" || len(requestId) > maxRequestIdLength {
+		return false
+	}
+	for i := 0; i < len(requestId); i++ {
+		if requestId[i] < 0x21 || requestId[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
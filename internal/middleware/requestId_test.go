@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"infotecstechtask/pkg/logger"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{name: "accepts incoming id", header: "req-42", expected: "req-42"},
+		{name: "generates missing id", header: ""},
+		{name: "replaces id with spaces", header: "req 42\nforged"},
+		{name: "replaces too long id", header: strings.Repeat("a", maxRequestIdLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fromContext string
+			router := gin.New()
+			router.Use(RequestID())
+			router.GET("/", func(c *gin.Context) {
+				fromContext = logger.RequestID(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			router.ServeHTTP(w, req)
+
+			responseId := w.Header().Get(RequestIDHeader)
+			assert.Equal(t, responseId, fromContext)
+			if tt.expected != "" {
+				assert.Equal(t, tt.expected, responseId)
+			} else {
+				assert.NoError(t, uuid.Validate(responseId))
+			}
+		})
+	}
+}
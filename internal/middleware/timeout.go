@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"infotecstechtask/internal/models"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Заголовок, которым клиент может сократить время ожидания ответа
+// Значение задается длительностью в формате Go ("1500ms", "2s") или числом секунд ("2.5")
+const RequestTimeoutHeader = "X-Request-Timeout"
+
+// Нестандартный статус, которым логируются запросы, прерванные закрытием соединения со стороны клиента
+// Клиент этот ответ уже не получит, статус нужен для логов и метрик
+const StatusClientClosedRequest = 499
+
+// Миддлвар ограничивает время обработки запроса
+//
+// Таймаут берется из конфига по методу и шаблону маршрута, дедлайн устанавливается в контекст запроса,
+// поэтому работа с БД прерывается и по таймауту, и при отключении клиента.
+// Заголовок X-Request-Timeout может только сократить таймаут маршрута, для маршрутов без таймаута он игнорируется
+func Timeout(config TimeoutConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := config.Route(c.Request.Method, UnversionedPath(c.FullPath()))
+		if timeout == 0 {
+			c.Next()
+			return
+		}
+
+		if header := c.GetHeader(RequestTimeoutHeader); header != "" {
+			requested, err := parseRequestTimeout(header)
+			if err != nil {
+				AbortWithError(c, http.StatusBadRequest, models.CodeInvalidHeader, RequestTimeoutHeader)
+				return
+			}
+			timeout = min(timeout, requested)
+		}
+
+		c.Set(untimedContextKey, c.Request.Context())
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// Ключ, под которым сохраняется контекст запроса до установки дедлайна
+const untimedContextKey = "untimedContext"
+
+// Функция возвращает контекст запроса без дедлайна маршрута, он отменяется только при отключении клиента
+// Нужен обработчикам, которые после быстрых проверок отдают длинный потоковый ответ, например выгрузку транзакций
+func WithoutTimeout(c *gin.Context) context.Context {
+	if ctx, ok := c.Get(untimedContextKey); ok {
+		return ctx.(context.Context)
+	}
+	return c.Request.Context()
+}
+
+var errInvalidTimeout = errors.New("invalid timeout")
+
+func parseRequestTimeout(value string) (time.Duration, error) {
+	if timeout, err := time.ParseDuration(value); err == nil {
+		if timeout <= 0 {
+			return 0, errInvalidTimeout
+		}
+		return timeout, nil
+	}
+
+	seconds, err := strconv.ParseFloat(value, 64)
+	// Сравнение отсекает и NaN, и значения, которые не помещаются в time.Duration
+	if err != nil || !(seconds > 0 && seconds < math.MaxInt64/float64(time.Second)) {
+		return 0, errInvalidTimeout
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	config := TimeoutConfig{
+		Default: 5 * time.Second,
+		Routes:  map[string]time.Duration{"POST /api/send": 2 * time.Second},
+	}
+	config.Disable(http.MethodGet, "/api/stream")
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		header   string
+		status   int
+		expected time.Duration
+	}{
+		{name: "default timeout", method: http.MethodGet, path: "/api/transactions", status: http.StatusOK, expected: 5 * time.Second},
+		{name: "route timeout", method: http.MethodPost, path: "/api/send", status: http.StatusOK, expected: 2 * time.Second},
+		{name: "route timeout in every version", method: http.MethodPost, path: "/api/v2/send", status: http.StatusOK, expected: 2 * time.Second},
+		{name: "header shortens timeout", method: http.MethodPost, path: "/api/send", header: "500ms", status: http.StatusOK, expected: 500 * time.Millisecond},
+		{name: "header in seconds", method: http.MethodGet, path: "/api/transactions", header: "1.5", status: http.StatusOK, expected: 1500 * time.Millisecond},
+		{name: "header does not extend timeout", method: http.MethodPost, path: "/api/send", header: "1m", status: http.StatusOK, expected: 2 * time.Second},
+		{name: "disabled route ignores header", method: http.MethodGet, path: "/api/stream", header: "1s", status: http.StatusOK},
+		{name: "invalid header", method: http.MethodGet, path: "/api/transactions", header: "soon", status: http.StatusBadRequest},
+		{name: "negative header", method: http.MethodGet, path: "/api/transactions", header: "-1s", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var remaining time.Duration
+			var hasDeadline bool
+			handler := func(c *gin.Context) {
+				var deadline time.Time
+				deadline, hasDeadline = c.Request.Context().Deadline()
+				remaining = time.Until(deadline)
+				c.Status(http.StatusOK)
+			}
+
+			router := gin.New()
+			router.Use(Timeout(config))
+			router.GET("/api/transactions", handler)
+			router.POST("/api/send", handler)
+			router.POST("/api/v2/send", handler)
+			router.GET("/api/stream", handler)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(RequestTimeoutHeader, tt.header)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+			if tt.status != http.StatusOK {
+				return
+			}
+			assert.Equal(t, tt.expected != 0, hasDeadline)
+			if tt.expected != 0 {
+				assert.InDelta(t, tt.expected, remaining, float64(100*time.Millisecond))
+			}
+		})
+	}
+}
+
+func TestWithoutTimeout(t *testing.T) {
+	config := TimeoutConfig{Default: 5 * time.Second}
+	config.Disable(http.MethodGet, "/api/stream")
+
+	for _, path := range []string{"/api/transactions", "/api/stream"} {
+		t.Run(path, func(t *testing.T) {
+			var hasDeadline bool
+			handler := func(c *gin.Context) {
+				_, hasDeadline = WithoutTimeout(c).Deadline()
+				c.Status(http.StatusOK)
+			}
+
+			router := gin.New()
+			router.Use(Timeout(config))
+			router.GET("/api/transactions", handler)
+			router.GET("/api/stream", handler)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.False(t, hasDeadline)
+		})
+	}
+}
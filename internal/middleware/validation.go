@@ -1,10 +1,16 @@
 package middleware
 
 import (
-	"fmt"
+	"encoding/json"
+	"errors"
+	"infotecstechtask/internal/i18n"
 	"infotecstechtask/internal/models"
+	"io"
 	"net/http"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -21,8 +27,9 @@ func JSONValidation(model any, validate *validator.Validate) gin.HandlerFunc {
 		}
 
 		if c.ContentType() != "application/json" {
-			c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{
-				"error": "Only application/json content type is accepted for POST requests",
+			message := Translate(c, string(models.CodeUnsupportedMediaType))
+			Abort(c, http.StatusUnsupportedMediaType, models.Problem{Code: models.CodeUnsupportedMediaType, Detail: message}, gin.H{
+				"error": message,
 			})
 			return
 		}
@@ -30,16 +37,22 @@ func JSONValidation(model any, validate *validator.Validate) gin.HandlerFunc {
 		val := createModelInstance(model)
 
 		if err := c.ShouldBindJSON(val); err != nil {
-			c.AbortWithStatus(http.StatusBadRequest)
+			Abort(c, http.StatusBadRequest, models.Problem{
+				Code:   models.CodeInvalidJSON,
+				Detail: Translate(c, string(models.CodeInvalidJSON), bindErrorReason(c, err)),
+			}, nil)
 			return
 		}
 
 		if err := validate.Struct(val); err != nil {
-			c.AbortWithStatusJSON(
+			problem := validationFailed(c, model, err)
+			Abort(
+				c,
 				http.StatusBadRequest,
+				problem,
 				models.ValidationError{
-					Error:   "Validation failed",
-					Details: formatJSONValidationErrors(err),
+					Error:   problem.Detail,
+					Details: FormatValidationErrors(err, i18n.FromContext(c.Request.Context())),
 				},
 			)
 			return
@@ -53,34 +66,33 @@ func JSONValidation(model any, validate *validator.Validate) gin.HandlerFunc {
 // Миддлвар для валидации Path и Query параметров
 // Внутри происходит сборка объекта модели параметров запроса и его валидация
 // Если во время сборки или валидации возникает ошибка, конструируется ответ и отправляется клиенту
+// Может использоваться с любым методом, тело запроса при этом не читается
 func ParamsValidation(model any, validate *validator.Validate) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		if c.Request.Method != http.MethodGet {
-			c.Next()
-			return
-		}
-
 		val := createModelInstance(model)
 
 		if err := c.ShouldBindUri(val); err != nil {
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"error":   "Invalid path parameters",
+			message := Translate(c, "params.invalid_path")
+			Abort(c, http.StatusBadRequest, models.Problem{Code: models.CodeInvalidParameters, Detail: message + ": " + bindErrorReason(c, err)}, gin.H{
+				"error":   message,
 				"details": formatErrors(err),
 			})
 			return
 		}
 
 		if err := c.ShouldBindQuery(val); err != nil {
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"error": "Invalid query parameters",
+			message := Translate(c, "params.invalid_query")
+			Abort(c, http.StatusBadRequest, models.Problem{Code: models.CodeInvalidParameters, Detail: message + ": " + bindErrorReason(c, err)}, gin.H{
+				"error": message,
 			})
 			return
 		}
 
 		if err := validate.Struct(val); err != nil {
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"error":   "Validation failed",
-				"details": formatJSONValidationErrors(err),
+			problem := validationFailed(c, model, err)
+			Abort(c, http.StatusBadRequest, problem, gin.H{
+				"error":   problem.Detail,
+				"details": FormatValidationErrors(err, i18n.FromContext(c.Request.Context())),
 			})
 			return
 		}
@@ -90,15 +102,16 @@ func ParamsValidation(model any, validate *validator.Validate) gin.HandlerFunc {
 	}
 }
 
-// Функция для форматирования ошибок валидации
-func formatJSONValidationErrors(err error) []models.FieldError {
+// Функция для форматирования ошибок валидации, используется также gRPC сервером
+// Сообщения об ошибках полей берутся из каталога на указанном языке
+func FormatValidationErrors(err error, lang i18n.Language) []models.FieldError {
 	errors := make([]models.FieldError, 0)
 	for _, fieldErr := range err.(validator.ValidationErrors) {
 		errors = append(
 			errors,
 			models.FieldError{
 				Field:   fieldErr.Field(),
-				Message: getValidationMessage(fieldErr),
+				Message: getValidationMessage(fieldErr, lang),
 			},
 		)
 	}
@@ -106,6 +119,62 @@ func formatJSONValidationErrors(err error) []models.FieldError {
 	return errors
 }
 
+// Функция собирает ошибку валидации в формате problem+json
+// Поля называются так, как их передает клиент: по тегам json, form или uri модели
+func validationFailed(c *gin.Context, model any, err error) models.Problem {
+	details := FormatValidationErrors(err, i18n.FromContext(c.Request.Context()))
+
+	t := reflect.TypeOf(model)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for i, fieldErr := range err.(validator.ValidationErrors) {
+		field, ok := t.FieldByName(fieldErr.StructField())
+		if !ok {
+			continue
+		}
+		for _, tag := range []string{"json", "form", "uri"} {
+			if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name != "" && name != "-" {
+				details[i].Field = name
+				break
+			}
+		}
+	}
+
+	return models.Problem{
+		Code:   models.CodeValidationFailed,
+		Detail: Translate(c, string(models.CodeValidationFailed)),
+		Errors: details,
+	}
+}
+
+// Функция возвращает причину, по которой не удалось разобрать тело или параметры запроса
+// Ошибки декодера переводятся в сообщения на языке запроса без имен типов Go
+func bindErrorReason(c *gin.Context, err error) string {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var numErr *strconv.NumError
+	var timeErr *time.ParseError
+	switch {
+	case errors.Is(err, io.EOF):
+		return Translate(c, "bind.empty_body")
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return Translate(c, "bind.unexpected_end")
+	case errors.As(err, &syntaxErr):
+		return Translate(c, "bind.syntax_error", syntaxErr.Offset)
+	case errors.As(err, &typeErr) && typeErr.Field != "":
+		return Translate(c, "bind.field_type", typeErr.Field, typeErr.Value)
+	case errors.As(err, &typeErr):
+		return Translate(c, "bind.body_type", typeErr.Value)
+	case errors.As(err, &numErr):
+		return Translate(c, "bind.invalid_number", numErr.Num)
+	case errors.As(err, &timeErr):
+		return Translate(c, "bind.invalid_time", timeErr.Value)
+	default:
+		return err.Error()
+	}
+}
+
 // Функция для форматирования ошибок валидации query и path параметров запроса
 func formatErrors(err error) map[string]string {
 	errors := make(map[string]string)
@@ -130,15 +199,15 @@ func createModelInstance(model any) any {
 }
 
 // Функция возвращает человекочитаемые ошибки валидации в зависимости от типа ошибки
-func getValidationMessage(fieldErr validator.FieldError) string {
-	switch fieldErr.Tag() {
-	case "required":
-		return "Field is required"
-	case "uuid":
-		return "Field must be a valid UUID"
-	case "min":
-		return fmt.Sprintf("Field must be greater than %s", fieldErr.Param())
-	default:
-		return fieldErr.Tag()
+// Для тегов, которых нет в каталоге, сообщение содержит название тега
+func getValidationMessage(fieldErr validator.FieldError, lang i18n.Language) string {
+	var args []any
+	if fieldErr.Param() != "" {
+		args = append(args, fieldErr.Param())
 	}
+	if message, ok := i18n.Lookup(lang, "validation."+fieldErr.Tag(), args...); ok {
+		return message
+	}
+
+	return i18n.Translate(lang, "validation.unknown", fieldErr.Tag())
 }
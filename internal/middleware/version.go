@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Версии API
+// Пути без версии (/api/...) - устаревший псевдоним v1
+const (
+	APIVersion1 = "v1"
+	APIVersion2 = "v2"
+)
+
+const apiPath = "/api"
+
+// Функция возвращает версию API по пути запроса
+// Версия определяется по пути, а не миддлваром группы, чтобы ошибки глобальных миддлваров (рейт лимит, таймаут)
+// тоже отдавались в формате версии
+func APIVersion(c *gin.Context) string {
+	if strings.HasPrefix(c.Request.URL.Path, apiPath+"/"+APIVersion2+"/") {
+		return APIVersion2
+	}
+	return APIVersion1
+}
+
+// Функция убирает версию из пути: /api/v2/send -> /api/send
+// Таймауты маршрутов задаются без версии и действуют во всех версиях API
+func UnversionedPath(path string) string {
+	for _, version := range []string{APIVersion1, APIVersion2} {
+		if rest, ok := strings.CutPrefix(path, apiPath+"/"+version+"/"); ok {
+			return apiPath + "/" + rest
+		}
+	}
+	return path
+}
+
+// Миддлвар помечает ответы устаревших маршрутов заголовками Deprecation (RFC 9745) и Sunset (RFC 8594)
+// В заголовке Link передается путь, на который нужно перейти: префикс from в пути запроса заменяется на to
+func Deprecated(deprecatedAt time.Time, sunsetAt time.Time, from string, to string) gin.HandlerFunc {
+	deprecation := "@" + strconv.FormatInt(deprecatedAt.Unix(), 10)
+	sunset := sunsetAt.UTC().Format(http.TimeFormat)
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunset)
+		if rest, ok := strings.CutPrefix(c.Request.URL.Path, from); ok {
+			c.Header("Link", "<"+to+rest+`>; rel="successor-version"`)
+		}
+
+		c.Next()
+	}
+}
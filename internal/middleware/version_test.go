@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnversionedPath(t *testing.T) {
+	assert.Equal(t, "/api/send", UnversionedPath("/api/v1/send"))
+	assert.Equal(t, "/api/wallet/:walletId/balance", UnversionedPath("/api/v2/wallet/:walletId/balance"))
+	assert.Equal(t, "/api/send", UnversionedPath("/api/send"))
+	assert.Equal(t, "/api/v3/send", UnversionedPath("/api/v3/send"))
+}
+
+func TestDeprecated(t *testing.T) {
+	deprecatedAt := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	sunsetAt := time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	router := gin.New()
+	router.GET("/api/transactions", Deprecated(deprecatedAt, sunsetAt, "/api", "/api/v1"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/transactions?count=5", nil))
+
+	assert.Equal(t, "@1792368000", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Thu, 01 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v1/transactions>; rel="successor-version"`, w.Header().Get("Link"))
+}
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AdjustmentStatus string
+
+// Возможные статусы заявок на корректировку баланса
+const (
+	AdjustmentPending  AdjustmentStatus = "pending"
+	AdjustmentApproved AdjustmentStatus = "approved"
+	AdjustmentRejected AdjustmentStatus = "rejected"
+)
+
+type AdjustmentDirection string
+
+// Направление корректировки: зачисление на кошелёк или списание с него
+const (
+	Credit AdjustmentDirection = "credit"
+	Debit  AdjustmentDirection = "debit"
+)
+
+type ReasonCode string
+
+// Возможные причины корректировки баланса
+const (
+	ReasonCorrection ReasonCode = "correction"
+	ReasonChargeback ReasonCode = "chargeback"
+	ReasonRefund     ReasonCode = "refund"
+	ReasonFee        ReasonCode = "fee"
+	ReasonOther      ReasonCode = "other"
+)
+
+// Модель заявки на корректировку, которая хранится в БД
+// Amount - размер корректировки (в копейках)
+// ReviewedBy и TransactionID равны uuid.Nil, пока заявка не рассмотрена
+type Adjustment struct {
+	ID            uuid.UUID
+	WalletID      uuid.UUID
+	Direction     AdjustmentDirection
+	Amount        int
+	ReasonCode    ReasonCode
+	Comment       string
+	Status        AdjustmentStatus
+	CreatedBy     uuid.UUID
+	ReviewedBy    uuid.UUID
+	TransactionID uuid.UUID
+	CreatedAt     time.Time
+	ReviewedAt    *time.Time
+}
+
+// Модель для API-запроса на создание заявки на корректировку
+type CreateAdjustmentRequest struct {
+	WalletID   string              `json:"wallet_id" validate:"required,uuid"`
+	Direction  AdjustmentDirection `json:"direction" validate:"required,oneof=credit debit"`
+	Amount     float64             `json:"amount" validate:"required,gt=0"`
+	ReasonCode ReasonCode          `json:"reason_code" validate:"required,oneof=correction chargeback refund fee other"`
+	Comment    string              `json:"comment" validate:"max=500"`
+}
+
+// Модель для ответа на API-запросы по заявкам на корректировку
+type AdjustmentResponse struct {
+	ID            uuid.UUID           `json:"id"`
+	WalletID      uuid.UUID           `json:"wallet_id"`
+	Direction     AdjustmentDirection `json:"direction"`
+	Amount        float64             `json:"amount"`
+	ReasonCode    ReasonCode          `json:"reason_code"`
+	Comment       string              `json:"comment"`
+	Status        AdjustmentStatus    `json:"status"`
+	CreatedBy     uuid.UUID           `json:"created_by"`
+	ReviewedBy    *uuid.UUID          `json:"reviewed_by,omitempty"`
+	TransactionID *uuid.UUID          `json:"transaction_id,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+	ReviewedAt    *time.Time          `json:"reviewed_at,omitempty"`
+}
+
+// Модель аккумулирующая в себе параметры запроса для получения списка заявок
+type GetAdjustmentsRequest struct {
+	Status *AdjustmentStatus `form:"status" validate:"omitempty,oneof=pending approved rejected"`
+}
+
+// Модель аккумулирующая в себе параметры запроса для рассмотрения заявки
+type ReviewAdjustmentRequest struct {
+	ID string `uri:"adjustmentId" validate:"required,uuid"`
+}
+
+func ToAdjustment(request *CreateAdjustmentRequest, id uuid.UUID, createdBy uuid.UUID, createdAt time.Time) *Adjustment {
+	return &Adjustment{
+		ID:         id,
+		WalletID:   uuid.MustParse(request.WalletID),
+		Direction:  request.Direction,
+		Amount:     int(math.Round(request.Amount * 100)),
+		ReasonCode: request.ReasonCode,
+		Comment:    request.Comment,
+		Status:     AdjustmentPending,
+		CreatedBy:  createdBy,
+		CreatedAt:  createdAt,
+	}
+}
+
+func ToAdjustmentResponse(adjustment *Adjustment) *AdjustmentResponse {
+	response := &AdjustmentResponse{
+		ID:         adjustment.ID,
+		WalletID:   adjustment.WalletID,
+		Direction:  adjustment.Direction,
+		Amount:     float64(adjustment.Amount) / 100.0,
+		ReasonCode: adjustment.ReasonCode,
+		Comment:    adjustment.Comment,
+		Status:     adjustment.Status,
+		CreatedBy:  adjustment.CreatedBy,
+		CreatedAt:  adjustment.CreatedAt,
+		ReviewedAt: adjustment.ReviewedAt,
+	}
+
+	if adjustment.ReviewedBy != uuid.Nil {
+		reviewedBy := adjustment.ReviewedBy
+		response.ReviewedBy = &reviewedBy
+	}
+	if adjustment.TransactionID != uuid.Nil {
+		transactionID := adjustment.TransactionID
+		response.TransactionID = &transactionID
+	}
+
+	return response
+}
+
+func ToAdjustmentResponses(adjustments []*Adjustment) []*AdjustmentResponse {
+	adjustmentResponses := make([]*AdjustmentResponse, 0, len(adjustments))
+
+	for _, adjustment := range adjustments {
+		adjustmentResponses = append(adjustmentResponses, ToAdjustmentResponse(adjustment))
+	}
+
+	return adjustmentResponses
+}
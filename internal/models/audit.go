@@ -0,0 +1,82 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+
+// Список действий, которые фиксируются в журнале аудита
+const (
+	AuditTransferCreate    AuditAction = "transfer.create"
+	AuditAdjustmentCreate  AuditAction = "adjustment.create"
+	AuditAdjustmentApprove AuditAction = "adjustment.approve"
+	AuditAdjustmentReject  AuditAction = "adjustment.reject"
+)
+
+type AuditEntityType string
+
+// Типы сущностей, изменения которых фиксируются в журнале аудита
+const (
+	AuditEntityTransaction AuditEntityType = "transaction"
+	AuditEntityAdjustment  AuditEntityType = "adjustment"
+)
+
+// Метаданные запроса, от имени которого выполняется изменение
+// Передаются через context.Context от миддлвара до репозитория
+type AuditMetadata struct {
+	ActorID   uuid.UUID
+	ActorRole Role
+	IP        string
+	RequestID string
+}
+
+// Описание изменения, которое необходимо зафиксировать в журнале аудита
+// Before и After сериализуются в JSON, nil означает отсутствие состояния
+type AuditChange struct {
+	Action     AuditAction
+	EntityType AuditEntityType
+	EntityID   string
+	Before     any
+	After      any
+}
+
+// Модель записи журнала аудита, которая хранится в БД
+// Before и After хранятся в виде JSON строк, Hash считается от PrevHash и всех остальных полей записи
+// ID - порядковый номер записи внутри цепочки Chain
+type AuditRecord struct {
+	Chain      int
+	ID         int64
+	ActorID    uuid.UUID
+	ActorRole  Role
+	IP         string
+	RequestID  string
+	Action     AuditAction
+	EntityType AuditEntityType
+	EntityID   string
+	Before     string
+	After      string
+	CreatedAt  time.Time
+	PrevHash   string
+	Hash       string
+}
+
+// Последняя запись цепочки журнала аудита
+type AuditChainHead struct {
+	Chain    int
+	LastID   int64
+	LastHash string
+}
+
+// Результат проверки цепочек хешей журнала аудита
+// Если цепочка нарушена, BrokenChain и BrokenAt содержат номер цепочки и идентификатор первой некорректной записи в ней
+type AuditVerificationResult struct {
+	Valid       bool   `json:"valid"`
+	Chains      int    `json:"chains"`
+	Checked     int64  `json:"checked"`
+	BrokenChain int    `json:"broken_chain,omitempty"`
+	BrokenAt    int64  `json:"broken_at,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Шаг ряда баланса кошелька
+type BalanceInterval string
+
+const (
+	IntervalHour  BalanceInterval = "hour"
+	IntervalDay   BalanceInterval = "day"
+	IntervalWeek  BalanceInterval = "week"
+	IntervalMonth BalanceInterval = "month"
+)
+
+// Функция возвращает момент через n шагов после t
+// Месяцы отсчитываются от t, а не от предыдущей точки, поэтому ряд не смещается после коротких месяцев
+func (i BalanceInterval) Add(t time.Time, n int) time.Time {
+	switch i {
+	case IntervalHour:
+		return t.Add(time.Duration(n) * time.Hour)
+	case IntervalWeek:
+		return t.AddDate(0, 0, 7*n)
+	case IntervalMonth:
+		return t.AddDate(0, n, 0)
+	default:
+		return t.AddDate(0, 0, n)
+	}
+}
+
+// Модель аккумулирующая в себе параметры запроса для получения ряда баланса кошелька
+// Interval по умолчанию - day
+type GetBalanceHistoryRequest struct {
+	ID       string          `uri:"walletId" validate:"required,uuid"`
+	From     time.Time       `form:"from" validate:"required"`
+	To       time.Time       `form:"to" validate:"required,gtfield=From"`
+	Interval BalanceInterval `form:"interval" validate:"omitempty,oneof=hour day week month"`
+}
+
+// Баланс кошелька на момент At (в рублях), учитываются транзакции, созданные до At
+type BalancePoint struct {
+	At      time.Time `json:"at"`
+	Balance float64   `json:"balance"`
+}
+
+// Модель для ответа на API-запрос получения ряда баланса
+// Points - баланс на from, через каждый шаг interval и на to
+type BalanceHistoryResponse struct {
+	WalletID uuid.UUID       `json:"wallet_id"`
+	From     time.Time       `json:"from"`
+	To       time.Time       `json:"to"`
+	Interval BalanceInterval `json:"interval"`
+	Points   []BalancePoint  `json:"points"`
+}
+
+// Балансы передаются в копейках в порядке points
+func ToBalanceHistoryResponse(walletId uuid.UUID, interval BalanceInterval, points []time.Time, balances []int) *BalanceHistoryResponse {
+	response := &BalanceHistoryResponse{
+		WalletID: walletId,
+		From:     points[0],
+		To:       points[len(points)-1],
+		Interval: interval,
+		Points:   make([]BalancePoint, 0, len(points)),
+	}
+	for i, at := range points {
+		response.Points = append(response.Points, BalancePoint{At: at, Balance: float64(balances[i]) / 100.0})
+	}
+
+	return response
+}
@@ -16,3 +16,52 @@ type ValidationError struct {
 	Error   string
 	Details []FieldError
 }
+
+// Машиночитаемый код ошибки
+// Коды не меняются между релизами, клиенты могут обрабатывать ошибки по коду, а не по тексту сообщения
+type ErrorCode string
+
+const (
+	CodeValidationFailed          ErrorCode = "VALIDATION_FAILED"
+	CodeInvalidJSON               ErrorCode = "INVALID_JSON"
+	CodeInvalidParameters         ErrorCode = "INVALID_PARAMETERS"
+	CodeInvalidHeader             ErrorCode = "INVALID_HEADER"
+	CodeUnsupportedMediaType      ErrorCode = "UNSUPPORTED_MEDIA_TYPE"
+	CodeAPIKeyRequired            ErrorCode = "API_KEY_REQUIRED"
+	CodeInvalidAPIKey             ErrorCode = "INVALID_API_KEY"
+	CodeInsufficientRole          ErrorCode = "INSUFFICIENT_ROLE"
+	CodeRateLimited               ErrorCode = "RATE_LIMITED"
+	CodeRequestTimeout            ErrorCode = "REQUEST_TIMEOUT"
+	CodeInternalError             ErrorCode = "INTERNAL_ERROR"
+	CodeSenderWalletNotFound      ErrorCode = "SENDER_WALLET_NOT_FOUND"
+	CodeRecipientWalletNotFound   ErrorCode = "RECIPIENT_WALLET_NOT_FOUND"
+	CodeSameWallet                ErrorCode = "SAME_WALLET"
+	CodeWalletNotFound            ErrorCode = "WALLET_NOT_FOUND"
+	CodeWalletNotOwned            ErrorCode = "WALLET_NOT_OWNED"
+	CodeAdjustmentNotFound        ErrorCode = "ADJUSTMENT_NOT_FOUND"
+	CodeAdjustmentSelfReview      ErrorCode = "ADJUSTMENT_SELF_REVIEW"
+	CodeAdjustmentAlreadyReviewed ErrorCode = "ADJUSTMENT_ALREADY_REVIEWED"
+	CodeInsufficientFunds         ErrorCode = "INSUFFICIENT_FUNDS"
+	CodeAdjustmentAmountTooSmall  ErrorCode = "ADJUSTMENT_AMOUNT_TOO_SMALL"
+	CodeWebhookNotFound           ErrorCode = "WEBHOOK_NOT_FOUND"
+	CodeDeliveryNotFound          ErrorCode = "DELIVERY_NOT_FOUND"
+	CodeWebhookURLNotAllowed      ErrorCode = "WEBHOOK_URL_NOT_ALLOWED"
+	CodeStatementPeriodTooLong    ErrorCode = "STATEMENT_PERIOD_TOO_LONG"
+	CodeBalanceHistoryTooLong     ErrorCode = "BALANCE_HISTORY_TOO_LONG"
+	CodeStreamClosed              ErrorCode = "STREAM_CLOSED"
+	CodeRouteNotFound             ErrorCode = "ROUTE_NOT_FOUND"
+)
+
+// Модель ошибки в формате RFC 7807 (application/problem+json)
+// Type всегда about:blank, поэтому Title совпадает с текстом HTTP статуса, а тип ошибки передается в Code
+// Errors заполняется для ошибок валидации
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail"`
+	Instance  string       `json:"instance"`
+	Code      ErrorCode    `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
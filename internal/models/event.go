@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+// Типы событий, которые публикуются через outbox
+const (
+	EventTransactionCompleted EventType = "transaction.completed"
+	EventTransactionFailed    EventType = "transaction.failed"
+	EventWalletBalanceChanged EventType = "wallet.balance_changed"
+)
+
+// Модель события, хранящаяся в таблице outbox
+// WalletID - кошелёк, в рамках которого события доставляются строго по порядку
+type Event struct {
+	ID        int64           `json:"id"`
+	WalletID  uuid.UUID       `json:"wallet_id"`
+	Type      EventType       `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+	Attempts  int             `json:"-"`
+}
+
+// Данные события wallet.balance_changed
+// Balance и Delta передаются в рублях, как и в остальных ответах API
+type BalanceChangedPayload struct {
+	WalletID      uuid.UUID `json:"wallet_id"`
+	Balance       float64   `json:"balance"`
+	Delta         float64   `json:"delta"`
+	TransactionID uuid.UUID `json:"transaction_id"`
+}
+
+// Уведомление о новом событии, которое отправляется через Postgres NOTIFY после фиксации транзакции
+// Само событие читается из outbox по идентификатору, так как размер уведомления ограничен
+type EventNotification struct {
+	ID       int64     `json:"id"`
+	WalletID uuid.UUID `json:"wallet_id"`
+}
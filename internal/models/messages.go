@@ -1,9 +1,10 @@
 package models
 
-// Возможные сообщения внутри транзакций
+// Коды сообщений внутри транзакций
+// В БД хранится код, текст на языке клиента берется из каталога сообщений (пакет i18n)
 const (
-	SENDER_NOT_HAVE_ENOUGH_BALANCE = "Sender does not have enough balance"
-	TRANSACTION_COMPLETED          = "Transaction completed"
-	TRANSACTION_PENDING            = "Transaction pending"
-	TRANSACTION_FAILED             = "Transaction failed"
+	SENDER_NOT_HAVE_ENOUGH_BALANCE = "SENDER_NOT_HAVE_ENOUGH_BALANCE"
+	TRANSACTION_COMPLETED          = "TRANSACTION_COMPLETED"
+	TRANSACTION_PENDING            = "TRANSACTION_PENDING"
+	TRANSACTION_FAILED             = "TRANSACTION_FAILED"
 )
@@ -0,0 +1,39 @@
+package models
+
+import "github.com/google/uuid"
+
+type Role string
+
+// Возможные роли вызывающей стороны
+// Обычный клиент работает только со своими кошельками, остальные роли дают доступ к административному API
+const (
+	RoleClient   Role = "client"
+	RoleViewer   Role = "viewer"
+	RoleOperator Role = "operator"
+	RoleAdmin    Role = "admin"
+)
+
+// Уровни ролей, каждая следующая роль включает в себя права предыдущей
+var roleLevels = map[Role]int{
+	RoleClient:   0,
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+// Функция проверяет, что роль имеет права не ниже требуемой
+func (r Role) Allows(required Role) bool {
+	level, ok := roleLevels[r]
+	if !ok {
+		return false
+	}
+
+	return level >= roleLevels[required]
+}
+
+// Модель аутентифицированной вызывающей стороны
+// ID - идентификатор владельца API ключа, по нему определяются принадлежащие ему кошельки
+type Principal struct {
+	ID   uuid.UUID
+	Role Role
+}
@@ -0,0 +1,6 @@
+package models
+
+// Конверт успешного ответа API v2, данные всегда передаются в поле data
+type DataResponse struct {
+	Data any `json:"data"`
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"infotecstechtask/internal/i18n"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Модель выписки по кошельку за период [From, To), хранится в БД в формате JSON
+// Суммы в копейках: Amount строки положителен у зачислений и отрицателен у списаний, Balance - баланс после транзакции
+type Statement struct {
+	WalletID       uuid.UUID       `json:"wallet_id"`
+	From           time.Time       `json:"from"`
+	To             time.Time       `json:"to"`
+	OpeningBalance int             `json:"opening_balance"`
+	ClosingBalance int             `json:"closing_balance"`
+	TotalIn        int             `json:"total_in"`
+	TotalOut       int             `json:"total_out"`
+	Lines          []StatementLine `json:"lines"`
+	GeneratedAt    time.Time       `json:"generated_at"`
+}
+
+// Строка выписки, Counterparty - другая сторона перевода, у корректировок - uuid.Nil
+type StatementLine struct {
+	TransactionID uuid.UUID       `json:"transaction_id"`
+	Type          TransactionType `json:"type"`
+	Counterparty  uuid.UUID       `json:"counterparty"`
+	Amount        int             `json:"amount"`
+	Balance       int             `json:"balance"`
+	MessageCode   string          `json:"message_code"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// Модель для ответа на API-запрос получения выписки, суммы в рублях
+type StatementResponse struct {
+	WalletID       uuid.UUID               `json:"wallet_id"`
+	From           time.Time               `json:"from"`
+	To             time.Time               `json:"to"`
+	OpeningBalance float64                 `json:"opening_balance"`
+	ClosingBalance float64                 `json:"closing_balance"`
+	TotalIn        float64                 `json:"total_in"`
+	TotalOut       float64                 `json:"total_out"`
+	Lines          []StatementLineResponse `json:"lines"`
+	GeneratedAt    time.Time               `json:"generated_at"`
+}
+
+// Строка выписки в ответе API, Message - текст сообщения на языке клиента
+type StatementLineResponse struct {
+	TransactionID uuid.UUID       `json:"transaction_id"`
+	Type          TransactionType `json:"type"`
+	Counterparty  uuid.UUID       `json:"counterparty"`
+	Amount        float64         `json:"amount"`
+	Balance       float64         `json:"balance"`
+	Message       string          `json:"message"`
+	MessageCode   string          `json:"message_code"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// Модель аккумулирующая в себе параметры запроса для получения выписки
+// From и To (RFC 3339) задаются вместе, без них выписка формируется за прошлый календарный месяц в UTC.
+// Format выбирает формат ответа, без него формат определяется заголовком Accept
+type GetStatementRequest struct {
+	ID     string    `uri:"walletId" validate:"required,uuid"`
+	From   time.Time `form:"from" validate:"required_with=To"`
+	To     time.Time `form:"to" validate:"required_with=From,omitempty,gtfield=From"`
+	Format string    `form:"format" validate:"omitempty,oneof=json html pdf"`
+}
+
+// Функция собирает выписку из входящего остатка и транзакций периода в порядке проведения
+// Amount строк должен быть уже со знаком, баланс после каждой транзакции и обороты считаются здесь
+func NewStatement(walletId uuid.UUID, from time.Time, to time.Time, openingBalance int, lines []StatementLine, generatedAt time.Time) *Statement {
+	statement := &Statement{
+		WalletID:       walletId,
+		From:           from,
+		To:             to,
+		OpeningBalance: openingBalance,
+		ClosingBalance: openingBalance,
+		Lines:          make([]StatementLine, 0, len(lines)),
+		GeneratedAt:    generatedAt,
+	}
+
+	for _, line := range lines {
+		if line.Amount > 0 {
+			statement.TotalIn += line.Amount
+		} else {
+			statement.TotalOut -= line.Amount
+		}
+		statement.ClosingBalance += line.Amount
+		line.Balance = statement.ClosingBalance
+		statement.Lines = append(statement.Lines, line)
+	}
+
+	return statement
+}
+
+// Функция переводит сообщения строк выписки на указанный язык
+func (statement *StatementResponse) Localize(lang i18n.Language) {
+	for i := range statement.Lines {
+		if message, ok := i18n.Lookup(lang, statement.Lines[i].MessageCode); ok {
+			statement.Lines[i].Message = message
+		}
+	}
+}
+
+// Сообщения переводятся на язык по умолчанию, ответ API переводится на язык клиента методом Localize
+func ToStatementResponse(statement *Statement) *StatementResponse {
+	lines := make([]StatementLineResponse, 0, len(statement.Lines))
+	for _, line := range statement.Lines {
+		lines = append(lines, StatementLineResponse{
+			TransactionID: line.TransactionID,
+			Type:          line.Type,
+			Counterparty:  line.Counterparty,
+			Amount:        float64(line.Amount) / 100.0,
+			Balance:       float64(line.Balance) / 100.0,
+			Message:       i18n.Translate(i18n.DefaultLanguage, line.MessageCode),
+			MessageCode:   line.MessageCode,
+			CreatedAt:     line.CreatedAt,
+		})
+	}
+
+	return &StatementResponse{
+		WalletID:       statement.WalletID,
+		From:           statement.From,
+		To:             statement.To,
+		OpeningBalance: float64(statement.OpeningBalance) / 100.0,
+		ClosingBalance: float64(statement.ClosingBalance) / 100.0,
+		TotalIn:        float64(statement.TotalIn) / 100.0,
+		TotalOut:       float64(statement.TotalOut) / 100.0,
+		Lines:          lines,
+		GeneratedAt:    statement.GeneratedAt,
+	}
+}
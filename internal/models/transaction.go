@@ -1,6 +1,7 @@
 package models
 
 import (
+	"infotecstechtask/internal/i18n"
 	"math"
 	"time"
 
@@ -8,9 +9,11 @@ import (
 )
 
 // Модель транзакции, которая хранится в БД
-// Amount - размер транзакции (в копейках)
+// Amount - размер транзакции (в копейках), Message - код сообщения (messages.go)
+// У корректировок одна из сторон отсутствует, вместо неё хранится uuid.Nil
 type Transaction struct {
 	ID          uuid.UUID
+	Type        TransactionType
 	FromAddress uuid.UUID
 	ToAddress   uuid.UUID
 	Amount      int
@@ -27,14 +30,18 @@ type CreateTransactionRequest struct {
 }
 
 // Модель для ответа на API-запрос получения списка транзакций
+// Message - текст сообщения на языке клиента, MessageCode - код сообщения, который не зависит от языка
+// У корректировок отсутствующая сторона равна nil и сериализуется как null
 type TransactionResponse struct {
-	ID          uuid.UUID `json:"id"`
-	FromAddress uuid.UUID `json:"from"`
-	ToAddress   uuid.UUID `json:"to"`
-	Amount      float64   `json:"amount"`
-	Status      Status    `json:"status"`
-	Message     string    `json:"message"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          uuid.UUID       `json:"id"`
+	Type        TransactionType `json:"type"`
+	FromAddress *uuid.UUID      `json:"from"`
+	ToAddress   *uuid.UUID      `json:"to"`
+	Amount      float64         `json:"amount"`
+	Status      Status          `json:"status"`
+	Message     string          `json:"message"`
+	MessageCode string          `json:"message_code"`
+	CreatedAt   time.Time       `json:"created_at"`
 }
 
 // Модель аккумулирующая в себе параметры запроса для получения списка транзакций
@@ -42,32 +49,94 @@ type GetTransactionWithCountRequest struct {
 	Count *int `form:"count" validate:"omitempty,min=1"`
 }
 
+// Модель аккумулирующая в себе параметры запроса для получения и выгрузки истории транзакций
+// From и To (RFC 3339) ограничивают created_at полуинтервалом [from, to), нулевое значение границу не задает.
+// Format выбирает формат ответа, без него формат определяется заголовком Accept
+type GetTransactionHistoryRequest struct {
+	Count  *int      `form:"count" validate:"omitempty,min=1"`
+	From   time.Time `form:"from"`
+	To     time.Time `form:"to" validate:"omitempty,gtfield=From"`
+	Format string    `form:"format" validate:"omitempty,oneof=json csv ndjson"`
+}
+
+// Модель аккумулирующая в себе параметры запроса для получения истории транзакций кошелька
+type GetWalletTransactionsRequest struct {
+	ID string `uri:"walletId" validate:"required,uuid"`
+	GetTransactionHistoryRequest
+}
+
+// Параметры выборки транзакций для выгрузки
+// Незаданные (nil) поля выборку не ограничивают
+type TransactionFilter struct {
+	OwnerID  *uuid.UUID
+	WalletID *uuid.UUID
+	From     *time.Time
+	To       *time.Time
+	Limit    *int
+}
+
+// Функция собирает фильтр выборки из параметров запроса
+// created_at хранится без часового пояса в UTC, поэтому границы периода переводятся в UTC
+func (request GetTransactionHistoryRequest) Filter() TransactionFilter {
+	filter := TransactionFilter{Limit: request.Count}
+	if !request.From.IsZero() {
+		from := request.From.UTC()
+		filter.From = &from
+	}
+	if !request.To.IsZero() {
+		to := request.To.UTC()
+		filter.To = &to
+	}
+
+	return filter
+}
+
+// Функция переводит сообщение транзакции на указанный язык
+// Сообщение с неизвестным кодом не меняется
+func (transaction *TransactionResponse) Localize(lang i18n.Language) {
+	if message, ok := i18n.Lookup(lang, transaction.MessageCode); ok {
+		transaction.Message = message
+	}
+}
+
+// Сообщение переводится на язык по умолчанию, ответ API переводится на язык клиента методом Localize
 func ToTransactionResponse(transaction *Transaction) *TransactionResponse {
 	return &TransactionResponse{
 		ID:          transaction.ID,
-		FromAddress: transaction.FromAddress,
-		ToAddress:   transaction.ToAddress,
+		Type:        transaction.Type,
+		FromAddress: optionalAddress(transaction.FromAddress),
+		ToAddress:   optionalAddress(transaction.ToAddress),
 		Amount:      float64(transaction.Amount) / 100.0,
 		Status:      transaction.Status,
-		Message:     transaction.Message,
+		Message:     i18n.Translate(i18n.DefaultLanguage, transaction.Message),
+		MessageCode: transaction.Message,
 		CreatedAt:   transaction.CreatedAt,
 	}
 }
 
+// Функция возвращает nil для отсутствующей стороны транзакции
+func optionalAddress(address uuid.UUID) *uuid.UUID {
+	if address == uuid.Nil {
+		return nil
+	}
+
+	return &address
+}
+
+// Функция возвращает адрес стороны транзакции строкой, для отсутствующей стороны - пустую строку
+func AddressString(address *uuid.UUID) string {
+	if address == nil {
+		return ""
+	}
+
+	return address.String()
+}
+
 func ToTransactionResponses(transactions []*Transaction) []*TransactionResponse {
 	transactionResponses := make([]*TransactionResponse, 0, len(transactions))
 
 	for _, transaction := range transactions {
-		tr := &TransactionResponse{
-			ID:          transaction.ID,
-			FromAddress: transaction.FromAddress,
-			ToAddress:   transaction.ToAddress,
-			Amount:      float64(transaction.Amount) / 100.0,
-			Status:      transaction.Status,
-			Message:     transaction.Message,
-			CreatedAt:   transaction.CreatedAt,
-		}
-		transactionResponses = append(transactionResponses, tr)
+		transactionResponses = append(transactionResponses, ToTransactionResponse(transaction))
 	}
 
 	return transactionResponses
@@ -76,6 +145,7 @@ func ToTransactionResponses(transactions []*Transaction) []*TransactionResponse
 func ToTransaction(transaction *CreateTransactionRequest, id uuid.UUID, status Status, createdAt time.Time, message string) *Transaction {
 	return &Transaction{
 		ID:          id,
+		Type:        TypeTransfer,
 		FromAddress: uuid.MustParse(transaction.FromAddress),
 		ToAddress:   uuid.MustParse(transaction.ToAddress),
 		Amount:      int(math.Round(transaction.Amount * 100)),
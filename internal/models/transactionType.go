@@ -0,0 +1,10 @@
+package models
+
+type TransactionType string
+
+// Возможные типы транзакций
+// TypeTransfer - перевод между кошельками, TypeAdjustment - ручная корректировка баланса одного кошелька
+const (
+	TypeTransfer   TransactionType = "transfer"
+	TypeAdjustment TransactionType = "adjustment"
+)
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -11,13 +13,22 @@ type WalletResponse struct {
 }
 
 // Модель кошелька, хранящаяся в БД
+// OwnerID равен uuid.Nil, если владелец кошелька не назначен
 type Wallet struct {
 	ID uuid.UUID
 	Balance int
+	OwnerID uuid.UUID
 }
 
 // Модель аккумулирующая в себе параметры запроса для получения баланса кошелька
+// С параметром At (RFC 3339) возвращается баланс на этот момент
 type GetWalletBalanceRequest struct {
+	ID string    `uri:"walletId" validate:"required,uuid"`
+	At time.Time `form:"at"`
+}
+
+// Модель аккумулирующая в себе параметры запроса для подписки на события кошелька
+type GetWalletEventsRequest struct {
 	ID string `uri:"walletId" validate:"required,uuid"`
 }
 
@@ -27,3 +38,16 @@ func ToWalletResponse(wallet *Wallet) *WalletResponse {
 		Balance: float64(wallet.Balance) / 100.0,
 	}
 }
+
+// Модель кошелька для клиента в API v2
+type WalletResponseV2 struct {
+	ID      uuid.UUID `json:"id"`
+	Balance float64   `json:"balance"`
+}
+
+func ToWalletResponseV2(wallet *WalletResponse) *WalletResponseV2 {
+	return &WalletResponseV2{
+		ID:      wallet.ID,
+		Balance: wallet.Balance,
+	}
+}
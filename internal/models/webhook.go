@@ -0,0 +1,162 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookDeliveryStatus string
+
+// Возможные статусы доставки события на webhook
+// WebhookDeliveryDead - попытки исчерпаны, доставка возобновляется только вручную
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryDead      WebhookDeliveryStatus = "dead"
+)
+
+// Модель подписки на события, которая хранится в БД
+// Secret используется для подписи доставок и возвращается клиенту только при создании подписки
+type WebhookSubscription struct {
+	ID         uuid.UUID
+	OwnerID    uuid.UUID
+	URL        string
+	Secret     string
+	EventTypes []EventType
+	WalletIDs  []uuid.UUID
+	CreatedAt  time.Time
+}
+
+// Модель доставки события на webhook, которая хранится в БД
+// URL и Secret заполняются из подписки при выборке доставок для отправки
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	EventID        int64
+	EventType      EventType
+	Payload        json.RawMessage
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	LastStatusCode int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+	URL            string
+	Secret         string
+}
+
+// Модель для API-запроса на создание подписки
+type CreateWebhookRequest struct {
+	URL        string      `json:"url" validate:"required,url,max=2048"`
+	EventTypes []EventType `json:"event_types" validate:"required,min=1,dive,oneof=transaction.completed transaction.failed wallet.balance_changed"`
+	WalletIDs  []string    `json:"wallet_ids" validate:"required,min=1,dive,uuid"`
+}
+
+// Модель для ответа на API-запросы по подпискам
+// Secret заполняется только в ответе на создание подписки
+type WebhookResponse struct {
+	ID         uuid.UUID   `json:"id"`
+	URL        string      `json:"url"`
+	EventTypes []EventType `json:"event_types"`
+	WalletIDs  []uuid.UUID `json:"wallet_ids"`
+	Secret     string      `json:"secret,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// Модель для ответа на API-запросы по истории доставок
+type WebhookDeliveryResponse struct {
+	ID             uuid.UUID             `json:"id"`
+	EventID        int64                 `json:"event_id"`
+	EventType      EventType             `json:"event_type"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	LastStatusCode int                   `json:"last_status_code,omitempty"`
+	LastError      string                `json:"last_error,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+}
+
+// Модель аккумулирующая в себе параметры запроса для получения истории доставок
+type GetWebhookDeliveriesRequest struct {
+	ID string `uri:"webhookId" validate:"required,uuid"`
+}
+
+// Модель аккумулирующая в себе параметры запроса для повторной доставки
+type RedeliverWebhookRequest struct {
+	ID         string `uri:"webhookId" validate:"required,uuid"`
+	DeliveryID string `uri:"deliveryId" validate:"required,uuid"`
+}
+
+// Тело запроса, которое получает webhook
+type WebhookPayload struct {
+	ID        uuid.UUID       `json:"id"`
+	EventID   int64           `json:"event_id"`
+	Type      EventType       `json:"type"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func ToWebhookSubscription(request *CreateWebhookRequest, id uuid.UUID, ownerId uuid.UUID, secret string, createdAt time.Time) *WebhookSubscription {
+	walletIds := make([]uuid.UUID, 0, len(request.WalletIDs))
+	for _, walletId := range request.WalletIDs {
+		walletIds = append(walletIds, uuid.MustParse(walletId))
+	}
+
+	return &WebhookSubscription{
+		ID:         id,
+		OwnerID:    ownerId,
+		URL:        request.URL,
+		Secret:     secret,
+		EventTypes: request.EventTypes,
+		WalletIDs:  walletIds,
+		CreatedAt:  createdAt,
+	}
+}
+
+func ToWebhookResponse(subscription *WebhookSubscription) *WebhookResponse {
+	return &WebhookResponse{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		WalletIDs:  subscription.WalletIDs,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
+
+func ToWebhookResponses(subscriptions []*WebhookSubscription) []*WebhookResponse {
+	webhookResponses := make([]*WebhookResponse, 0, len(subscriptions))
+
+	for _, subscription := range subscriptions {
+		webhookResponses = append(webhookResponses, ToWebhookResponse(subscription))
+	}
+
+	return webhookResponses
+}
+
+func ToWebhookDeliveryResponse(delivery *WebhookDelivery) *WebhookDeliveryResponse {
+	return &WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+		DeliveredAt:    delivery.DeliveredAt,
+	}
+}
+
+func ToWebhookDeliveryResponses(deliveries []*WebhookDelivery) []*WebhookDeliveryResponse {
+	deliveryResponses := make([]*WebhookDeliveryResponse, 0, len(deliveries))
+
+	for _, delivery := range deliveries {
+		deliveryResponses = append(deliveryResponses, ToWebhookDeliveryResponse(delivery))
+	}
+
+	return deliveryResponses
+}
@@ -0,0 +1,40 @@
+package models
+
+import "github.com/google/uuid"
+
+type FeedAction string
+
+// Действия, которые клиент может отправить в WebSocket-ленту
+const (
+	FeedSubscribe   FeedAction = "subscribe"
+	FeedUnsubscribe FeedAction = "unsubscribe"
+)
+
+type FeedMessageType string
+
+// Типы сообщений, которые сервер отправляет в WebSocket-ленту
+const (
+	FeedSubscribed   FeedMessageType = "subscribed"
+	FeedUnsubscribed FeedMessageType = "unsubscribed"
+	FeedEvent        FeedMessageType = "event"
+	FeedError        FeedMessageType = "error"
+)
+
+// Сообщение клиента в WebSocket-ленте
+// LastEventID учитывается только при подписке: события кошельков после него будут отправлены повторно
+type FeedRequest struct {
+	Action      FeedAction `json:"action"`
+	WalletIDs   []string   `json:"wallet_ids"`
+	LastEventID int64      `json:"last_event_id,omitempty"`
+}
+
+// Сообщение сервера в WebSocket-ленте
+// WalletID, Code и Error заполняются в сообщениях об ошибке, Error - на языке из Accept-Language, Event - в сообщениях с событием
+type FeedMessage struct {
+	Type      FeedMessageType `json:"type"`
+	WalletIDs []uuid.UUID     `json:"wallet_ids,omitempty"`
+	WalletID  *uuid.UUID      `json:"wallet_id,omitempty"`
+	Event     *Event          `json:"event,omitempty"`
+	Code      ErrorCode       `json:"code,omitempty"`
+	Error     string          `json:"error,omitempty"`
+}
@@ -0,0 +1,66 @@
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"infotecstechtask/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Изменение баланса кошелька в результате транзакции (в копейках)
+type BalanceChange struct {
+	WalletID uuid.UUID
+	Balance  int
+	Delta    int
+}
+
+// Функция для сборки событий по итогам транзакции
+//
+// Для завершенной транзакции событие transaction.completed получает каждая из сторон,
+// для неуспешной событие transaction.failed получает только отправитель.
+// Для каждого изменения баланса добавляется событие wallet.balance_changed
+func TransactionEvents(transaction *models.Transaction, changes ...BalanceChange) ([]*models.Event, error) {
+	eventType := models.EventTransactionCompleted
+	wallets := []uuid.UUID{transaction.FromAddress, transaction.ToAddress}
+	if transaction.Status == models.Failed {
+		eventType = models.EventTransactionFailed
+		wallets = wallets[:1]
+	}
+
+	payload, err := json.Marshal(models.ToTransactionResponse(transaction))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal transaction event: %w", err)
+	}
+
+	events := make([]*models.Event, 0, len(wallets)+len(changes))
+	for _, walletId := range wallets {
+		if walletId == uuid.Nil {
+			continue
+		}
+		events = append(events, &models.Event{
+			WalletID: walletId,
+			Type:     eventType,
+			Payload:  payload,
+		})
+	}
+
+	for _, change := range changes {
+		payload, err := json.Marshal(models.BalanceChangedPayload{
+			WalletID:      change.WalletID,
+			Balance:       float64(change.Balance) / 100.0,
+			Delta:         float64(change.Delta) / 100.0,
+			TransactionID: transaction.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal balance event: %w", err)
+		}
+		events = append(events, &models.Event{
+			WalletID: change.WalletID,
+			Type:     models.EventWalletBalanceChanged,
+			Payload:  payload,
+		})
+	}
+
+	return events, nil
+}
@@ -0,0 +1,5 @@
+package outbox
+
+// Канал Postgres, в который отправляется уведомление о каждом записанном в outbox событии
+// Уведомления доставляются подписчикам только после фиксации транзакции, поэтому откаченные события в канал не попадают
+const NotifyChannel = "outbox_events"
@@ -0,0 +1,12 @@
+package outbox
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+)
+
+// Интерфейс, который должна реализовывать каждая структура, публикующая события во внешние системы
+// Publish может быть вызван для одного и того же события повторно, получатели должны быть готовы к дублям
+type EventPublisher interface {
+	Publish(ctx context.Context, event *models.Event) error
+}
@@ -0,0 +1,49 @@
+package publisher
+
+import (
+	"fmt"
+	"infotecstechtask/internal/outbox"
+	"time"
+)
+
+// Способы публикации событий
+const (
+	KindStdout  = "stdout"
+	KindFile    = "file"
+	KindWebhook = "webhook"
+)
+
+const defaultWebhookTimeout = 5 * time.Second
+
+// Список поддерживаемых способов публикации
+var Kinds = []string{KindStdout, KindFile, KindWebhook}
+
+// Структура, хранящая в себе параметры публикации событий
+// Значения собираются пакетом internal/config
+//
+// FilePath используется только KindFile, WebhookURL - только KindWebhook
+type Config struct {
+	Kind       string
+	FilePath   string
+	WebhookURL string
+}
+
+// Функция для создания публикатора по конфигу
+func New(config Config) (outbox.EventPublisher, error) {
+	switch config.Kind {
+	case KindStdout:
+		return NewStdoutPublisher(), nil
+	case KindFile:
+		if config.FilePath == "" {
+			return nil, fmt.Errorf("outbox.file_path is required for %s publisher", KindFile)
+		}
+		return NewFilePublisher(config.FilePath)
+	case KindWebhook:
+		if config.WebhookURL == "" {
+			return nil, fmt.Errorf("outbox.webhook_url is required for %s publisher", KindWebhook)
+		}
+		return NewWebhookPublisher(config.WebhookURL, defaultWebhookTimeout), nil
+	default:
+		return nil, fmt.Errorf("unknown outbox publisher %q", config.Kind)
+	}
+}
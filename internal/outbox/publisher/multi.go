@@ -0,0 +1,31 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/outbox"
+)
+
+// Реализация публикатора, передающая каждое событие всем вложенным публикаторам
+// Если хотя бы один из них вернул ошибку, событие будет опубликовано повторно во всех
+type MultiPublisher struct {
+	publishers []outbox.EventPublisher
+}
+
+func NewMultiPublisher(publishers ...outbox.EventPublisher) *MultiPublisher {
+	return &MultiPublisher{
+		publishers: publishers,
+	}
+}
+
+func (p *MultiPublisher) Publish(ctx context.Context, event *models.Event) error {
+	var errs []error
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,62 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"infotecstechtask/internal/models"
+	"io"
+	"os"
+	"sync"
+)
+
+// Реализация публикатора, записывающая события в формате JSON, по одному событию на строку
+type WriterPublisher struct {
+	mu     sync.Mutex
+	writer io.Writer
+	closer io.Closer
+}
+
+func NewWriterPublisher(writer io.Writer) *WriterPublisher {
+	return &WriterPublisher{
+		writer: writer,
+	}
+}
+
+func NewStdoutPublisher() *WriterPublisher {
+	return NewWriterPublisher(os.Stdout)
+}
+
+// Файл открывается на дозапись и создается, если его нет
+func NewFilePublisher(path string) (*WriterPublisher, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outbox file: %w", err)
+	}
+
+	return &WriterPublisher{
+		writer: file,
+		closer: file,
+	}, nil
+}
+
+func (p *WriterPublisher) Publish(ctx context.Context, event *models.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, err = p.writer.Write(append(data, '\n'))
+	return err
+}
+
+func (p *WriterPublisher) Close() error {
+	if p.closer == nil {
+		return nil
+	}
+
+	return p.closer.Close()
+}
@@ -0,0 +1,12 @@
+package relay
+
+import "time"
+
+// Структура, хранящая в себе параметры публикации событий из outbox
+// Значения собираются пакетом internal/config
+//
+// Interval - период проверки неопубликованных событий, BatchSize - количество событий в одном запросе
+type Config struct {
+	Interval  time.Duration
+	BatchSize int
+}
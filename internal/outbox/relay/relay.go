@@ -0,0 +1,111 @@
+package relay
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/outbox"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Ключ advisory блокировки, которую удерживает relay во время публикации
+// Благодаря ей при нескольких экземплярах приложения события публикует только один из них
+const relayLockKey int64 = 7_263_001
+
+const (
+	minRetryDelay = 1 * time.Second
+	maxRetryDelay = 5 * time.Minute
+)
+
+// Интерфейс для выполнения функции под распределенной блокировкой
+type Locker interface {
+	WithAdvisoryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error)
+}
+
+// Фоновый процесс, публикующий события из outbox
+//
+// Доставка выполняется по схеме at-least-once: событие помечается опубликованным только после успешного Publish.
+// Если публикация события кошелька не удалась, остальные события этого кошелька
+// не публикуются до успешной повторной попытки, что сохраняет порядок доставки в рамках кошелька
+type Relay struct {
+	repository outbox.Repository
+	publisher  outbox.EventPublisher
+	locker     Locker
+	interval   time.Duration
+	batchSize  int
+}
+
+func NewRelay(repository outbox.Repository, publisher outbox.EventPublisher, locker Locker, config Config) *Relay {
+	return &Relay{
+		repository: repository,
+		publisher:  publisher,
+		locker:     locker,
+		interval:   config.Interval,
+		batchSize:  config.BatchSize,
+	}
+}
+
+// Функция запускает цикл публикации и завершается при отмене контекста
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := r.locker.WithAdvisoryLock(ctx, relayLockKey, r.publishPending)
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "outbox relay failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Функция публикует одну пачку неопубликованных событий
+func (r *Relay) publishPending(ctx context.Context) error {
+	events, err := r.repository.GetPending(ctx, r.batchSize)
+	if err != nil {
+		return err
+	}
+
+	blocked := make(map[uuid.UUID]struct{})
+	for _, event := range events {
+		if _, ok := blocked[event.WalletID]; ok {
+			continue
+		}
+
+		if err := r.publisher.Publish(ctx, event); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			blocked[event.WalletID] = struct{}{}
+			err = r.repository.MarkFailed(ctx, event.ID, retryDelay(event), err.Error())
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := r.repository.MarkPublished(ctx, event.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Функция для расчета задержки перед повторной попыткой, задержка удваивается с каждой неудачной попыткой
+func retryDelay(event *models.Event) time.Duration {
+	delay := minRetryDelay
+	for i := 0; i < event.Attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxRetryDelay)
+}
@@ -0,0 +1,77 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"infotecstechtask/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepository struct {
+	events    []*models.Event
+	published []int64
+	failed    map[int64]time.Duration
+}
+
+func (r *fakeRepository) GetPending(_ context.Context, limit int) ([]*models.Event, error) {
+	return r.events[:min(limit, len(r.events))], nil
+}
+
+func (r *fakeRepository) MarkPublished(_ context.Context, eventId int64) error {
+	r.published = append(r.published, eventId)
+	return nil
+}
+
+func (r *fakeRepository) MarkFailed(_ context.Context, eventId int64, retryAfter time.Duration, _ string) error {
+	r.failed[eventId] = retryAfter
+	return nil
+}
+
+type fakePublisher struct {
+	failing   map[int64]bool
+	published []int64
+}
+
+func (p *fakePublisher) Publish(_ context.Context, event *models.Event) error {
+	if p.failing[event.ID] {
+		return errors.New("unavailable")
+	}
+	p.published = append(p.published, event.ID)
+	return nil
+}
+
+type fakeLocker struct{}
+
+func (fakeLocker) WithAdvisoryLock(ctx context.Context, _ int64, fn func(ctx context.Context) error) (bool, error) {
+	return true, fn(ctx)
+}
+
+func TestPublishPendingKeepsOrderPerWallet(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	repository := &fakeRepository{
+		events: []*models.Event{
+			{ID: 1, WalletID: first, Type: models.EventTransactionCompleted},
+			{ID: 2, WalletID: second, Type: models.EventTransactionCompleted},
+			{ID: 3, WalletID: first, Type: models.EventWalletBalanceChanged},
+			{ID: 4, WalletID: second, Type: models.EventWalletBalanceChanged, Attempts: 2},
+		},
+		failed: map[int64]time.Duration{},
+	}
+	publisher := &fakePublisher{failing: map[int64]bool{1: true, 4: true}}
+
+	err := NewRelay(repository, publisher, fakeLocker{}, Config{Interval: time.Second, BatchSize: 100}).publishPending(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []int64{2}, publisher.published)
+	assert.Equal(t, []int64{2}, repository.published)
+	assert.Equal(t, map[int64]time.Duration{1: 1 * time.Second, 4: 4 * time.Second}, repository.failed)
+}
+
+func TestRetryDelayIsCapped(t *testing.T) {
+	assert.Equal(t, maxRetryDelay, retryDelay(&models.Event{Attempts: 100}))
+}
@@ -0,0 +1,25 @@
+package outbox
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// Интерфейс для записи событий в outbox
+// Запись выполняется внутри уже открытой БД транзакции, поэтому событие сохраняется только вместе с изменением
+type Writer interface {
+	Add(ctx context.Context, tx pgx.Tx, events []*models.Event) error
+}
+
+// Интерфейс репозитория, который использует relay для публикации событий
+//
+// GetPending возвращает неопубликованные события в порядке создания,
+// пропуская кошельки, у которых есть событие, ожидающее повторной попытки
+type Repository interface {
+	GetPending(ctx context.Context, limit int) ([]*models.Event, error)
+	MarkPublished(ctx context.Context, eventId int64) error
+	MarkFailed(ctx context.Context, eventId int64, retryAfter time.Duration, lastError string) error
+}
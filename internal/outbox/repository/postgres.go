@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/outbox"
+	"infotecstechtask/pkg/database"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+// Реализация репозитория
+type OutboxRepository struct {
+	db *database.Client
+}
+
+func NewOutboxRepository(db *database.Client) *OutboxRepository {
+	return &OutboxRepository{
+		db: db,
+	}
+}
+
+// Реализация метода для записи событий в outbox
+// Идентификаторы событий выдаются после блокировки кошелька в той же транзакции,
+// поэтому для одного кошелька порядок идентификаторов совпадает с порядком фиксации транзакций
+//
+// О каждом событии отправляется уведомление в канал outbox.NotifyChannel
+func (r *OutboxRepository) Add(ctx context.Context, tx pgx.Tx, events []*models.Event) error {
+	for _, event := range events {
+		err := tx.QueryRow(
+			ctx,
+			`INSERT INTO outbox (wallet_id, event_type, payload) VALUES ($1, $2, $3) RETURNING id, created_at`,
+			event.WalletID,
+			event.Type,
+			string(event.Payload),
+		).Scan(&event.ID, &event.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert outbox event: %w", err)
+		}
+
+		notification, err := json.Marshal(models.EventNotification{ID: event.ID, WalletID: event.WalletID})
+		if err != nil {
+			return fmt.Errorf("failed to marshal outbox notification: %w", err)
+		}
+		_, err = tx.Exec(ctx, `SELECT pg_notify($1, $2)`, outbox.NotifyChannel, string(notification))
+		if err != nil {
+			return fmt.Errorf("failed to notify outbox event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Возвращает неопубликованные события в порядке создания
+// События кошельков, у которых есть событие в ожидании повторной попытки, не возвращаются,
+// чтобы более поздние события не были опубликованы раньше неудачного
+func (r *OutboxRepository) GetPending(ctx context.Context, limit int) ([]*models.Event, error) {
+	sql := `SELECT id, wallet_id, event_type, payload, created_at, attempts
+            FROM outbox
+            WHERE published_at IS NULL
+              AND wallet_id NOT IN (
+                  SELECT wallet_id FROM outbox
+                  WHERE published_at IS NULL AND next_attempt_at > CURRENT_TIMESTAMP
+              )
+            ORDER BY id
+            LIMIT $1`
+
+	rows, err := r.db.Query(ctx, sql, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows, limit)
+}
+
+// Возвращает событие по идентификатору
+func (r *OutboxRepository) GetEvent(ctx context.Context, eventId int64) (*models.Event, error) {
+	row := r.db.QueryRow(
+		ctx,
+		`SELECT id, wallet_id, event_type, payload, created_at, attempts FROM outbox WHERE id = $1`,
+		eventId,
+	)
+
+	return scanEvent(row)
+}
+
+// Возвращает события кошелька с идентификатором больше afterId в порядке создания
+// Используется для восстановления потока событий после переподключения клиента
+func (r *OutboxRepository) GetWalletEvents(ctx context.Context, walletId uuid.UUID, afterId int64, limit int) ([]*models.Event, error) {
+	sql := `SELECT id, wallet_id, event_type, payload, created_at, attempts
+            FROM outbox
+            WHERE wallet_id = $1 AND id > $2
+            ORDER BY id
+            LIMIT $3`
+
+	rows, err := r.db.Query(ctx, sql, walletId, afterId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEvents(rows, limit)
+}
+
+func (r *OutboxRepository) MarkPublished(ctx context.Context, eventId int64) error {
+	return r.db.Exec(
+		ctx,
+		`UPDATE outbox SET published_at = CURRENT_TIMESTAMP, attempts = attempts + 1, last_error = '' WHERE id = $1`,
+		eventId,
+	)
+}
+
+// Откладывает следующую попытку публикации события на retryAfter
+func (r *OutboxRepository) MarkFailed(ctx context.Context, eventId int64, retryAfter time.Duration, lastError string) error {
+	return r.db.Exec(
+		ctx,
+		`UPDATE outbox
+         SET attempts = attempts + 1, last_error = $2, next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $3)
+         WHERE id = $1`,
+		eventId,
+		lastError,
+		retryAfter.Seconds(),
+	)
+}
+
+// Функция для сборки события из строки результата запроса
+func scanEvent(row pgx.Row) (*models.Event, error) {
+	var event models.Event
+	var payload string
+	err := row.Scan(&event.ID, &event.WalletID, &event.Type, &payload, &event.CreatedAt, &event.Attempts)
+	if err != nil {
+		return nil, err
+	}
+	event.Payload = []byte(payload)
+
+	return &event, nil
+}
+
+// Функция для сборки списка событий из результата запроса
+func scanEvents(rows pgx.Rows, capacity int) ([]*models.Event, error) {
+	events := make([]*models.Event, 0, capacity)
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
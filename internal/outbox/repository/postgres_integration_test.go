@@ -0,0 +1,192 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/pkg/database"
+	"infotecstechtask/test/testutils"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	firstWallet  = uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10")
+	secondWallet = uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")
+)
+
+type OutboxRepositoryTestSuite struct {
+	suite.Suite
+	pgContainer *testutils.PGTestContainer
+	client      *database.Client
+	repo        *OutboxRepository
+	ctx         context.Context
+}
+
+func (suite *OutboxRepositoryTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+
+	container, err := testutils.StartPGContainer(suite.ctx)
+	if err != nil {
+		log.Fatalf("Failed to start test container: %v", err)
+	}
+	suite.pgContainer = container
+
+	suite.client = database.NewClientWithPool(container.Pool)
+	suite.repo = NewOutboxRepository(suite.client)
+}
+
+func (suite *OutboxRepositoryTestSuite) TearDownSuite() {
+	if suite.pgContainer != nil {
+		if err := suite.pgContainer.Close(suite.ctx); err != nil {
+			log.Printf("Failed to close test container: %v", err)
+		}
+	}
+}
+
+func (suite *OutboxRepositoryTestSuite) BeforeTest(_, _ string) {
+	_, err := suite.pgContainer.Pool.Exec(suite.ctx, "TRUNCATE TABLE outbox RESTART IDENTITY")
+	if err != nil {
+		log.Printf("TRUNCATE error: %v", err)
+	}
+
+	assert.NoError(suite.T(), err)
+}
+
+func TestOutboxRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxRepositoryTestSuite))
+}
+
+func (suite *OutboxRepositoryTestSuite) TestGetPendingReturnsEventsInOrder() {
+	first := suite.add(firstWallet, models.EventTransactionCompleted)
+	second := suite.add(secondWallet, models.EventTransactionCompleted)
+	third := suite.add(firstWallet, models.EventWalletBalanceChanged)
+
+	events, err := suite.repo.GetPending(suite.ctx, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(events, 3)
+	suite.Assert().Equal([]int64{first.ID, second.ID, third.ID}, eventIds(events))
+	suite.Assert().Equal(firstWallet, events[0].WalletID)
+	suite.Assert().Equal(models.EventWalletBalanceChanged, events[2].Type)
+	suite.Assert().JSONEq(`{"wallet_id":"`+firstWallet.String()+`"}`, string(events[2].Payload))
+
+	events, err = suite.repo.GetPending(suite.ctx, 2)
+	suite.Require().NoError(err)
+	suite.Assert().Equal([]int64{first.ID, second.ID}, eventIds(events))
+}
+
+func (suite *OutboxRepositoryTestSuite) TestRolledBackEventIsNotAdded() {
+	err := suite.client.ExecuteTx(suite.ctx, func(tx pgx.Tx) error {
+		err := suite.repo.Add(suite.ctx, tx, []*models.Event{newEvent(firstWallet, models.EventTransactionCompleted)})
+		suite.Require().NoError(err)
+		return errors.New("rollback")
+	})
+	suite.Require().Error(err)
+
+	events, err := suite.repo.GetPending(suite.ctx, 10)
+	suite.Require().NoError(err)
+	suite.Assert().Empty(events)
+}
+
+// Событие, публикация которого не подтверждена, возвращается повторно
+func (suite *OutboxRepositoryTestSuite) TestUnacknowledgedEventIsReturnedAgain() {
+	event := suite.add(firstWallet, models.EventTransactionCompleted)
+
+	for i := 0; i < 2; i++ {
+		events, err := suite.repo.GetPending(suite.ctx, 10)
+		suite.Require().NoError(err)
+		suite.Assert().Equal([]int64{event.ID}, eventIds(events))
+	}
+
+	err := suite.repo.MarkPublished(suite.ctx, event.ID)
+	suite.Require().NoError(err)
+
+	events, err := suite.repo.GetPending(suite.ctx, 10)
+	suite.Require().NoError(err)
+	suite.Assert().Empty(events)
+
+	published, err := suite.repo.GetEvent(suite.ctx, event.ID)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(1, published.Attempts)
+}
+
+// Пока неудачное событие кошелька ждет повторной попытки, следующие события этого кошелька не возвращаются,
+// а события других кошельков публикуются как обычно
+func (suite *OutboxRepositoryTestSuite) TestFailedEventHoldsBackLaterEventsOfWallet() {
+	failed := suite.add(firstWallet, models.EventTransactionCompleted)
+	other := suite.add(secondWallet, models.EventTransactionCompleted)
+	later := suite.add(firstWallet, models.EventWalletBalanceChanged)
+
+	err := suite.repo.MarkFailed(suite.ctx, failed.ID, time.Minute, "unavailable")
+	suite.Require().NoError(err)
+
+	events, err := suite.repo.GetPending(suite.ctx, 10)
+	suite.Require().NoError(err)
+	suite.Assert().Equal([]int64{other.ID}, eventIds(events))
+
+	var lastError string
+	err = suite.pgContainer.Pool.QueryRow(suite.ctx, `SELECT last_error FROM outbox WHERE id = $1`, failed.ID).Scan(&lastError)
+	suite.Require().NoError(err)
+	suite.Assert().Equal("unavailable", lastError)
+
+	// После задержки неудачное событие возвращается снова и по-прежнему раньше следующих событий кошелька
+	err = suite.repo.MarkFailed(suite.ctx, failed.ID, 0, "unavailable")
+	suite.Require().NoError(err)
+
+	events, err = suite.repo.GetPending(suite.ctx, 10)
+	suite.Require().NoError(err)
+	suite.Assert().Equal([]int64{failed.ID, other.ID, later.ID}, eventIds(events))
+	suite.Assert().Equal(2, events[0].Attempts)
+}
+
+func (suite *OutboxRepositoryTestSuite) TestGetWalletEvents() {
+	first := suite.add(firstWallet, models.EventTransactionCompleted)
+	suite.add(secondWallet, models.EventTransactionCompleted)
+	third := suite.add(firstWallet, models.EventWalletBalanceChanged)
+
+	err := suite.repo.MarkPublished(suite.ctx, first.ID)
+	suite.Require().NoError(err)
+
+	events, err := suite.repo.GetWalletEvents(suite.ctx, firstWallet, 0, 10)
+	suite.Require().NoError(err)
+	suite.Assert().Equal([]int64{first.ID, third.ID}, eventIds(events))
+
+	events, err = suite.repo.GetWalletEvents(suite.ctx, firstWallet, first.ID, 10)
+	suite.Require().NoError(err)
+	suite.Assert().Equal([]int64{third.ID}, eventIds(events))
+}
+
+// Функция записывает одно событие в отдельной транзакции
+func (suite *OutboxRepositoryTestSuite) add(walletId uuid.UUID, eventType models.EventType) *models.Event {
+	event := newEvent(walletId, eventType)
+	err := suite.client.ExecuteTx(suite.ctx, func(tx pgx.Tx) error {
+		return suite.repo.Add(suite.ctx, tx, []*models.Event{event})
+	})
+	suite.Require().NoError(err)
+	suite.Require().NotZero(event.ID)
+
+	return event
+}
+
+func newEvent(walletId uuid.UUID, eventType models.EventType) *models.Event {
+	return &models.Event{
+		WalletID: walletId,
+		Type:     eventType,
+		Payload:  []byte(`{"wallet_id":"` + walletId.String() + `"}`),
+	}
+}
+
+func eventIds(events []*models.Event) []int64 {
+	ids := make([]int64, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+
+	return ids
+}
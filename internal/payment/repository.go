@@ -7,6 +7,7 @@ import (
 
 // Интерфейс репозитория для создания транзакций
 // Выделил операцию в отдельный интерфейс, чтобы все операции во время создания и выполнения транзакции выполнялись в одной БД транзакции
+// Списывать с кошелька может его владелец или вызывающая сторона с ролью не ниже operator
 type Repository interface {
-	CreatePayment(ctx context.Context, createTransactionRequest *models.CreateTransactionRequest) (*models.TransactionResponse, error)
+	CreatePayment(ctx context.Context, principal *models.Principal, createTransactionRequest *models.CreateTransactionRequest) (*models.TransactionResponse, error)
 }
@@ -4,30 +4,48 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"infotecstechtask/internal/audit"
 	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/outbox"
 	"infotecstechtask/internal/payment"
+	"infotecstechtask/internal/tracing"
+	"infotecstechtask/internal/wallet"
 	"infotecstechtask/pkg/database"
+	"log/slog"
 	"math"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Реализация репозитория
 type PaymentRepository struct {
-	db *database.Client
+	db            *database.Client
+	auditRecorder audit.Recorder
+	outboxWriter  outbox.Writer
 }
 
-func NewPaymentRepository(db *database.Client) *PaymentRepository {
+func NewPaymentRepository(db *database.Client, auditRecorder audit.Recorder, outboxWriter outbox.Writer) *PaymentRepository {
 	return &PaymentRepository{
-		db: db,
+		db:            db,
+		auditRecorder: auditRecorder,
+		outboxWriter:  outboxWriter,
 	}
 }
 
+// Состояние кошельков участников перевода, фиксируемое в журнале аудита
+type transferState struct {
+	SenderBalance    int           `json:"sender_balance"`
+	RecipientBalance int           `json:"recipient_balance"`
+	Status           models.Status `json:"status,omitempty"`
+}
+
 // Реализация метода для создания транзакций
 //
 // Если не найден кошелёк отправителя или получателя возвращается ошибка и запись в БД не создается
+// Если кошелек отправителя не принадлежит вызывающей стороне с ролью ниже operator, возвращается wallet.ErrWalletNotOwned
 // Если кошельки найдены, в БД создается запись о транзакции со статусом pending и соответствующим сообщением
 //
 // В случае, когда на балансе отправителя не хватает нужной суммы для совершения транзакции,
@@ -35,7 +53,13 @@ func NewPaymentRepository(db *database.Client) *PaymentRepository {
 //
 // В случае, если все необходимые условия выполнены,
 // запись о транзакции в БД обновляется со статусом completed и соответствующим сообщением
-func (r *PaymentRepository) CreatePayment(ctx context.Context, createTransactionRequest *models.CreateTransactionRequest) (*models.TransactionResponse, error) {
+//
+// Каждая созданная запись о транзакции фиксируется в журнале аудита в той же БД транзакции,
+// там же в outbox записываются события о результате транзакции и изменении балансов
+func (r *PaymentRepository) CreatePayment(ctx context.Context, principal *models.Principal, createTransactionRequest *models.CreateTransactionRequest) (response *models.TransactionResponse, err error) {
+	ctx, span := tracing.Start(ctx, "PaymentRepository.CreatePayment")
+	defer func() { tracing.End(span, err) }()
+
 	if createTransactionRequest.FromAddress == createTransactionRequest.ToAddress {
 		return nil, payment.ErrSenderAndRecipientSame
 	}
@@ -43,20 +67,24 @@ func (r *PaymentRepository) CreatePayment(ctx context.Context, createTransaction
 	transactionAmount := int(math.Round(createTransactionRequest.Amount * 100))
 	var transaction *models.Transaction
 
-	err := r.db.ExecuteTx(ctx, func(tx pgx.Tx) error {
+	err = r.db.ExecuteTx(ctx, func(tx pgx.Tx) error {
 		var senderBalance, recipientBalance int
+		var senderOwner uuid.UUID
 
 		err := tx.QueryRow(
 			ctx,
-			`SELECT balance FROM wallets WHERE id = $1 FOR UPDATE`,
+			`SELECT balance, owner_id FROM wallets WHERE id = $1 FOR UPDATE`,
 			createTransactionRequest.FromAddress,
-		).Scan(&senderBalance)
+		).Scan(&senderBalance, &senderOwner)
 		if err != nil {
 			if errors.Is(err, pgx.ErrNoRows) {
 				return payment.ErrSenderWalletNotFound
 			}
 			return fmt.Errorf("failed to lock sender wallet: %w", err)
 		}
+		if senderOwner != principal.ID && !principal.Role.Allows(models.RoleOperator) {
+			return wallet.ErrWalletNotOwned
+		}
 
 		err = tx.QueryRow(
 			ctx,
@@ -70,20 +98,58 @@ func (r *PaymentRepository) CreatePayment(ctx context.Context, createTransaction
 			return fmt.Errorf("failed to lock recipient wallet: %w", err)
 		}
 
+		// Запись в журнал аудита и outbox с итоговым статусом и балансами, вызывается на каждом пути, где транзакция сохраняется
+		finishTransfer := func(senderBalanceAfter, recipientBalanceAfter int) error {
+			err := r.auditRecorder.Record(ctx, tx, &models.AuditChange{
+				Action:     models.AuditTransferCreate,
+				EntityType: models.AuditEntityTransaction,
+				EntityID:   transaction.ID.String(),
+				Before: transferState{
+					SenderBalance:    senderBalance,
+					RecipientBalance: recipientBalance,
+				},
+				After: transferState{
+					SenderBalance:    senderBalanceAfter,
+					RecipientBalance: recipientBalanceAfter,
+					Status:           transaction.Status,
+				},
+			})
+			if err != nil {
+				return err
+			}
+
+			var changes []outbox.BalanceChange
+			if transaction.Status == models.Completed {
+				changes = []outbox.BalanceChange{
+					{WalletID: transaction.FromAddress, Balance: senderBalanceAfter, Delta: senderBalanceAfter - senderBalance},
+					{WalletID: transaction.ToAddress, Balance: recipientBalanceAfter, Delta: recipientBalanceAfter - recipientBalance},
+				}
+			}
+			events, err := outbox.TransactionEvents(transaction, changes...)
+			if err != nil {
+				return err
+			}
+
+			return r.outboxWriter.Add(ctx, tx, events)
+		}
+
+		// pgx отбрасывает часовой пояс при записи в TIMESTAMP, поэтому время создания берется в UTC
 		transaction = &models.Transaction{
 			ID:          uuid.New(),
+			Type:        models.TypeTransfer,
 			FromAddress: uuid.MustParse(createTransactionRequest.FromAddress),
 			ToAddress:   uuid.MustParse(createTransactionRequest.ToAddress),
 			Amount:      transactionAmount,
 			Status:      models.Pending,
 			Message:     models.TRANSACTION_PENDING,
-			CreatedAt:   time.Now(),
+			CreatedAt:   time.Now().UTC(),
 		}
 
 		_, err = tx.Exec(
 			ctx,
-			`INSERT INTO transactions (id, from_address, to_address, amount, status, message, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			`INSERT INTO transactions (id, type, from_address, to_address, amount, status, message, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
 			transaction.ID,
+			transaction.Type,
 			transaction.FromAddress,
 			transaction.ToAddress,
 			transactionAmount,
@@ -108,7 +174,7 @@ func (r *PaymentRepository) CreatePayment(ctx context.Context, createTransaction
 			if err != nil {
 				return fmt.Errorf("failed to update transaction: %w", err)
 			}
-			return nil
+			return finishTransfer(senderBalance, recipientBalance)
 		}
 
 		_, err = tx.Exec(
@@ -118,6 +184,7 @@ func (r *PaymentRepository) CreatePayment(ctx context.Context, createTransaction
 			createTransactionRequest.FromAddress,
 		)
 		if err != nil {
+			slog.ErrorContext(ctx, "failed to debit sender wallet", "transaction_id", transaction.ID, "error", err)
 			transaction.Status = models.Failed
 			transaction.Message = models.TRANSACTION_FAILED
 			_, err = tx.Exec(
@@ -127,7 +194,10 @@ func (r *PaymentRepository) CreatePayment(ctx context.Context, createTransaction
 				transaction.Message,
 				transaction.ID,
 			)
-			return nil
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to mark transaction as failed", "transaction_id", transaction.ID, "error", err)
+			}
+			return finishTransfer(senderBalance, recipientBalance)
 		}
 
 		_, err = tx.Exec(
@@ -137,6 +207,7 @@ func (r *PaymentRepository) CreatePayment(ctx context.Context, createTransaction
 			createTransactionRequest.ToAddress,
 		)
 		if err != nil {
+			slog.ErrorContext(ctx, "failed to credit recipient wallet", "transaction_id", transaction.ID, "error", err)
 			transaction.Status = models.Failed
 			transaction.Message = models.TRANSACTION_FAILED
 			_, err = tx.Exec(
@@ -146,13 +217,19 @@ func (r *PaymentRepository) CreatePayment(ctx context.Context, createTransaction
 				transaction.Message,
 				transaction.ID,
 			)
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to mark transaction as failed", "transaction_id", transaction.ID, "error", err)
+			}
 			_, err = tx.Exec(
 				ctx,
 				`UPDATE wallets SET balance = balance + $1 WHERE id = $2`,
 				transactionAmount,
 				createTransactionRequest.FromAddress,
 			)
-			return nil
+			if err != nil {
+				slog.ErrorContext(ctx, "failed to refund sender wallet", "transaction_id", transaction.ID, "error", err)
+			}
+			return finishTransfer(senderBalance, recipientBalance)
 		}
 
 		transaction.Status = models.Completed
@@ -168,12 +245,17 @@ func (r *PaymentRepository) CreatePayment(ctx context.Context, createTransaction
 			return fmt.Errorf("failed to update transaction: %w", err)
 		}
 
-		return nil
+		return finishTransfer(senderBalance-transactionAmount, recipientBalance+transactionAmount)
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
+	span.SetAttributes(
+		attribute.String("transaction.id", transaction.ID.String()),
+		attribute.String("transaction.status", string(transaction.Status)),
+	)
+
 	return models.ToTransactionResponse(transaction), nil
 }
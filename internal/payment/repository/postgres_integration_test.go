@@ -3,20 +3,26 @@ package postgres
 import (
 	"context"
 	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/wallet"
 	"infotecstechtask/pkg/database"
 	"infotecstechtask/test/testutils"
 	"log"
 	"math"
-	"path/filepath"
-	"runtime"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+
+	audrepo "infotecstechtask/internal/audit/repository"
+	orepo "infotecstechtask/internal/outbox/repository"
 )
 
+// Оператор может списывать с любого кошелька
+var operator = &models.Principal{ID: uuid.New(), Role: models.RoleOperator}
+
 type PaymentRepositoryTestSuite struct {
 	suite.Suite
 	pgContainer *testutils.PGTestContainer
@@ -29,18 +35,14 @@ type PaymentRepositoryTestSuite struct {
 func (suite *PaymentRepositoryTestSuite) SetupSuite() {
 	suite.ctx = context.Background()
 
-	_, filename, _, _ := runtime.Caller(0)
-	dir := filepath.Dir(filename)
-	migrationsPath := filepath.Join(dir, "../../../init/migrations")
-
-	container, err := testutils.StartPGContainer(suite.ctx, migrationsPath)
+	container, err := testutils.StartPGContainer(suite.ctx)
 	if err != nil {
 		log.Fatalf("Failed to start test container: %v", err)
 	}
 	suite.pgContainer = container
 
 	client := database.NewClientWithPool(container.Pool)
-	suite.repo = NewPaymentRepository(client)
+	suite.repo = NewPaymentRepository(client, audrepo.NewAuditRepository(client), orepo.NewOutboxRepository(client))
 
 	suite.fixtures = testutils.NewFixtureManager(container.Pool)
 	suite.dataLoader = testutils.NewDataLoader()
@@ -55,7 +57,7 @@ func (suite *PaymentRepositoryTestSuite) TearDownSuite() {
 }
 
 func (suite *PaymentRepositoryTestSuite) BeforeTest(_, _ string) {
-	_, err := suite.pgContainer.Pool.Exec(suite.ctx, `TRUNCATE TABLE wallets, transactions CASCADE`)
+	_, err := suite.pgContainer.Pool.Exec(suite.ctx, `TRUNCATE TABLE wallets, transactions, outbox CASCADE`)
 	if err != nil {
 		log.Printf("TRUNCATE error: %v", err)
 	}
@@ -83,7 +85,7 @@ func (suite *PaymentRepositoryTestSuite) TestCreatePaymentSuccess() {
 	err = suite.dataLoader.LoadJSONFixture("payments/transaction_request.json", &request)
 	suite.Require().NoError(err)
 
-	response, err := suite.repo.CreatePayment(suite.ctx, &request)
+	response, err := suite.repo.CreatePayment(suite.ctx, operator, &request)
 	suite.Require().NoError(err)
 
 	suite.Assert().Equal(request.FromAddress, response.FromAddress.String())
@@ -114,7 +116,7 @@ func (suite *PaymentRepositoryTestSuite) TestCreatePaymentInsufficientFunds() {
 	err = suite.dataLoader.LoadJSONFixture("payments/transaction_request_insufficient_funds.json", &request)
 	suite.Require().NoError(err)
 
-	response, err := suite.repo.CreatePayment(suite.ctx, &request)
+	response, err := suite.repo.CreatePayment(suite.ctx, operator, &request)
 	suite.Require().NoError(err)
 
 	suite.Assert().Equal(request.FromAddress, response.FromAddress.String())
@@ -129,6 +131,47 @@ func (suite *PaymentRepositoryTestSuite) TestCreatePaymentInsufficientFunds() {
 	suite.verifyTransactionStatus(response.ID, models.Failed)
 }
 
+func (suite *PaymentRepositoryTestSuite) TestCreatePaymentWritesOutboxEvents() {
+	err := suite.fixtures.ApplySQLFixture(suite.ctx, "wallets/wallets.sql")
+	suite.Require().NoError(err)
+
+	var sender models.Wallet
+	err = suite.dataLoader.LoadJSONFixture("payments/addition/sender_wallet.json", &sender)
+	suite.Require().NoError(err)
+
+	var recipient models.Wallet
+	err = suite.dataLoader.LoadJSONFixture("payments/addition/recipient_wallet.json", &recipient)
+	suite.Require().NoError(err)
+
+	var request models.CreateTransactionRequest
+	err = suite.dataLoader.LoadJSONFixture("payments/transaction_request.json", &request)
+	suite.Require().NoError(err)
+
+	_, err = suite.repo.CreatePayment(suite.ctx, operator, &request)
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal(
+		[]models.EventType{models.EventTransactionCompleted, models.EventWalletBalanceChanged},
+		suite.outboxEventTypes(sender.ID),
+	)
+	suite.Assert().Equal(
+		[]models.EventType{models.EventTransactionCompleted, models.EventWalletBalanceChanged},
+		suite.outboxEventTypes(recipient.ID),
+	)
+
+	err = suite.dataLoader.LoadJSONFixture("payments/transaction_request_insufficient_funds.json", &request)
+	suite.Require().NoError(err)
+
+	_, err = suite.repo.CreatePayment(suite.ctx, operator, &request)
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal(
+		[]models.EventType{models.EventTransactionCompleted, models.EventWalletBalanceChanged, models.EventTransactionFailed},
+		suite.outboxEventTypes(sender.ID),
+	)
+	suite.Assert().Len(suite.outboxEventTypes(recipient.ID), 2)
+}
+
 func (suite *PaymentRepositoryTestSuite) TestCreatePaymentSenderNotFound() {
 	err := suite.fixtures.ApplySQLFixture(suite.ctx, "wallets/wallets.sql")
 	suite.Require().NoError(err)
@@ -145,7 +188,7 @@ func (suite *PaymentRepositoryTestSuite) TestCreatePaymentSenderNotFound() {
 		Amount:      50.0,
 	}
 
-	response, err := suite.repo.CreatePayment(suite.ctx, request)
+	response, err := suite.repo.CreatePayment(suite.ctx, operator, request)
 	suite.Assert().Nil(response)
 	suite.Assert().Contains(err.Error(), "Sender wallet not found")
 
@@ -174,7 +217,7 @@ func (suite *PaymentRepositoryTestSuite) TestCreatePaymentRecipientNotFound() {
 		Amount:      50.0,
 	}
 
-	response, err := suite.repo.CreatePayment(suite.ctx, request)
+	response, err := suite.repo.CreatePayment(suite.ctx, operator, request)
 	suite.Require().Error(err)
 	suite.Assert().Nil(response)
 	suite.Assert().Contains(err.Error(), "Recipient wallet not found")
@@ -188,6 +231,37 @@ func (suite *PaymentRepositoryTestSuite) TestCreatePaymentRecipientNotFound() {
 	suite.Assert().Equal(0, count)
 }
 
+func (suite *PaymentRepositoryTestSuite) TestCreatePaymentChecksSenderOwner() {
+	err := suite.fixtures.ApplySQLFixture(suite.ctx, "wallets/wallets.sql")
+	suite.Require().NoError(err)
+	err = suite.fixtures.ApplySQLFixture(suite.ctx, "wallets/wallet_owners.sql")
+	suite.Require().NoError(err)
+
+	owner := &models.Principal{ID: uuid.MustParse("5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e01"), Role: models.RoleClient}
+	stranger := &models.Principal{ID: uuid.MustParse("5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e02"), Role: models.RoleViewer}
+	request := &models.CreateTransactionRequest{
+		FromAddress: "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10",
+		ToAddress:   "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a13",
+		Amount:      1.0,
+	}
+
+	response, err := suite.repo.CreatePayment(suite.ctx, stranger, request)
+	suite.Assert().ErrorIs(err, wallet.ErrWalletNotOwned)
+	suite.Assert().Nil(response)
+
+	var count int
+	err = suite.pgContainer.Pool.QueryRow(suite.ctx,
+		`SELECT COUNT(*) FROM transactions WHERE from_address = $1`,
+		request.FromAddress,
+	).Scan(&count)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(0, count)
+
+	response, err = suite.repo.CreatePayment(suite.ctx, owner, request)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(models.Completed, response.Status)
+}
+
 func (suite *PaymentRepositoryTestSuite) TestCreatePaymentConcurrentTransactions() {
 	err := suite.fixtures.ApplySQLFixture(suite.ctx, "wallets/wallets.sql")
 	suite.Require().NoError(err)
@@ -212,7 +286,7 @@ func (suite *PaymentRepositoryTestSuite) TestCreatePaymentConcurrentTransactions
 		go func() {
 			defer wg.Done()
 			ctx := context.Background()
-			_, err := suite.repo.CreatePayment(ctx, &request)
+			_, err := suite.repo.CreatePayment(ctx, operator, &request)
 			suite.Require().NoError(err)
 		}()
 	}
@@ -237,6 +311,70 @@ func (suite *PaymentRepositoryTestSuite) TestCreatePaymentConcurrentTransactions
 	suite.Assert().Equal(concurrency, txCount)
 }
 
+// Переводы с разных кошельков не ждут друг друга на общей блокировке журнала аудита, каждый оставляет в нем запись
+func (suite *PaymentRepositoryTestSuite) TestCreatePaymentConcurrentSendersRecordAudit() {
+	err := suite.fixtures.ApplySQLFixture(suite.ctx, "wallets/wallets.sql")
+	suite.Require().NoError(err)
+
+	senders := []string{
+		"b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10",
+		"b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11",
+		"b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12",
+		"b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a13",
+	}
+	var wg sync.WaitGroup
+	wg.Add(len(senders))
+
+	for _, sender := range senders {
+		go func() {
+			defer wg.Done()
+			response, err := suite.repo.CreatePayment(context.Background(), operator, &models.CreateTransactionRequest{
+				FromAddress: sender,
+				ToAddress:   "b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a14",
+				Amount:      15,
+			})
+			suite.Require().NoError(err)
+			suite.Assert().Equal(models.Completed, response.Status)
+		}()
+	}
+
+	wg.Wait()
+
+	var recorded int
+	err = suite.pgContainer.Pool.QueryRow(suite.ctx,
+		`SELECT COUNT(*) FROM audit_log WHERE action = $1 AND entity_id IN (SELECT id::VARCHAR FROM transactions)`,
+		models.AuditTransferCreate,
+	).Scan(&recorded)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(len(senders), recorded)
+}
+
+// Время создания хранится в UTC и при локальном поясе сервера, отличном от UTC,
+// иначе перевод не попадет в выписку и выгрузку за период, границы которого переводятся в UTC
+func (suite *PaymentRepositoryTestSuite) TestCreatePaymentStoresCreatedAtInUTC() {
+	testutils.SetNonUTCLocal(suite.T())
+
+	err := suite.fixtures.ApplySQLFixture(suite.ctx, "wallets/wallets.sql")
+	suite.Require().NoError(err)
+
+	var request models.CreateTransactionRequest
+	err = suite.dataLoader.LoadJSONFixture("payments/transaction_request.json", &request)
+	suite.Require().NoError(err)
+
+	from := time.Now().UTC().Add(-time.Minute)
+	response, err := suite.repo.CreatePayment(suite.ctx, operator, &request)
+	suite.Require().NoError(err)
+	to := time.Now().UTC().Add(time.Minute)
+
+	var found int
+	err = suite.pgContainer.Pool.QueryRow(suite.ctx,
+		`SELECT COUNT(*) FROM transactions WHERE id = $1 AND created_at >= $2 AND created_at < $3`,
+		response.ID, from, to,
+	).Scan(&found)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(1, found)
+}
+
 func (suite *PaymentRepositoryTestSuite) verifyWalletBalance(id uuid.UUID, expected int) {
 	log.Printf("expected balance - %d", expected)
 	var balance int
@@ -259,3 +397,23 @@ func (suite *PaymentRepositoryTestSuite) verifyTransactionStatus(id uuid.UUID, e
 	suite.Require().NoError(err)
 	suite.Assert().Equal(expected, status)
 }
+
+func (suite *PaymentRepositoryTestSuite) outboxEventTypes(walletId uuid.UUID) []models.EventType {
+	rows, err := suite.pgContainer.Pool.Query(
+		context.Background(),
+		"SELECT event_type FROM outbox WHERE wallet_id = $1 ORDER BY id",
+		walletId,
+	)
+	suite.Require().NoError(err)
+	defer rows.Close()
+
+	eventTypes := []models.EventType{}
+	for rows.Next() {
+		var eventType models.EventType
+		suite.Require().NoError(rows.Scan(&eventType))
+		eventTypes = append(eventTypes, eventType)
+	}
+	suite.Require().NoError(rows.Err())
+
+	return eventTypes
+}
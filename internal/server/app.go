@@ -2,99 +2,284 @@ package server
 
 import (
 	"context"
+	"infotecstechtask/init/migrations"
+	"infotecstechtask/internal/config"
 	"infotecstechtask/internal/facade"
+	"infotecstechtask/internal/health"
+	"infotecstechtask/internal/metrics"
 	"infotecstechtask/internal/middleware"
+	"infotecstechtask/internal/tracing"
 	"infotecstechtask/pkg/database"
-	"log"
+	"infotecstechtask/pkg/lifecycle"
+	"log/slog"
 	"net/http"
 	"os"
-	"os/signal"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/ulule/limiter/v3"
 	"github.com/ulule/limiter/v3/drivers/store/memory"
 
+	adjrepo "infotecstechtask/internal/adjustment/repository"
+	adjservice "infotecstechtask/internal/adjustment/service"
+	audrepo "infotecstechtask/internal/audit/repository"
+	arepo "infotecstechtask/internal/auth/repository"
+	aservice "infotecstechtask/internal/auth/service"
+	brepo "infotecstechtask/internal/balance/repository"
+	bservice "infotecstechtask/internal/balance/service"
+	bsnapshotter "infotecstechtask/internal/balance/snapshotter"
+	dgrpc "infotecstechtask/internal/delivery/grpc"
 	dhttp "infotecstechtask/internal/delivery/http"
+	opublisher "infotecstechtask/internal/outbox/publisher"
+	orelay "infotecstechtask/internal/outbox/relay"
+	orepo "infotecstechtask/internal/outbox/repository"
 	prepo "infotecstechtask/internal/payment/repository"
+	stgenerator "infotecstechtask/internal/statement/generator"
+	strepo "infotecstechtask/internal/statement/repository"
+	stservice "infotecstechtask/internal/statement/service"
+	sbroker "infotecstechtask/internal/stream/broker"
 	trepo "infotecstechtask/internal/transaction/repository"
 	tservice "infotecstechtask/internal/transaction/service"
 	wrepo "infotecstechtask/internal/wallet/repository"
 	wservice "infotecstechtask/internal/wallet/service"
+	whdispatcher "infotecstechtask/internal/webhook/dispatcher"
+	whrepo "infotecstechtask/internal/webhook/repository"
+	whservice "infotecstechtask/internal/webhook/service"
 )
 
-// Структура, хранящая в себе указатель на http сервер и экземпляр фасада
+// Имена фоновых процессов в проверке готовности
+const (
+	relayWorker      = "outbox_relay"
+	dispatcherWorker = "webhook_dispatcher"
+	brokerWorker     = "event_broker"
+	statementWorker  = "statement_generator"
+	snapshotWorker   = "balance_snapshotter"
+)
+
+// Структура, хранящая в себе указатели на http и gRPC серверы, клиент БД, экземпляр фасада, фоновые процессы публикации событий,
+// формирования выписок и снимков баланса
+// metricsServer создается, только если для метрик задан отдельный адрес, grpcServer - если задан grpc.port
 type App struct {
-	httpServer *http.Server
+	config         config.Config
+	httpServer     *http.Server
+	grpcServer     *dgrpc.Server
+	metricsServer  *http.Server
+	dbClient       *database.Client
+	shutdownTracer func(ctx context.Context) error
 
-	facade facade.TransactionFacade
+	checker *health.Checker
+	workers *health.Workers
+
+	facade      facade.TransactionFacade
+	relay       *orelay.Relay
+	dispatcher  *whdispatcher.Dispatcher
+	broker      *sbroker.Broker
+	generator   *stgenerator.Generator
+	snapshotter *bsnapshotter.Snapshotter
 }
 
-func NewApp() *App {
-	config := database.LoadConfig()
+func NewApp(config config.Config) *App {
+	// Попытки подключения к БД ограничены database.connect_timeout
+	dbClient, err := database.NewClient(context.Background(), config.Database)
+	if err != nil {
+		fatal("Failed to create DB client", err)
+	}
+
+	if config.Migrations.Auto {
+		migrate(dbClient)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	dbClient, err := database.NewClient(ctx, config)
+	prometheus.MustRegister(metrics.NewDatabaseCollector(dbClient))
+
+	shutdownTracer, err := tracing.Setup(ctx, config.Tracing)
+	if err != nil {
+		fatal("Failed to set up tracing", err)
+	}
+
+	eventPublisher, err := opublisher.New(config.Outbox)
 	if err != nil {
-		log.Fatal("Failed to create DB client: %w", err)
+		fatal("Failed to create event publisher", err)
 	}
 
+	auditRepository := audrepo.NewAuditRepository(dbClient)
+	outboxRepository := orepo.NewOutboxRepository(dbClient)
+	webhookRepository := whrepo.NewWebhookRepository(dbClient)
+	authRepository := arepo.NewAuthRepository(dbClient)
 	walletRepository := wrepo.NewWalletRepository(dbClient)
 	transactionRepository := trepo.NewTransactionRepository(dbClient)
-	paymentRepository := prepo.NewPaymentRepository(dbClient)
+	adjustmentRepository := adjrepo.NewAdjustmentRepository(dbClient, auditRepository, outboxRepository)
+	paymentRepository := prepo.NewPaymentRepository(dbClient, auditRepository, outboxRepository)
+	statementRepository := strepo.NewStatementRepository(dbClient)
+	balanceRepository := brepo.NewBalanceRepository(dbClient)
 
+	authService := aservice.NewAuthService(authRepository)
 	walletService := wservice.NewWalletService(walletRepository)
-	transactionService := tservice.NewTransactionService(transactionRepository)
+	transactionService := tservice.NewTransactionService(transactionRepository, walletRepository)
+	adjustmentService := adjservice.NewAdjustmentService(adjustmentRepository)
+	webhookService := whservice.NewWebhookService(webhookRepository)
+	statementService := stservice.NewStatementService(statementRepository, walletRepository)
+	balanceService := bservice.NewBalanceService(balanceRepository, walletRepository)
+	eventBroker := sbroker.NewBroker(dbClient, outboxRepository, walletRepository)
+
+	workers := health.NewWorkers()
+	for _, name := range []string{relayWorker, dispatcherWorker, brokerWorker, statementWorker, snapshotWorker} {
+		workers.Register(name)
+	}
+
+	checker := health.NewChecker()
+	checker.Add("database", health.DatabaseCheck(dbClient))
+	checker.Add("migrations", health.MigrationsCheck(dbClient, database.SchemaVersion))
+	checker.Add("workers", workers.Check)
+
+	var metricsServer *http.Server
+	if config.Metrics.Addr != "" {
+		metricsServer = metrics.NewServer(config.Metrics.Addr)
+	}
 
 	return &App{
-		facade: *facade.NewFacade(walletService, transactionService, paymentRepository),
+		config:         config,
+		metricsServer:  metricsServer,
+		dbClient:       dbClient,
+		shutdownTracer: shutdownTracer,
+		checker:        checker,
+		workers:        workers,
+		facade:         *facade.NewFacade(authService, walletService, transactionService, adjustmentService, webhookService, eventBroker, statementService, balanceService, paymentRepository),
+		relay: orelay.NewRelay(
+			outboxRepository,
+			opublisher.NewMultiPublisher(eventPublisher, whdispatcher.NewScheduler(webhookRepository)),
+			dbClient,
+			config.OutboxRelay,
+		),
+		dispatcher:  whdispatcher.NewDispatcher(webhookRepository, config.Webhooks),
+		broker:      eventBroker,
+		generator:   stgenerator.NewGenerator(statementRepository, dbClient, config.Statements),
+		snapshotter: bsnapshotter.NewSnapshotter(balanceRepository, dbClient, config.BalanceSnapshots),
 	}
 }
 
-// Функция для запуска http сервера
-// Реализован базовый механизм graceful shutdown
-func (a App) Run(port string) error {
+// Функция для запуска приложения, возвращает код завершения процесса
+//
+// Вместе с http сервером запускаются gRPC сервер (если задан grpc.port), relay, отправка webhook, брокер событий, формирование выписок и снимки баланса.
+// При получении SIGINT/SIGTERM готовность переключается в fail, через health.drain_delay серверы перестают принимать
+// соединения и дожидаются текущих запросов, затем останавливаются фоновые процессы, отправляются трейсы и закрывается пул соединений.
+// На всю остановку отводится shutdown.timeout
+func (a App) Run() int {
 	rate := limiter.Rate{
-		Period: 1 * time.Minute,
-		Limit:  100,
+		Period: a.config.RateLimit.Period,
+		Limit:  a.config.RateLimit.Requests,
 	}
 	store := memory.NewStore()
 	limiterInstance := limiter.New(store, rate)
 
-	router := gin.Default()
+	timeouts := a.config.Timeouts
+	// Потоки событий живут до закрытия соединения клиентом, поэтому таймаут для них не устанавливается
+	timeouts.Disable(http.MethodGet, dhttp.FULL_WALLET_EVENTS)
+	timeouts.Disable(http.MethodGet, dhttp.FULL_TRANSACTION_FEED)
+
+	router := gin.New()
+	// Проверки регистрируются до миддлваров, чтобы пробы оркестратора не упирались в рейт лимитер и не засоряли логи
+	health.Register(router, a.checker)
 	router.Use(
-		gin.Recovery(),
-		gin.Logger(),
+		middleware.RequestID(),
+		middleware.Localization(),
+		middleware.Logger(),
+		middleware.Recovery(),
+		middleware.Tracing(),
+		middleware.Metrics(),
 		middleware.RateLimiter(limiterInstance),
+		middleware.Timeout(timeouts),
+		middleware.ReadConsistency(),
 	)
+	router.NoRoute(middleware.NoRoute())
+
+	if a.metricsServer == nil {
+		router.GET(metrics.Path, gin.WrapH(metrics.Handler()))
+	}
 
 	validate := validator.New()
 
+	dhttp.RegisterDocsEndpoints(&router.RouterGroup)
 	dhttp.RegisterHTTPEndpoints(&router.RouterGroup, a.facade, validate)
 
 	a.httpServer = &http.Server{
-		Addr:           ":" + port,
+		Addr:           ":" + a.config.Server.Port,
 		Handler:        router,
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
-		MaxHeaderBytes: 1 << 20,
+		ReadTimeout:    a.config.Server.ReadTimeout,
+		WriteTimeout:   a.config.Server.WriteTimeout,
+		MaxHeaderBytes: a.config.Server.MaxHeaderBytes,
 	}
+	// Потоки SSE и WebSocket не завершаются сами, поэтому при остановке сервера брокер закрывает все подписки
+	a.httpServer.RegisterOnShutdown(a.broker.Close)
 
-	go func() {
-		if err := a.httpServer.ListenAndServe(); err != nil {
-			log.Fatalf("Failed to listen and serve: %+v", err)
+	manager := lifecycle.New(a.config.Shutdown)
+	manager.AddServer("http", a.httpServer)
+	if a.config.GRPC.Port != "" {
+		a.grpcServer = dgrpc.NewServer(a.config.GRPC, a.facade)
+		a.grpcServer.RegisterOnShutdown(a.broker.Close)
+		manager.AddServer("grpc", a.grpcServer)
+	}
+	if a.metricsServer != nil {
+		manager.AddServer("metrics", a.metricsServer)
+	}
+
+	// Процессы останавливаются в обратном порядке: сначала снимки баланса, формирование выписок и relay,
+	// затем останавливается отправка webhook и последним брокер событий
+	for _, w := range []struct {
+		name string
+		run  func(ctx context.Context)
+	}{
+		{brokerWorker, a.broker.Run},
+		{dispatcherWorker, a.dispatcher.Run},
+		{relayWorker, a.relay.Run},
+		{statementWorker, a.generator.Run},
+		{snapshotWorker, a.snapshotter.Run},
+	} {
+		manager.AddWorker(w.name, func(ctx context.Context) {
+			a.workers.Run(ctx, w.name, w.run)
+		})
+	}
+
+	// Сначала готовность переключается в fail, и балансировщик успевает вывести экземпляр из ротации
+	manager.BeforeShutdown("readiness", func(ctx context.Context) error {
+		a.checker.Shutdown()
+		select {
+		case <-time.After(a.config.Health.DrainDelay):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
 		}
-	}()
+	})
+	manager.OnShutdown("database", func(context.Context) error {
+		a.dbClient.Close()
+		return nil
+	})
+	manager.OnShutdown("tracing", a.shutdownTracer)
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, os.Interrupt)
+	return manager.Run(context.Background())
+}
 
-	<-quit
+// Функция применяет встроенные миграции
+// Несколько экземпляров могут стартовать одновременно: миграции выполняет первый, остальные ждут его на advisory блокировке
+func migrate(dbClient *database.Client) {
+	migrator, err := database.NewMigrator(dbClient, migrations.FS)
+	if err != nil {
+		fatal("Failed to load migrations", err)
+	}
 
-	ctx, shutdown := context.WithTimeout(context.Background(), 5*time.Second)
-	defer shutdown()
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		fatal("Failed to apply migrations", err)
+	}
+	slog.Info("schema is up to date", "applied", applied)
+}
 
-	return a.httpServer.Shutdown(ctx)
+// Функция логирует ошибку, после которой работа приложения невозможна, и завершает процесс
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -0,0 +1,6 @@
+package statement
+
+import "errors"
+
+// Список возможных ошибок бизнес-логики выписок
+var ErrPeriodTooLong = errors.New("Statement period must not exceed 366 days")
@@ -0,0 +1,12 @@
+package generator
+
+import "time"
+
+// Структура, хранящая в себе параметры формирования выписок
+// Значения собираются пакетом internal/config
+//
+// Interval - период проверки, есть ли кошельки без выписки за прошлый месяц, BatchSize - количество кошельков в одном запросе
+type Config struct {
+	Interval  time.Duration
+	BatchSize int
+}
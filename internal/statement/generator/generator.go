@@ -0,0 +1,92 @@
+package generator
+
+import (
+	"context"
+	"infotecstechtask/internal/statement"
+	"log/slog"
+	"time"
+)
+
+// Ключ advisory блокировки, которую удерживает генератор во время формирования выписок
+// Благодаря ей при нескольких экземплярах приложения одну и ту же выписку не собирают параллельно
+const generatorLockKey int64 = 7_263_002
+
+// Интерфейс для выполнения функции под распределенной блокировкой
+type Locker interface {
+	WithAdvisoryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error)
+}
+
+// Фоновый процесс, заранее формирующий выписки за прошлый календарный месяц
+//
+// Выписки формируются для всех кошельков пачками по BatchSize и сохраняются в БД,
+// после чего API отдает их без повторного чтения транзакций.
+// Если формирование прервалось, оставшиеся кошельки обрабатываются на следующем запуске
+type Generator struct {
+	repository statement.Repository
+	locker     Locker
+	interval   time.Duration
+	batchSize  int
+	now        func() time.Time
+}
+
+func NewGenerator(repository statement.Repository, locker Locker, config Config) *Generator {
+	return &Generator{
+		repository: repository,
+		locker:     locker,
+		interval:   config.Interval,
+		batchSize:  config.BatchSize,
+		now:        time.Now,
+	}
+}
+
+// Функция запускает цикл формирования выписок и завершается при отмене контекста
+func (g *Generator) Run(ctx context.Context) {
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := g.locker.WithAdvisoryLock(ctx, generatorLockKey, g.generatePending)
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "statement generator failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Функция формирует выписки за прошлый месяц для всех кошельков, у которых их еще нет
+func (g *Generator) generatePending(ctx context.Context) error {
+	from, to := statement.PreviousMonth(g.now())
+
+	generated := 0
+	for {
+		walletIds, err := g.repository.GetWalletsWithoutStatement(ctx, from, to, g.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, walletId := range walletIds {
+			result, err := g.repository.BuildStatement(ctx, walletId, from, to)
+			if err != nil {
+				return err
+			}
+			if err := g.repository.SaveStatement(ctx, result); err != nil {
+				return err
+			}
+			generated++
+		}
+
+		if len(walletIds) < g.batchSize {
+			break
+		}
+	}
+
+	if generated > 0 {
+		slog.InfoContext(ctx, "statements generated", "from", from, "to", to, "count", generated)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"infotecstechtask/internal/models"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepository struct {
+	wallets []uuid.UUID
+	saved   []*models.Statement
+	failing map[uuid.UUID]bool
+}
+
+func (r *fakeRepository) BuildStatement(_ context.Context, walletId uuid.UUID, from time.Time, to time.Time) (*models.Statement, error) {
+	if r.failing[walletId] {
+		return nil, errors.New("unavailable")
+	}
+	return models.NewStatement(walletId, from, to, 0, nil, to), nil
+}
+
+func (r *fakeRepository) GetStatement(context.Context, uuid.UUID, time.Time, time.Time) (*models.Statement, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *fakeRepository) SaveStatement(_ context.Context, statement *models.Statement) error {
+	r.saved = append(r.saved, statement)
+	return nil
+}
+
+func (r *fakeRepository) GetWalletsWithoutStatement(_ context.Context, from time.Time, to time.Time, limit int) ([]uuid.UUID, error) {
+	var pending []uuid.UUID
+	for _, walletId := range r.wallets {
+		if !r.hasStatement(walletId, from, to) {
+			pending = append(pending, walletId)
+		}
+	}
+	return pending[:min(limit, len(pending))], nil
+}
+
+func (r *fakeRepository) hasStatement(walletId uuid.UUID, from time.Time, to time.Time) bool {
+	for _, statement := range r.saved {
+		if statement.WalletID == walletId && statement.From.Equal(from) && statement.To.Equal(to) {
+			return true
+		}
+	}
+	return false
+}
+
+type fakeLocker struct{}
+
+func (fakeLocker) WithAdvisoryLock(ctx context.Context, _ int64, fn func(ctx context.Context) error) (bool, error) {
+	return true, fn(ctx)
+}
+
+func TestGeneratePendingCoversAllWallets(t *testing.T) {
+	repository := &fakeRepository{wallets: []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}}
+	generator := NewGenerator(repository, fakeLocker{}, Config{Interval: time.Hour, BatchSize: 2})
+	generator.now = func() time.Time { return time.Date(2026, time.March, 15, 12, 0, 0, 0, time.UTC) }
+
+	require.NoError(t, generator.generatePending(context.Background()))
+	require.NoError(t, generator.generatePending(context.Background()))
+
+	require.Len(t, repository.saved, 3)
+	for i, statement := range repository.saved {
+		assert.Equal(t, repository.wallets[i], statement.WalletID)
+		assert.Equal(t, time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC), statement.From)
+		assert.Equal(t, time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC), statement.To)
+	}
+}
+
+func TestGeneratePendingStopsOnError(t *testing.T) {
+	repository := &fakeRepository{wallets: []uuid.UUID{uuid.New(), uuid.New()}}
+	repository.failing = map[uuid.UUID]bool{repository.wallets[0]: true}
+
+	err := NewGenerator(repository, fakeLocker{}, Config{Interval: time.Hour, BatchSize: 10}).generatePending(context.Background())
+
+	assert.Error(t, err)
+	assert.Empty(t, repository.saved)
+}
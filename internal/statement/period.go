@@ -0,0 +1,14 @@
+package statement
+
+import "time"
+
+// Максимальная длина периода выписки, выписка собирается в памяти целиком
+const MaxPeriod = 366 * 24 * time.Hour
+
+// Функция возвращает прошлый календарный месяц относительно now в UTC как полуинтервал [from, to)
+func PreviousMonth(now time.Time) (time.Time, time.Time) {
+	now = now.UTC()
+	to := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	return to.AddDate(0, -1, 0), to
+}
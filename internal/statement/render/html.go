@@ -0,0 +1,51 @@
+package render
+
+import (
+	"html/template"
+	"infotecstechtask/internal/i18n"
+	"infotecstechtask/internal/models"
+	"io"
+)
+
+// Шаблон HTML выписки, стили встроены, чтобы выписку можно было сохранить одним файлом
+var htmlTemplate = template.Must(template.New("statement").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; font-size: 14px; margin: 24px; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; }
+.summary th { border: none; padding-left: 0; }
+.summary td { border: none; }
+.lines { width: 100%; margin-top: 16px; }
+.lines th { background: #eee; }
+.amount { text-align: right; white-space: nowrap; }
+footer { margin-top: 16px; color: #666; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table class="summary">
+{{- range .Summary}}
+<tr><th>{{index . 0}}</th><td>{{index . 1}}</td></tr>
+{{- end}}
+</table>
+<table class="lines">
+<tr>{{range .Columns}}<th>{{.}}</th>{{end}}</tr>
+{{- range .Rows}}
+<tr>{{range $i, $value := .}}<td{{if or (eq $i 3) (eq $i 4)}} class="amount"{{end}}>{{$value}}</td>{{end}}</tr>
+{{- else}}
+<tr><td colspan="{{len .Columns}}">{{.Empty}}</td></tr>
+{{- end}}
+</table>
+<footer>{{.Footnote}}</footer>
+</body>
+</html>
+`))
+
+// Функция выводит выписку в формате HTML на указанном языке
+func HTML(w io.Writer, statement *models.StatementResponse, lang i18n.Language) error {
+	return htmlTemplate.Execute(w, newView(statement, lang))
+}
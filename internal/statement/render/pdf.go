@@ -0,0 +1,102 @@
+package render
+
+import (
+	"infotecstechtask/internal/i18n"
+	"infotecstechtask/internal/models"
+	"io"
+
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// Шрифты Go встраиваются в документ целиком, они поддерживают кириллицу и не требуют файлов на диске
+const fontFamily = "go"
+
+// Параметры страницы PDF в миллиметрах
+const (
+	pageMargin = 10.0
+	lineHeight = 6.0
+)
+
+// Ширины колонок таблицы транзакций в порядке columnKeys, в сумме - ширина альбомного A4 без полей
+var columnWidths = []float64{32, 24, 64, 24, 24, 45, 64}
+
+// Функция выводит выписку в формате PDF на указанном языке
+// Шапка таблицы повторяется на каждой странице
+func PDF(w io.Writer, statement *models.StatementResponse, lang i18n.Language) error {
+	v := newView(statement, lang)
+
+	pdf := fpdf.New("L", "mm", "A4", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(false, pageMargin)
+	pdf.SetTitle(v.Title, true)
+	pdf.AddUTF8FontFromBytes(fontFamily, "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes(fontFamily, "B", gobold.TTF)
+	pdf.AddPage()
+
+	pdf.SetFont(fontFamily, "B", 16)
+	pdf.CellFormat(0, 10, v.Title, "", 1, "L", false, 0, "")
+
+	pdf.SetFont(fontFamily, "", 10)
+	for _, item := range v.Summary {
+		pdf.SetFont(fontFamily, "B", 10)
+		pdf.CellFormat(50, lineHeight, item[0], "", 0, "L", false, 0, "")
+		pdf.SetFont(fontFamily, "", 10)
+		pdf.CellFormat(0, lineHeight, item[1], "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(lineHeight)
+
+	_, pageHeight := pdf.GetPageSize()
+	writeColumns(pdf, v.Columns)
+	pdf.SetFont(fontFamily, "", 8)
+	for _, row := range v.Rows {
+		if pdf.GetY()+2*lineHeight > pageHeight-pageMargin {
+			pdf.AddPage()
+			writeColumns(pdf, v.Columns)
+			pdf.SetFont(fontFamily, "", 8)
+		}
+		for i, value := range row {
+			align := "L"
+			if i == 3 || i == 4 {
+				align = "R"
+			}
+			pdf.CellFormat(columnWidths[i], lineHeight, truncate(pdf, value, columnWidths[i]), "1", 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	if len(v.Rows) == 0 {
+		pdf.CellFormat(0, lineHeight, v.Empty, "1", 1, "L", false, 0, "")
+	}
+
+	pdf.Ln(lineHeight)
+	pdf.CellFormat(0, lineHeight, v.Footnote, "", 1, "L", false, 0, "")
+
+	return pdf.Output(w)
+}
+
+func writeColumns(pdf *fpdf.Fpdf, columns []string) {
+	pdf.SetFont(fontFamily, "B", 8)
+	pdf.SetFillColor(238, 238, 238)
+	for i, column := range columns {
+		pdf.CellFormat(columnWidths[i], lineHeight, column, "1", 0, "L", true, 0, "")
+	}
+	pdf.Ln(-1)
+}
+
+// Ячейки PDF не переносят строки, поэтому слишком длинное значение обрезается по ширине колонки
+func truncate(pdf *fpdf.Fpdf, value string, width float64) string {
+	const ellipsis = "..."
+
+	width -= 2 * pdf.GetCellMargin()
+	if pdf.GetStringWidth(value) <= width {
+		return value
+	}
+
+	runes := []rune(value)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+ellipsis) > width {
+		runes = runes[:len(runes)-1]
+	}
+
+	return string(runes) + ellipsis
+}
@@ -0,0 +1,84 @@
+package render
+
+import (
+	"bytes"
+	"infotecstechtask/internal/i18n"
+	"infotecstechtask/internal/models"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStatement() *models.StatementResponse {
+	walletId, counterparty := uuid.New(), uuid.New()
+	from := time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	return models.ToStatementResponse(models.NewStatement(walletId, from, to, 10000, []models.StatementLine{
+		{TransactionID: uuid.New(), Type: models.TypeTransfer, Counterparty: counterparty, Amount: 2550, MessageCode: "TRANSACTION_COMPLETED", CreatedAt: from.Add(time.Hour)},
+		{TransactionID: uuid.New(), Type: models.TypeAdjustment, Amount: -1000, MessageCode: "<script>", CreatedAt: from.Add(2 * time.Hour)},
+	}, to))
+}
+
+func TestHTML(t *testing.T) {
+	statement := testStatement()
+
+	var buf bytes.Buffer
+	require.NoError(t, HTML(&buf, statement, i18n.Russian))
+
+	body := buf.String()
+	assert.Contains(t, body, `<html lang="ru">`)
+	assert.Contains(t, body, "Выписка по кошельку")
+	assert.Contains(t, body, statement.WalletID.String())
+	assert.Contains(t, body, "Транзакция выполнена")
+	assert.Contains(t, body, "Корректировка")
+	for _, amount := range []string{"100.00", "25.50", "125.50", "-10.00", "115.50"} {
+		assert.Contains(t, body, amount)
+	}
+	assert.NotContains(t, body, "<script>")
+}
+
+func TestHTMLWithoutTransactions(t *testing.T) {
+	statement := testStatement()
+	statement.Lines = nil
+
+	var buf bytes.Buffer
+	require.NoError(t, HTML(&buf, statement, i18n.English))
+
+	assert.Contains(t, buf.String(), "No transactions in the period")
+}
+
+func TestPDF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, PDF(&buf, testStatement(), i18n.Russian))
+
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+	assert.Equal(t, 1, pageCount(t, buf.Bytes()))
+}
+
+func TestPDFSplitsLongStatementIntoPages(t *testing.T) {
+	statement := testStatement()
+	for i := 0; i < 100; i++ {
+		statement.Lines = append(statement.Lines, statement.Lines[0])
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, PDF(&buf, statement, i18n.English))
+
+	assert.Greater(t, pageCount(t, buf.Bytes()), 2)
+}
+
+// Количество страниц берется из несжатого словаря /Pages документа
+func pageCount(t *testing.T, document []byte) int {
+	match := regexp.MustCompile(`/Count (\d+)`).FindSubmatch(document)
+	require.NotNil(t, match)
+
+	count, err := strconv.Atoi(string(match[1]))
+	require.NoError(t, err)
+	return count
+}
@@ -0,0 +1,92 @@
+package render
+
+import (
+	"infotecstechtask/internal/i18n"
+	"infotecstechtask/internal/models"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Формат времени в выписке, время выводится в UTC
+const timeLayout = "2006-01-02 15:04:05"
+
+// Выписка, подготовленная к выводу: все значения уже отформатированы и переведены на язык клиента
+// HTML и PDF строятся из одного представления, поэтому содержат одинаковые данные
+type view struct {
+	Lang     i18n.Language
+	Title    string
+	Summary  [][2]string
+	Columns  []string
+	Rows     [][]string
+	Empty    string
+	Footnote string
+}
+
+// Колонки таблицы транзакций, порядок совпадает с порядком значений в строках view.Rows
+var columnKeys = []string{
+	"statement.date",
+	"statement.type",
+	"statement.counterparty",
+	"statement.amount",
+	"statement.balance",
+	"statement.message",
+	"statement.transaction",
+}
+
+func newView(statement *models.StatementResponse, lang i18n.Language) *view {
+	statement.Localize(lang)
+
+	v := &view{
+		Lang:  lang,
+		Title: i18n.Translate(lang, "statement.title"),
+		Summary: [][2]string{
+			{i18n.Translate(lang, "statement.wallet"), statement.WalletID.String()},
+			{i18n.Translate(lang, "statement.period"), formatTime(statement.From) + " - " + formatTime(statement.To) + " UTC"},
+			{i18n.Translate(lang, "statement.opening_balance"), formatAmount(statement.OpeningBalance)},
+			{i18n.Translate(lang, "statement.total_in"), formatAmount(statement.TotalIn)},
+			{i18n.Translate(lang, "statement.total_out"), formatAmount(statement.TotalOut)},
+			{i18n.Translate(lang, "statement.closing_balance"), formatAmount(statement.ClosingBalance)},
+		},
+		Columns:  make([]string, 0, len(columnKeys)),
+		Rows:     make([][]string, 0, len(statement.Lines)),
+		Empty:    i18n.Translate(lang, "statement.empty"),
+		Footnote: i18n.Translate(lang, "statement.generated_at") + ": " + formatTime(statement.GeneratedAt) + " UTC",
+	}
+
+	for _, key := range columnKeys {
+		v.Columns = append(v.Columns, i18n.Translate(lang, key))
+	}
+	for _, line := range statement.Lines {
+		v.Rows = append(v.Rows, []string{
+			formatTime(line.CreatedAt),
+			i18n.Translate(lang, "statement.type."+string(line.Type)),
+			formatCounterparty(line.Counterparty),
+			formatAmount(line.Amount),
+			formatAmount(line.Balance),
+			line.Message,
+			line.TransactionID.String(),
+		})
+	}
+
+	return v
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// Суммы выводятся в рублях с двумя знаками после точки, списания - со знаком минус
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// У корректировок нет другой стороны, вместо нулевого идентификатора выводится прочерк
+func formatCounterparty(counterparty uuid.UUID) string {
+	if counterparty == uuid.Nil {
+		return "-"
+	}
+
+	return counterparty.String()
+}
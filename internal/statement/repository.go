@@ -0,0 +1,18 @@
+package statement
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Интерфейс репозитория выписок
+// BuildStatement собирает выписку по транзакциям, остальные методы работают с уже сформированными выписками
+type Repository interface {
+	BuildStatement(ctx context.Context, walletId uuid.UUID, from time.Time, to time.Time) (*models.Statement, error)
+	GetStatement(ctx context.Context, walletId uuid.UUID, from time.Time, to time.Time) (*models.Statement, error)
+	SaveStatement(ctx context.Context, statement *models.Statement) error
+	GetWalletsWithoutStatement(ctx context.Context, from time.Time, to time.Time, limit int) ([]uuid.UUID, error)
+}
@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/pkg/database"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+// Реализация репозитория
+// Выписка собирается в одной транзакции, поэтому входящий остаток и транзакции периода согласованы между собой
+type StatementRepository struct {
+	db *database.Client
+}
+
+func NewStatementRepository(db *database.Client) *StatementRepository {
+	return &StatementRepository{
+		db: db,
+	}
+}
+
+// Реализация метода для сборки выписки по транзакциям
+//
+// Входящий остаток считается от текущего баланса за вычетом проведенных после начала периода транзакций,
+// поэтому выписка верна и для кошельков, баланс которых был задан без транзакций.
+// Учитываются только проведенные транзакции, они упорядочиваются по времени создания и id.
+// Если кошелек не найден, возвращается pgx.ErrNoRows
+func (r StatementRepository) BuildStatement(ctx context.Context, walletId uuid.UUID, from time.Time, to time.Time) (*models.Statement, error) {
+	from, to = from.UTC(), to.UTC()
+
+	var openingBalance int
+	var lines []models.StatementLine
+	err := r.db.ExecuteTx(ctx, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx,
+			`SELECT w.balance - COALESCE(SUM(CASE WHEN t.to_address = w.id THEN t.amount ELSE -t.amount END), 0)
+             FROM wallets w
+             LEFT JOIN transactions t ON (t.from_address = w.id OR t.to_address = w.id)
+                                     AND t.status = $2
+                                     AND t.created_at >= $3
+             WHERE w.id = $1
+             GROUP BY w.id, w.balance`,
+			walletId, models.Completed, from,
+		).Scan(&openingBalance)
+		if err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx,
+			`SELECT id, type, from_address, to_address, amount, message, created_at
+             FROM transactions
+             WHERE (from_address = $1 OR to_address = $1)
+               AND status = $2
+               AND created_at >= $3
+               AND created_at < $4
+             ORDER BY created_at, id`,
+			walletId, models.Completed, from, to,
+		)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		lines = nil
+		for rows.Next() {
+			var t models.Transaction
+			err := rows.Scan(&t.ID, &t.Type, &t.FromAddress, &t.ToAddress, &t.Amount, &t.Message, &t.CreatedAt)
+			if err != nil {
+				return err
+			}
+			lines = append(lines, toStatementLine(walletId, &t))
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return models.NewStatement(walletId, from, to, openingBalance, lines, time.Now().UTC()), nil
+}
+
+// Зачисления идут в выписку с положительной суммой, списания - с отрицательной
+func toStatementLine(walletId uuid.UUID, t *models.Transaction) models.StatementLine {
+	line := models.StatementLine{
+		TransactionID: t.ID,
+		Type:          t.Type,
+		Counterparty:  t.FromAddress,
+		Amount:        t.Amount,
+		MessageCode:   t.Message,
+		CreatedAt:     t.CreatedAt,
+	}
+	if t.ToAddress != walletId {
+		line.Counterparty = t.ToAddress
+		line.Amount = -t.Amount
+	}
+
+	return line
+}
+
+// Реализация метода для получения сформированной выписки
+// Если выписка за период не сформирована, возвращается pgx.ErrNoRows
+func (r StatementRepository) GetStatement(ctx context.Context, walletId uuid.UUID, from time.Time, to time.Time) (*models.Statement, error) {
+	var data string
+	err := r.db.ReadQueryRow(ctx,
+		`SELECT data FROM statements WHERE wallet_id = $1 AND period_start = $2 AND period_end = $3`,
+		walletId, from.UTC(), to.UTC(),
+	).Scan(&data)
+	if err != nil {
+		return nil, err
+	}
+
+	var statement models.Statement
+	if err := json.Unmarshal([]byte(data), &statement); err != nil {
+		return nil, err
+	}
+
+	return &statement, nil
+}
+
+// Реализация метода для сохранения выписки
+// Выписка за период сохраняется один раз, повторное сохранение другим экземпляром приложения игнорируется
+func (r StatementRepository) SaveStatement(ctx context.Context, statement *models.Statement) error {
+	data, err := json.Marshal(statement)
+	if err != nil {
+		return err
+	}
+
+	return r.db.Exec(ctx,
+		`INSERT INTO statements (wallet_id, period_start, period_end, data, created_at) VALUES ($1, $2, $3, $4, $5)
+         ON CONFLICT (wallet_id, period_start, period_end) DO NOTHING`,
+		statement.WalletID, statement.From.UTC(), statement.To.UTC(), string(data), statement.GeneratedAt.UTC(),
+	)
+}
+
+// Реализация метода для получения кошельков, выписка которых за период еще не сформирована
+func (r StatementRepository) GetWalletsWithoutStatement(ctx context.Context, from time.Time, to time.Time, limit int) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT w.id
+         FROM wallets w
+         WHERE NOT EXISTS (SELECT 1 FROM statements s WHERE s.wallet_id = w.id AND s.period_start = $1 AND s.period_end = $2)
+         ORDER BY w.id
+         LIMIT $3`,
+		from.UTC(), to.UTC(), limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	walletIds := make([]uuid.UUID, 0, limit)
+	for rows.Next() {
+		var walletId uuid.UUID
+		if err := rows.Scan(&walletId); err != nil {
+			return nil, err
+		}
+		walletIds = append(walletIds, walletId)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return walletIds, nil
+}
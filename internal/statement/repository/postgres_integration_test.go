@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/pkg/database"
+	"infotecstechtask/test/testutils"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type StatementRepositoryTestSuite struct {
+	suite.Suite
+	pgContainer *testutils.PGTestContainer
+	repo        *StatementRepository
+	fixtures    *testutils.FixtureManager
+	ctx         context.Context
+}
+
+func (suite *StatementRepositoryTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+
+	container, err := testutils.StartPGContainer(suite.ctx)
+	if err != nil {
+		log.Fatalf("Failed to start test container: %v", err)
+	}
+	suite.pgContainer = container
+
+	client := database.NewClientWithPool(container.Pool)
+	suite.repo = NewStatementRepository(client)
+
+	suite.fixtures = testutils.NewFixtureManager(container.Pool)
+}
+
+func (suite *StatementRepositoryTestSuite) TearDownSuite() {
+	if suite.pgContainer != nil {
+		if err := suite.pgContainer.Close(suite.ctx); err != nil {
+			log.Printf("Failed to close test container: %v", err)
+		}
+	}
+}
+
+func (suite *StatementRepositoryTestSuite) BeforeTest(_, _ string) {
+	_, err := suite.pgContainer.Pool.Exec(suite.ctx, "TRUNCATE TABLE wallets, transactions, statements CASCADE")
+	if err != nil {
+		log.Printf("TRUNCATE error: %v", err)
+	}
+
+	assert.NoError(suite.T(), err)
+
+	err = suite.fixtures.ApplySQLFixture(suite.ctx, "wallets/wallets.sql")
+	suite.Require().NoError(err)
+
+	err = suite.fixtures.ApplySQLFixture(suite.ctx, "transactions/transactions.sql")
+	suite.Require().NoError(err)
+}
+
+func TestStatementRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(StatementRepositoryTestSuite))
+}
+
+func (suite *StatementRepositoryTestSuite) TestBuildStatementSuccess() {
+	sender := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10")
+	recipient := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12")
+	from := time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
+
+	actual, err := suite.repo.BuildStatement(suite.ctx, sender, from, to)
+	suite.Require().NoError(err)
+
+	// В выписку попадает только проведенная транзакция, входящий остаток восстанавливается от текущего баланса
+	suite.Assert().Equal(11330, actual.OpeningBalance)
+	suite.Assert().Equal(10000, actual.ClosingBalance)
+	suite.Assert().Equal(0, actual.TotalIn)
+	suite.Assert().Equal(1330, actual.TotalOut)
+	suite.Assert().Equal([]models.StatementLine{{
+		TransactionID: uuid.MustParse("dd6bea64-8eea-423d-b046-c3002deba55b"),
+		Type:          models.TypeTransfer,
+		Counterparty:  recipient,
+		Amount:        -1330,
+		Balance:       10000,
+		MessageCode:   "TRANSACTION_COMPLETED",
+		CreatedAt:     time.Date(2025, time.August, 2, 0, 0, 0, 0, time.UTC),
+	}}, actual.Lines)
+
+	actual, err = suite.repo.BuildStatement(suite.ctx, recipient, from.AddDate(0, 0, 2), to)
+	suite.Require().NoError(err)
+
+	suite.Assert().Equal(10000, actual.OpeningBalance)
+	suite.Assert().Equal(10000, actual.ClosingBalance)
+	suite.Assert().Empty(actual.Lines)
+}
+
+func (suite *StatementRepositoryTestSuite) TestBuildStatementForUnknownWallet() {
+	from := time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := suite.repo.BuildStatement(suite.ctx, uuid.New(), from, from.AddDate(0, 1, 0))
+
+	suite.Assert().ErrorIs(err, pgx.ErrNoRows)
+}
+
+func (suite *StatementRepositoryTestSuite) TestSaveStatementSuccess() {
+	walletId := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10")
+	from := time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := suite.repo.GetStatement(suite.ctx, walletId, from, to)
+	suite.Require().ErrorIs(err, pgx.ErrNoRows)
+
+	expected, err := suite.repo.BuildStatement(suite.ctx, walletId, from, to)
+	suite.Require().NoError(err)
+	suite.Require().NoError(suite.repo.SaveStatement(suite.ctx, expected))
+	// Повторное сохранение той же выписки игнорируется
+	suite.Require().NoError(suite.repo.SaveStatement(suite.ctx, expected))
+
+	actual, err := suite.repo.GetStatement(suite.ctx, walletId, from, to)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(expected, actual)
+
+	pending, err := suite.repo.GetWalletsWithoutStatement(suite.ctx, from, to, 10)
+	suite.Require().NoError(err)
+	suite.Assert().Len(pending, 4)
+	suite.Assert().NotContains(pending, walletId)
+
+	pending, err = suite.repo.GetWalletsWithoutStatement(suite.ctx, from, to, 2)
+	suite.Require().NoError(err)
+	suite.Assert().Len(pending, 2)
+}
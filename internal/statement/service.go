@@ -0,0 +1,14 @@
+package statement
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Интерфейс сервиса выписок
+type Service interface {
+	GetStatement(ctx context.Context, principal *models.Principal, walletId uuid.UUID, from time.Time, to time.Time) (*models.StatementResponse, error)
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/statement"
+	"infotecstechtask/internal/wallet"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+// Реализация сервиса
+// Ответственна за проверку доступа к кошельку, выбор периода и маппинг моделей
+type StatementService struct {
+	statementRepository statement.Repository
+	walletRepository    wallet.Repository
+}
+
+func NewStatementService(statementRepository statement.Repository, walletRepository wallet.Repository) *StatementService {
+	return &StatementService{
+		statementRepository: statementRepository,
+		walletRepository:    walletRepository,
+	}
+}
+
+// Реализация метода для получения выписки
+//
+// Выписку может получить владелец кошелька или пользователь с ролью не ниже viewer.
+// Без from и to выписка формируется за прошлый календарный месяц.
+// Сформированная фоновым процессом выписка отдается как есть, за остальные периоды выписка собирается по транзакциям
+func (s StatementService) GetStatement(ctx context.Context, principal *models.Principal, walletId uuid.UUID, from time.Time, to time.Time) (*models.StatementResponse, error) {
+	if from.IsZero() && to.IsZero() {
+		from, to = statement.PreviousMonth(time.Now())
+	}
+	if to.Sub(from) > statement.MaxPeriod {
+		return nil, statement.ErrPeriodTooLong
+	}
+
+	walletToReport, err := s.walletRepository.GetWallet(ctx, walletId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, wallet.ErrWalletNotFound
+		}
+		return nil, err
+	}
+	if walletToReport.OwnerID != principal.ID && !principal.Role.Allows(models.RoleViewer) {
+		return nil, wallet.ErrWalletNotOwned
+	}
+
+	result, err := s.statementRepository.GetStatement(ctx, walletId, from, to)
+	if errors.Is(err, pgx.ErrNoRows) {
+		result, err = s.statementRepository.BuildStatement(ctx, walletId, from, to)
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, wallet.ErrWalletNotFound
+		}
+		return nil, err
+	}
+
+	return models.ToStatementResponse(result), nil
+}
@@ -0,0 +1,298 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/outbox"
+	"infotecstechtask/internal/stream"
+	"infotecstechtask/internal/wallet"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+const (
+	// Размер буфера событий подписчика, при переполнении подписчик отключается
+	inboxSize  = 64
+	outboxSize = 16
+
+	// Количество событий, которые читаются из БД за один запрос при восстановлении потока
+	replayBatchSize = 500
+
+	reconnectDelay = 1 * time.Second
+)
+
+// Интерфейс для подписки на уведомления Postgres
+type Listener interface {
+	Listen(ctx context.Context, channel string, fn func(payload string)) error
+}
+
+// Брокер событий кошельков
+//
+// Брокер слушает уведомления outbox.NotifyChannel через LISTEN/NOTIFY, поэтому подписчики любого экземпляра
+// приложения получают события, зафиксированные любым другим экземпляром.
+// Рассылка неблокирующая: подписчик, который не успевает читать события, отключается
+type Broker struct {
+	listener         Listener
+	eventRepository  stream.EventRepository
+	walletRepository wallet.Repository
+
+	mu          sync.RWMutex
+	subscribers map[uuid.UUID]map[*subscription]struct{}
+	closed      bool
+}
+
+func NewBroker(listener Listener, eventRepository stream.EventRepository, walletRepository wallet.Repository) *Broker {
+	return &Broker{
+		listener:         listener,
+		eventRepository:  eventRepository,
+		walletRepository: walletRepository,
+		subscribers:      make(map[uuid.UUID]map[*subscription]struct{}),
+	}
+}
+
+// Функция запускает прослушивание уведомлений и завершается при отмене контекста
+// При потере соединения все подписчики отключаются, так как уведомления за время переподключения теряются
+func (b *Broker) Run(ctx context.Context) {
+	for {
+		err := b.listener.Listen(ctx, outbox.NotifyChannel, func(payload string) {
+			b.dispatch(ctx, payload)
+		})
+		b.dropAll()
+
+		if ctx.Err() != nil {
+			return
+		}
+		slog.ErrorContext(ctx, "event broker lost notification connection", "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+// Реализация метода для подписки на события кошелька
+//
+// Если передан lastEventId, подписчик сначала получает из outbox все события кошелька после него,
+// а затем новые события. Подписка закрывается при отмене ctx или вызове Close
+func (b *Broker) SubscribeWallet(ctx context.Context, principal *models.Principal, walletId uuid.UUID, lastEventId int64) (stream.Subscription, error) {
+	walletToWatch, err := b.walletRepository.GetWallet(ctx, walletId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, wallet.ErrWalletNotFound
+		}
+		return nil, err
+	}
+	if walletToWatch.OwnerID != principal.ID && !principal.Role.Allows(models.RoleViewer) {
+		return nil, wallet.ErrWalletNotOwned
+	}
+
+	sub := &subscription{
+		broker:   b,
+		walletId: walletId,
+		inbox:    make(chan *models.Event, inboxSize),
+		events:   make(chan *models.Event, outboxSize),
+		done:     make(chan struct{}),
+	}
+	if !b.register(sub) {
+		return nil, stream.ErrStreamClosed
+	}
+
+	go sub.pump(ctx, lastEventId)
+
+	return sub, nil
+}
+
+// Функция обрабатывает уведомление о новом событии
+// Событие читается из БД, только если у кошелька есть подписчики
+func (b *Broker) dispatch(ctx context.Context, payload string) {
+	var notification models.EventNotification
+	if err := json.Unmarshal([]byte(payload), &notification); err != nil {
+		slog.ErrorContext(ctx, "event broker received invalid notification", "payload", payload, "error", err)
+		return
+	}
+
+	b.mu.RLock()
+	_, watched := b.subscribers[notification.WalletID]
+	b.mu.RUnlock()
+	if !watched {
+		return
+	}
+
+	event, err := b.eventRepository.GetEvent(ctx, notification.ID)
+	if err != nil {
+		slog.ErrorContext(ctx, "event broker failed to load event", "event_id", notification.ID, "error", err)
+		b.dropWallet(notification.WalletID)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers[event.WalletID] {
+		select {
+		case sub.inbox <- event:
+		default:
+			b.removeLocked(sub)
+		}
+	}
+}
+
+// Функция закрывает все подписки и запрещает новые
+// Вызывается в начале остановки http сервера, чтобы открытые потоки SSE и WebSocket завершились
+// и не задерживали остановку, а клиенты переподключились к другому экземпляру
+func (b *Broker) Close() {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+
+	b.dropAll()
+}
+
+func (b *Broker) register(sub *subscription) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return false
+	}
+	if b.subscribers[sub.walletId] == nil {
+		b.subscribers[sub.walletId] = make(map[*subscription]struct{})
+	}
+	b.subscribers[sub.walletId][sub] = struct{}{}
+
+	return true
+}
+
+func (b *Broker) unregister(sub *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.removeLocked(sub)
+}
+
+// Функция удаляет подписчика и закрывает его входящий канал
+// Канал закрывается только здесь и только если подписчик еще зарегистрирован, поэтому повторное закрытие невозможно
+func (b *Broker) removeLocked(sub *subscription) {
+	subscribers, ok := b.subscribers[sub.walletId]
+	if !ok {
+		return
+	}
+	if _, ok := subscribers[sub]; !ok {
+		return
+	}
+
+	delete(subscribers, sub)
+	if len(subscribers) == 0 {
+		delete(b.subscribers, sub.walletId)
+	}
+	close(sub.inbox)
+}
+
+func (b *Broker) dropWallet(walletId uuid.UUID) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for sub := range b.subscribers[walletId] {
+		b.removeLocked(sub)
+	}
+}
+
+func (b *Broker) dropAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, subscribers := range b.subscribers {
+		for sub := range subscribers {
+			b.removeLocked(sub)
+		}
+	}
+}
+
+// Подписка на события одного кошелька
+// inbox заполняет брокер, events читает клиент, между ними работает pump
+type subscription struct {
+	broker   *Broker
+	walletId uuid.UUID
+	inbox    chan *models.Event
+	events   chan *models.Event
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (s *subscription) Events() <-chan *models.Event {
+	return s.events
+}
+
+func (s *subscription) Close() {
+	s.closeOnce.Do(func() {
+		s.broker.unregister(s)
+		close(s.done)
+	})
+}
+
+// Функция передает клиенту сначала пропущенные события из outbox, а затем новые события от брокера
+// События с идентификатором не больше последнего отправленного пропускаются,
+// так как подписка регистрируется до чтения пропущенных событий и они могут прийти дважды
+func (s *subscription) pump(ctx context.Context, lastEventId int64) {
+	defer close(s.events)
+	defer s.Close()
+
+	if lastEventId > 0 {
+		for {
+			events, err := s.broker.eventRepository.GetWalletEvents(ctx, s.walletId, lastEventId, replayBatchSize)
+			if err != nil {
+				slog.ErrorContext(ctx, "event broker failed to replay events", "wallet_id", s.walletId, "error", err)
+				return
+			}
+			for _, event := range events {
+				if !s.send(ctx, event) {
+					return
+				}
+				lastEventId = event.ID
+			}
+			if len(events) < replayBatchSize {
+				break
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.done:
+			return
+		case event, ok := <-s.inbox:
+			if !ok {
+				return
+			}
+			if event.ID <= lastEventId {
+				continue
+			}
+			if !s.send(ctx, event) {
+				return
+			}
+			lastEventId = event.ID
+		}
+	}
+}
+
+func (s *subscription) send(ctx context.Context, event *models.Event) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-s.done:
+		return false
+	case s.events <- event:
+		return true
+	}
+}
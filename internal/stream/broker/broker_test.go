@@ -0,0 +1,177 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/stream"
+	"infotecstechtask/internal/wallet"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	ownerId  = uuid.MustParse("5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e01")
+	walletId = uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10")
+)
+
+type fakeListener struct {
+	payloads chan string
+}
+
+func (l *fakeListener) Listen(ctx context.Context, _ string, fn func(payload string)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case payload := <-l.payloads:
+			fn(payload)
+		}
+	}
+}
+
+func (l *fakeListener) notify(event *models.Event) {
+	payload, _ := json.Marshal(models.EventNotification{ID: event.ID, WalletID: event.WalletID})
+	l.payloads <- string(payload)
+}
+
+type fakeEventRepository struct {
+	mu     sync.Mutex
+	events map[int64]*models.Event
+}
+
+func (r *fakeEventRepository) add(event *models.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events[event.ID] = event
+}
+
+func (r *fakeEventRepository) GetEvent(_ context.Context, eventId int64) (*models.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.events[eventId], nil
+}
+
+func (r *fakeEventRepository) GetWalletEvents(_ context.Context, walletId uuid.UUID, afterId int64, limit int) ([]*models.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := []*models.Event{}
+	for id := afterId + 1; len(events) < limit; id++ {
+		event, ok := r.events[id]
+		if !ok {
+			break
+		}
+		if event.WalletID == walletId {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+type fakeWalletRepository struct{}
+
+func (fakeWalletRepository) GetWallet(_ context.Context, id uuid.UUID) (*models.Wallet, error) {
+	return &models.Wallet{ID: id, OwnerID: ownerId}, nil
+}
+
+func newTestBroker(events ...*models.Event) (*Broker, *fakeListener) {
+	repository := &fakeEventRepository{events: map[int64]*models.Event{}}
+	for _, event := range events {
+		repository.events[event.ID] = event
+	}
+	listener := &fakeListener{payloads: make(chan string)}
+
+	return NewBroker(listener, repository, fakeWalletRepository{}), listener
+}
+
+func receive(t *testing.T, events <-chan *models.Event) *models.Event {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("event was not received")
+		return nil
+	}
+}
+
+func TestSubscribeWalletReplaysMissedEventsThenStreamsNewOnes(t *testing.T) {
+	first := &models.Event{ID: 1, WalletID: walletId}
+	second := &models.Event{ID: 2, WalletID: walletId}
+	third := &models.Event{ID: 3, WalletID: walletId}
+	broker, listener := newTestBroker(first, second, third)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go broker.Run(ctx)
+
+	subscription, err := broker.SubscribeWallet(ctx, &models.Principal{ID: ownerId, Role: models.RoleClient}, walletId, 1)
+	require.NoError(t, err)
+	defer subscription.Close()
+
+	assert.Equal(t, int64(2), receive(t, subscription.Events()).ID)
+	assert.Equal(t, int64(3), receive(t, subscription.Events()).ID)
+
+	listener.notify(third)
+	fourth := &models.Event{ID: 4, WalletID: walletId}
+	broker.eventRepository.(*fakeEventRepository).add(fourth)
+	listener.notify(fourth)
+
+	assert.Equal(t, int64(4), receive(t, subscription.Events()).ID)
+}
+
+func TestSubscribeWalletOfAnotherOwner(t *testing.T) {
+	broker, _ := newTestBroker()
+
+	_, err := broker.SubscribeWallet(context.Background(), &models.Principal{ID: uuid.New(), Role: models.RoleClient}, walletId, 0)
+	assert.ErrorIs(t, err, wallet.ErrWalletNotOwned)
+
+	subscription, err := broker.SubscribeWallet(context.Background(), &models.Principal{ID: uuid.New(), Role: models.RoleViewer}, walletId, 0)
+	require.NoError(t, err)
+	subscription.Close()
+}
+
+func TestSlowSubscriberIsDisconnected(t *testing.T) {
+	broker, _ := newTestBroker()
+
+	subscription, err := broker.SubscribeWallet(context.Background(), &models.Principal{ID: ownerId, Role: models.RoleClient}, walletId, 0)
+	require.NoError(t, err)
+	defer subscription.Close()
+
+	for id := int64(1); id <= inboxSize+outboxSize+2; id++ {
+		event := &models.Event{ID: id, WalletID: walletId}
+		broker.eventRepository.(*fakeEventRepository).add(event)
+		payload, _ := json.Marshal(models.EventNotification{ID: id, WalletID: walletId})
+		broker.dispatch(context.Background(), string(payload))
+	}
+
+	received := 0
+	for range subscription.Events() {
+		received++
+	}
+	assert.Less(t, received, inboxSize+outboxSize+2)
+}
+
+func TestCloseEndsSubscriptionsAndRejectsNewOnes(t *testing.T) {
+	broker, _ := newTestBroker()
+	principal := &models.Principal{ID: ownerId, Role: models.RoleClient}
+
+	subscription, err := broker.SubscribeWallet(context.Background(), principal, walletId, 0)
+	require.NoError(t, err)
+
+	broker.Close()
+
+	select {
+	case _, ok := <-subscription.Events():
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("subscription was not closed")
+	}
+
+	_, err = broker.SubscribeWallet(context.Background(), principal, walletId, 0)
+	assert.ErrorIs(t, err, stream.ErrStreamClosed)
+}
@@ -0,0 +1,5 @@
+package stream
+
+import "errors"
+
+var ErrStreamClosed = errors.New("Event stream is shutting down")
@@ -0,0 +1,15 @@
+package stream
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Интерфейс для чтения событий из outbox
+// GetWalletEvents используется для восстановления потока после переподключения клиента
+type EventRepository interface {
+	GetEvent(ctx context.Context, eventId int64) (*models.Event, error)
+	GetWalletEvents(ctx context.Context, walletId uuid.UUID, afterId int64, limit int) ([]*models.Event, error)
+}
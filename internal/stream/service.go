@@ -0,0 +1,22 @@
+package stream
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Подписка на поток событий
+// Канал Events закрывается, если подписчик не успевает читать события или потеряно соединение с БД,
+// в этом случае клиент должен переподключиться, передав идентификатор последнего полученного события
+type Subscription interface {
+	Events() <-chan *models.Event
+	Close()
+}
+
+// Интерфейс сервиса
+// Подписаться на кошелёк может его владелец или пользователь с ролью не ниже viewer
+type Service interface {
+	SubscribeWallet(ctx context.Context, principal *models.Principal, walletId uuid.UUID, lastEventId int64) (Subscription, error)
+}
@@ -0,0 +1,22 @@
+package tracing
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+// Список поддерживаемых экспортеров
+var Exporters = []string{ExporterNone, ExporterOTLP, ExporterStdout, ExporterFile}
+
+// Структура, хранящая в себе параметры экспорта трейсов
+// Значения собираются пакетом internal/config
+//
+// Адрес коллектора для ExporterOTLP задается стандартными переменными OTEL_EXPORTER_OTLP_*,
+// ServiceName записывается в атрибут service.name, FilePath используется только ExporterFile
+type Config struct {
+	Exporter    string
+	FilePath    string
+	ServiceName string
+}
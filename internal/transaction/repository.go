@@ -3,11 +3,17 @@ package transaction
 import (
 	"context"
 	"infotecstechtask/internal/models"
+
+	"github.com/google/uuid"
 )
 
 // Интерфейс репозитория
-// Содержит в себе методы для получения списка транзакций
+// Содержит в себе методы для получения списка всех транзакций и транзакций, затрагивающих кошельки владельца,
+// а также потоковое чтение транзакций по фильтру для выгрузок
 type Repository interface {
 	GetTransactions(ctx context.Context, count int) ([]*models.Transaction, error)
 	GetAllTransactions(ctx context.Context) ([]*models.Transaction, error)
+	GetTransactionsByOwner(ctx context.Context, ownerId uuid.UUID, count int) ([]*models.Transaction, error)
+	GetAllTransactionsByOwner(ctx context.Context, ownerId uuid.UUID) ([]*models.Transaction, error)
+	StreamTransactions(ctx context.Context, filter models.TransactionFilter, fn func(*models.Transaction) error) error
 }
@@ -4,9 +4,13 @@ import (
 	"context"
 	"infotecstechtask/internal/models"
 	"infotecstechtask/pkg/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 )
 
 // Реализация репозитория
+// Запросы только читают данные, поэтому выполняются на репликах, если они настроены
 type TransactionRepository struct {
 	db *database.Client
 }
@@ -18,53 +22,124 @@ func NewTransactionRepository(db *database.Client) *TransactionRepository {
 }
 
 func (r TransactionRepository) GetTransactions(ctx context.Context, count int) ([]*models.Transaction, error) {
-	sql := `SELECT id, from_address, to_address, amount, status, message, created_at 
-            FROM transactions 
-            ORDER BY created_at DESC 
+	sql := `SELECT id, type, from_address, to_address, amount, status, message, created_at
+            FROM transactions
+            ORDER BY created_at DESC
             LIMIT $1`
 
-	rows, err := r.db.Query(ctx, sql, count)
+	rows, err := r.db.ReadQuery(ctx, sql, count)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	transactions := make([]*models.Transaction, 0, count)
-	for rows.Next() {
-		var t models.Transaction
-		err := rows.Scan(&t.ID, &t.FromAddress, &t.ToAddress, &t.Amount, &t.Status, &t.Message, &t.CreatedAt)
-		if err != nil {
-			return nil, err
-		}
-		transactions = append(transactions, &t)
+	return scanTransactions(rows, count)
+}
+
+func (r TransactionRepository) GetAllTransactions(ctx context.Context) ([]*models.Transaction, error) {
+	sql := `SELECT id, type, from_address, to_address, amount, status, message, created_at
+            FROM transactions
+            ORDER BY created_at DESC`
+
+	rows, err := r.db.ReadQuery(ctx, sql)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
 
-	if err := rows.Err(); err != nil {
+	return scanTransactions(rows, 0)
+}
+
+// Возвращает последние транзакции, в которых отправителем или получателем является кошелёк владельца
+func (r TransactionRepository) GetTransactionsByOwner(ctx context.Context, ownerId uuid.UUID, count int) ([]*models.Transaction, error) {
+	sql := `SELECT id, type, from_address, to_address, amount, status, message, created_at
+            FROM transactions
+            WHERE from_address IN (SELECT id FROM wallets WHERE owner_id = $1)
+               OR to_address IN (SELECT id FROM wallets WHERE owner_id = $1)
+            ORDER BY created_at DESC
+            LIMIT $2`
+
+	rows, err := r.db.ReadQuery(ctx, sql, ownerId, count)
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
-	return transactions, nil
+	return scanTransactions(rows, count)
 }
 
-func (r TransactionRepository) GetAllTransactions(ctx context.Context) ([]*models.Transaction, error) {
-	sql := `SELECT id, from_address, to_address, amount, status, message, created_at 
-            FROM transactions 
+// Возвращает все транзакции, в которых отправителем или получателем является кошелёк владельца
+func (r TransactionRepository) GetAllTransactionsByOwner(ctx context.Context, ownerId uuid.UUID) ([]*models.Transaction, error) {
+	sql := `SELECT id, type, from_address, to_address, amount, status, message, created_at
+            FROM transactions
+            WHERE from_address IN (SELECT id FROM wallets WHERE owner_id = $1)
+               OR to_address IN (SELECT id FROM wallets WHERE owner_id = $1)
             ORDER BY created_at DESC`
 
-	rows, err := r.db.Query(ctx, sql)
+	rows, err := r.db.ReadQuery(ctx, sql, ownerId)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	transactions := []*models.Transaction{}
+	return scanTransactions(rows, 0)
+}
+
+// Передает в fn транзакции, подходящие под фильтр, по мере чтения строк из БД
+//
+// Результат не собирается в памяти, поэтому метод подходит для выгрузки всей истории.
+// Порядок стабилен между выгрузками: при совпадении created_at транзакции упорядочиваются по id.
+// Ошибка fn прерывает чтение и возвращается как есть
+func (r TransactionRepository) StreamTransactions(ctx context.Context, filter models.TransactionFilter, fn func(*models.Transaction) error) error {
+	sql := `SELECT id, type, from_address, to_address, amount, status, message, created_at
+            FROM transactions
+            WHERE ($1::VARCHAR IS NULL
+                   OR from_address IN (SELECT id FROM wallets WHERE owner_id = $1)
+                   OR to_address IN (SELECT id FROM wallets WHERE owner_id = $1))
+              AND ($2::VARCHAR IS NULL OR from_address = $2 OR to_address = $2)
+              AND ($3::TIMESTAMP IS NULL OR created_at >= $3)
+              AND ($4::TIMESTAMP IS NULL OR created_at < $4)
+            ORDER BY created_at DESC, id DESC
+            LIMIT $5`
+
+	rows, err := r.db.ReadQuery(ctx, sql, filter.OwnerID, filter.WalletID, filter.From, filter.To, filter.Limit)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+func scanTransaction(rows pgx.Rows) (*models.Transaction, error) {
+	var t models.Transaction
+	err := rows.Scan(&t.ID, &t.Type, &t.FromAddress, &t.ToAddress, &t.Amount, &t.Status, &t.Message, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+// Функция для сборки списка транзакций из результата запроса
+func scanTransactions(rows pgx.Rows, capacity int) ([]*models.Transaction, error) {
+	transactions := make([]*models.Transaction, 0, capacity)
 	for rows.Next() {
-		var t models.Transaction
-		err := rows.Scan(&t.ID, &t.FromAddress, &t.ToAddress, &t.Amount, &t.Status, &t.Message, &t.CreatedAt)
+		t, err := scanTransaction(rows)
 		if err != nil {
 			return nil, err
 		}
-		transactions = append(transactions, &t)
+		transactions = append(transactions, t)
 	}
 
 	if err := rows.Err(); err != nil {
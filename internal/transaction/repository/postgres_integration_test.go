@@ -6,10 +6,10 @@ import (
 	"infotecstechtask/pkg/database"
 	"infotecstechtask/test/testutils"
 	"log"
-	"path/filepath"
-	"runtime"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -26,11 +26,7 @@ type TransactionRepositoryTestSuite struct {
 func (suite *TransactionRepositoryTestSuite) SetupSuite() {
 	suite.ctx = context.Background()
 
-	_, filename, _, _ := runtime.Caller(0)
-	dir := filepath.Dir(filename)
-	migrationsPath := filepath.Join(dir, "../../../init/migrations")
-
-	container, err := testutils.StartPGContainer(suite.ctx, migrationsPath)
+	container, err := testutils.StartPGContainer(suite.ctx)
 	if err != nil {
 		log.Fatalf("Failed to start test container: %v", err)
 	}
@@ -123,3 +119,88 @@ func (suite *TransactionRepositoryTestSuite) TestGetTransactionsSuccess() {
 		suite.Assert().ElementsMatch(tc.expected, actual)
 	}
 }
+
+func (suite *TransactionRepositoryTestSuite) TestGetTransactionsByOwnerSuccess() {
+	err := suite.fixtures.ApplySQLFixture(suite.ctx, "wallets/wallets.sql")
+	suite.Require().NoError(err)
+
+	err = suite.fixtures.ApplySQLFixture(suite.ctx, "wallets/wallet_owners.sql")
+	suite.Require().NoError(err)
+
+	err = suite.fixtures.ApplySQLFixture(suite.ctx, "transactions/transactions.sql")
+	suite.Require().NoError(err)
+
+	var allTransactions []*models.Transaction
+	err = suite.dataLoader.LoadJSONFixture("transactions/all_transactions.json", &allTransactions)
+	suite.Require().NoError(err)
+	var oneTransaction []*models.Transaction
+	err = suite.dataLoader.LoadJSONFixture("transactions/one_transaction.json", &oneTransaction)
+	suite.Require().NoError(err)
+
+	owner := uuid.MustParse("5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e01")
+	ownerWithoutTransactions := uuid.MustParse("5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e02")
+
+	actual, err := suite.repo.GetTransactionsByOwner(suite.ctx, owner, 1)
+	suite.Require().NoError(err)
+	suite.Assert().ElementsMatch(oneTransaction, actual)
+
+	actual, err = suite.repo.GetAllTransactionsByOwner(suite.ctx, owner)
+	suite.Require().NoError(err)
+	suite.Assert().ElementsMatch(allTransactions, actual)
+
+	actual, err = suite.repo.GetAllTransactionsByOwner(suite.ctx, ownerWithoutTransactions)
+	suite.Require().NoError(err)
+	suite.Assert().Empty(actual)
+}
+
+func (suite *TransactionRepositoryTestSuite) TestStreamTransactionsSuccess() {
+	err := suite.fixtures.ApplySQLFixture(suite.ctx, "wallets/wallets.sql")
+	suite.Require().NoError(err)
+
+	err = suite.fixtures.ApplySQLFixture(suite.ctx, "wallets/wallet_owners.sql")
+	suite.Require().NoError(err)
+
+	err = suite.fixtures.ApplySQLFixture(suite.ctx, "transactions/transactions.sql")
+	suite.Require().NoError(err)
+
+	var allTransactions []*models.Transaction
+	err = suite.dataLoader.LoadJSONFixture("transactions/all_transactions.json", &allTransactions)
+	suite.Require().NoError(err)
+	var oneTransaction []*models.Transaction
+	err = suite.dataLoader.LoadJSONFixture("transactions/one_transaction.json", &oneTransaction)
+	suite.Require().NoError(err)
+
+	owner := uuid.MustParse("5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e01")
+	sender := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10")
+	walletWithoutTransactions := uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11")
+	from := time.Date(2025, time.August, 3, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, time.August, 4, 0, 0, 0, 0, time.UTC)
+	limit := 1
+
+	testCases := []struct {
+		name     string
+		filter   models.TransactionFilter
+		expected []*models.Transaction
+	}{
+		{name: "without filter", expected: allTransactions},
+		{name: "by owner", filter: models.TransactionFilter{OwnerID: &owner}, expected: allTransactions},
+		{name: "by wallet", filter: models.TransactionFilter{WalletID: &sender}, expected: allTransactions},
+		{name: "wallet without transactions", filter: models.TransactionFilter{WalletID: &walletWithoutTransactions}, expected: []*models.Transaction{}},
+		{name: "by date range", filter: models.TransactionFilter{From: &from, To: &to}, expected: allTransactions[1:2]},
+		{name: "with limit", filter: models.TransactionFilter{Limit: &limit}, expected: oneTransaction},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			actual := []*models.Transaction{}
+			err := suite.repo.StreamTransactions(suite.ctx, tc.filter, func(t *models.Transaction) error {
+				actual = append(actual, t)
+				return nil
+			})
+			suite.Require().NoError(err)
+
+			// Порядок выгрузки важен, поэтому сравнение строгое
+			suite.Assert().Equal(tc.expected, actual)
+		})
+	}
+}
@@ -3,11 +3,16 @@ package transaction
 import (
 	"context"
 	"infotecstechtask/internal/models"
+
+	"github.com/google/uuid"
 )
 
 // Интерфейс сервиса
-// Содержит в себе методы для получения списка транзакций
+// Содержит в себе методы для получения списка транзакций и выгрузки истории
 type Service interface {
 	GetTransactions(ctx context.Context, count int) ([]*models.TransactionResponse, error)
 	GetAllTransactions(ctx context.Context) ([]*models.TransactionResponse, error)
+	GetTransactionsByOwner(ctx context.Context, ownerId uuid.UUID, count int) ([]*models.TransactionResponse, error)
+	GetAllTransactionsByOwner(ctx context.Context, ownerId uuid.UUID) ([]*models.TransactionResponse, error)
+	StreamTransactions(ctx context.Context, filter models.TransactionFilter, fn func(*models.TransactionResponse) error) error
 }
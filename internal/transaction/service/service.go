@@ -2,19 +2,27 @@ package service
 
 import (
 	"context"
+	"errors"
 	"infotecstechtask/internal/models"
 	"infotecstechtask/internal/transaction"
+	"infotecstechtask/internal/wallet"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 )
 
 // Реализация сервиса
 // Ответственна за обработку ошибок и маппинг моделей
+// Репозиторий кошельков нужен для проверки доступа к истории отдельного кошелька
 type TransactionService struct {
 	transactionRepository transaction.Repository
+	walletRepository      wallet.Repository
 }
 
-func NewTransactionService(transactionRepository transaction.Repository) *TransactionService {
+func NewTransactionService(transactionRepository transaction.Repository, walletRepository wallet.Repository) *TransactionService {
 	return &TransactionService{
 		transactionRepository: transactionRepository,
+		walletRepository:      walletRepository,
 	}
 }
 
@@ -29,3 +37,38 @@ func (s TransactionService) GetAllTransactions(ctx context.Context) ([]*models.T
 
 	return models.ToTransactionResponses(transactions), err
 }
+
+func (s TransactionService) GetTransactionsByOwner(ctx context.Context, ownerId uuid.UUID, count int) ([]*models.TransactionResponse, error) {
+	transactions, err := s.transactionRepository.GetTransactionsByOwner(ctx, ownerId, count)
+
+	return models.ToTransactionResponses(transactions), err
+}
+
+func (s TransactionService) GetAllTransactionsByOwner(ctx context.Context, ownerId uuid.UUID) ([]*models.TransactionResponse, error) {
+	transactions, err := s.transactionRepository.GetAllTransactionsByOwner(ctx, ownerId)
+
+	return models.ToTransactionResponses(transactions), err
+}
+
+// Реализация метода для выгрузки истории транзакций
+//
+// Если в фильтре задан кошелек, сначала проверяется, что он существует и принадлежит OwnerID (если он задан),
+// поэтому ошибки доступа возвращаются до первой транзакции
+func (s TransactionService) StreamTransactions(ctx context.Context, filter models.TransactionFilter, fn func(*models.TransactionResponse) error) error {
+	if filter.WalletID != nil {
+		walletToExport, err := s.walletRepository.GetWallet(ctx, *filter.WalletID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return wallet.ErrWalletNotFound
+			}
+			return err
+		}
+		if filter.OwnerID != nil && walletToExport.OwnerID != *filter.OwnerID {
+			return wallet.ErrWalletNotOwned
+		}
+	}
+
+	return s.transactionRepository.StreamTransactions(ctx, filter, func(t *models.Transaction) error {
+		return fn(models.ToTransactionResponse(t))
+	})
+}
@@ -4,3 +4,4 @@ import "errors"
 
 // Список возможных ошибок бизнес-логики кошельков
 var ErrWalletNotFound = errors.New("Wallet not found")
+var ErrWalletNotOwned = errors.New("Wallet does not belong to the caller")
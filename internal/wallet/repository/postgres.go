@@ -9,6 +9,7 @@ import (
 )
 
 // Реализация репозитория
+// Запросы только читают данные, поэтому выполняются на репликах, если они настроены
 type WalletRepository struct {
 	db *database.Client
 }
@@ -20,12 +21,12 @@ func NewWalletRepository(db *database.Client) *WalletRepository {
 }
 
 func (r WalletRepository) GetWallet(ctx context.Context, walletId uuid.UUID) (*models.Wallet, error) {
-	sql := `SELECT id, balance FROM wallets WHERE id = $1`
+	sql := `SELECT id, balance, owner_id FROM wallets WHERE id = $1`
 
-	row := r.db.QueryRow(ctx, sql, walletId)
+	row := r.db.ReadQueryRow(ctx, sql, walletId)
 
 	wallet := &models.Wallet{}
-	err := row.Scan(&wallet.ID, &wallet.Balance)
+	err := row.Scan(&wallet.ID, &wallet.Balance, &wallet.OwnerID)
 	if err != nil {
 		return nil, err
 	}
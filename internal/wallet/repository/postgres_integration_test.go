@@ -6,8 +6,6 @@ import (
 	"infotecstechtask/pkg/database"
 	"infotecstechtask/test/testutils"
 	"log"
-	"path/filepath"
-	"runtime"
 	"testing"
 
 	"github.com/google/uuid"
@@ -28,11 +26,7 @@ type WalletRepositoryTestSuite struct {
 func (suite *WalletRepositoryTestSuite) SetupSuite() {
 	suite.ctx = context.Background()
 
-	_, filename, _, _ := runtime.Caller(0)
-	dir := filepath.Dir(filename)
-	migrationsPath := filepath.Join(dir, "../../../init/migrations")
-
-	container, err := testutils.StartPGContainer(suite.ctx, migrationsPath)
+	container, err := testutils.StartPGContainer(suite.ctx)
 	if err != nil {
 		log.Fatalf("Failed to start test container: %v", err)
 	}
@@ -9,6 +9,7 @@ import (
 
 // Интерфейс сервиса
 // Содержит в себе метод для получения баланса кошелька
+// Баланс доступен владельцу кошелька или пользователю с ролью не ниже viewer
 type Service interface {
-	GetWallet(ctx context.Context, walletId uuid.UUID) (*models.WalletResponse, error)
+	GetWallet(ctx context.Context, principal *models.Principal, walletId uuid.UUID) (*models.WalletResponse, error)
 }
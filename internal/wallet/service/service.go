@@ -11,7 +11,7 @@ import (
 )
 
 // Реализация сервиса
-// Ответственна за обработку ошибок и маппинг моделей
+// Ответственна за проверку доступа к кошельку, обработку ошибок и маппинг моделей
 type WalletService struct {
 	walletRepository wallet.Repository
 }
@@ -22,7 +22,7 @@ func NewWalletService(walletRepository wallet.Repository) *WalletService {
 	}
 }
 
-func (s WalletService) GetWallet(ctx context.Context, walletId uuid.UUID) (*models.WalletResponse, error) {
+func (s WalletService) GetWallet(ctx context.Context, principal *models.Principal, walletId uuid.UUID) (*models.WalletResponse, error) {
 	walletToReturn, err := s.walletRepository.GetWallet(ctx, walletId)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -30,6 +30,9 @@ func (s WalletService) GetWallet(ctx context.Context, walletId uuid.UUID) (*mode
 		}
 		return nil, err
 	}
+	if walletToReturn.OwnerID != principal.ID && !principal.Role.Allows(models.RoleViewer) {
+		return nil, wallet.ErrWalletNotOwned
+	}
 	
 	return models.ToWalletResponse(walletToReturn), nil
 }
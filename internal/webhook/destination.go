@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"syscall"
+)
+
+// Диапазоны, которые не отсекаются методами netip.Addr, но не должны быть доступны получателям webhook
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// Функция для проверки адреса подписки при её создании
+// Адрес должен использовать https, а его хост - резолвиться только в публичные адреса
+func ValidateURL(ctx context.Context, rawURL string) error {
+	target, err := url.Parse(rawURL)
+	if err != nil || target.Scheme != "https" || target.Hostname() == "" || target.User != nil {
+		return ErrURLNotAllowed
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", target.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return ErrURLNotAllowed
+	}
+	for _, addr := range addrs {
+		if !IsPublicAddr(addr) {
+			return ErrURLNotAllowed
+		}
+	}
+
+	return nil
+}
+
+// Функция проверяет, что адрес публичный: не loopback, не link-local, не из частных и зарезервированных сетей
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Функция для net.Dialer.Control, проверяет адрес уже после резолва DNS непосредственно перед соединением
+// Так хост, который после создания подписки начал резолвиться во внутренний адрес, не дает отправить на него запрос
+func DialControl(_ string, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	if !IsPublicAddr(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrURLNotAllowed, addrPort.Addr())
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package dispatcher
+
+import "time"
+
+// Структура, хранящая в себе параметры доставки webhook
+// Значения собираются пакетом internal/config
+//
+// MaxAttempts - количество неудачных попыток, после которых доставка переводится в статус dead
+// Timeout - время ожидания ответа получателя
+type Config struct {
+	MaxAttempts int
+	Timeout     time.Duration
+}
@@ -0,0 +1,170 @@
+package dispatcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/webhook"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultInterval  = 1 * time.Second
+	defaultBatchSize = 50
+
+	minRetryDelay = 10 * time.Second
+	maxRetryDelay = 1 * time.Hour
+
+	// Максимальная длина ответа получателя, сохраняемая в истории как ошибка
+	maxErrorBodyLength = 256
+)
+
+// Фоновый процесс, отправляющий доставки на webhook
+//
+// Каждая доставка подписывается секретом подписки.
+// Неудачная попытка повторяется с экспоненциальной задержкой, после MaxAttempts неудачных попыток
+// доставка переводится в статус dead и больше не отправляется автоматически
+type Dispatcher struct {
+	repository  webhook.DeliveryRepository
+	client      *http.Client
+	maxAttempts int
+	lease       time.Duration
+	interval    time.Duration
+	batchSize   int
+}
+
+func NewDispatcher(repository webhook.DeliveryRepository, config Config) *Dispatcher {
+	return &Dispatcher{
+		repository:  repository,
+		client:      newClient(config.Timeout),
+		maxAttempts: config.MaxAttempts,
+		lease:       2 * config.Timeout,
+		interval:    defaultInterval,
+		batchSize:   defaultBatchSize,
+	}
+}
+
+// Функция создает http клиент, который соединяется только с публичными адресами
+//
+// Адрес проверяется при каждом соединении после резолва DNS, прокси из окружения не используется.
+// Перенаправления не выполняются: ответ 3xx считается неудачной попыткой
+func newClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: webhook.DialControl,
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			ForceAttemptHTTP2:   true,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Функция запускает цикл отправки и завершается при отмене контекста
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.deliverDue(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "webhook dispatcher failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Функция отправляет одну пачку доставок, которые пора отправить
+func (d *Dispatcher) deliverDue(ctx context.Context) error {
+	deliveries, err := d.repository.ClaimDueDeliveries(ctx, d.batchSize, d.lease)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		statusCode, err := d.send(ctx, delivery)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err == nil {
+			err = d.repository.MarkDelivered(ctx, delivery.ID, statusCode)
+		} else {
+			attempts := delivery.Attempts + 1
+			err = d.repository.MarkFailed(ctx, delivery.ID, statusCode, err.Error(), retryDelay(attempts), attempts >= d.maxAttempts)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Функция отправляет доставку и возвращает HTTP статус ответа
+// Любой ответ, кроме 2xx, считается неудачной попыткой
+func (d *Dispatcher) send(ctx context.Context, delivery *models.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(models.WebhookPayload{
+		ID:        delivery.ID,
+		EventID:   delivery.EventID,
+		Type:      delivery.EventType,
+		Data:      delivery.Payload,
+		CreatedAt: delivery.CreatedAt,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	// Подписки, созданные до проверки адреса, могут использовать http
+	if req.URL.Scheme != "https" {
+		return 0, webhook.ErrURLNotAllowed
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-ID", delivery.ID.String())
+	req.Header.Set("X-Event-Type", string(delivery.EventType))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(delivery.Secret, time.Now(), body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyLength))
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d: %s", resp.StatusCode, responseBody)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Функция для расчета задержки перед следующей попыткой, задержка удваивается с каждой неудачной попыткой
+func retryDelay(attempts int) time.Duration {
+	delay := minRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxRetryDelay)
+}
@@ -0,0 +1,164 @@
+package dispatcher
+
+import (
+	"context"
+	"encoding/json"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/webhook"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "test-secret"
+
+type failedDelivery struct {
+	statusCode int
+	retryAfter time.Duration
+	dead       bool
+}
+
+type fakeDeliveryRepository struct {
+	due       []*models.WebhookDelivery
+	delivered map[uuid.UUID]int
+	failed    map[uuid.UUID]failedDelivery
+}
+
+func newFakeDeliveryRepository(due ...*models.WebhookDelivery) *fakeDeliveryRepository {
+	return &fakeDeliveryRepository{
+		due:       due,
+		delivered: map[uuid.UUID]int{},
+		failed:    map[uuid.UUID]failedDelivery{},
+	}
+}
+
+func (r *fakeDeliveryRepository) ScheduleDeliveries(_ context.Context, _ *models.Event) error {
+	return nil
+}
+
+func (r *fakeDeliveryRepository) ClaimDueDeliveries(_ context.Context, _ int, _ time.Duration) ([]*models.WebhookDelivery, error) {
+	return r.due, nil
+}
+
+func (r *fakeDeliveryRepository) MarkDelivered(_ context.Context, deliveryId uuid.UUID, statusCode int) error {
+	r.delivered[deliveryId] = statusCode
+	return nil
+}
+
+func (r *fakeDeliveryRepository) MarkFailed(_ context.Context, deliveryId uuid.UUID, statusCode int, _ string, retryAfter time.Duration, dead bool) error {
+	r.failed[deliveryId] = failedDelivery{statusCode: statusCode, retryAfter: retryAfter, dead: dead}
+	return nil
+}
+
+func newDelivery(url string, attempts int) *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		ID:        uuid.New(),
+		EventID:   1,
+		EventType: models.EventWalletBalanceChanged,
+		Payload:   json.RawMessage(`{"balance":115}`),
+		Attempts:  attempts,
+		URL:       url,
+		Secret:    testSecret,
+	}
+}
+
+// Тестовый получатель слушает loopback, поэтому клиент диспетчера заменяется клиентом, которому доверяет сертификат получателя
+func newTestDispatcher(repository webhook.DeliveryRepository, receiver *httptest.Server) *Dispatcher {
+	dispatcher := NewDispatcher(repository, Config{MaxAttempts: 3, Timeout: time.Second})
+	dispatcher.client = receiver.Client()
+
+	return dispatcher
+}
+
+func TestDeliverDueSignsRequest(t *testing.T) {
+	var received models.WebhookPayload
+	receiver := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		if !webhook.Verify(testSecret, r.Header.Get(webhook.SignatureHeader), body, time.Minute) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		require.NoError(t, json.Unmarshal(body, &received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	delivery := newDelivery(receiver.URL, 0)
+	repository := newFakeDeliveryRepository(delivery)
+
+	err := newTestDispatcher(repository, receiver).deliverDue(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[uuid.UUID]int{delivery.ID: http.StatusNoContent}, repository.delivered)
+	assert.Empty(t, repository.failed)
+	assert.Equal(t, delivery.ID, received.ID)
+	assert.JSONEq(t, `{"balance":115}`, string(received.Data))
+}
+
+func TestDeliverDueRetriesAndMovesToDeadLetter(t *testing.T) {
+	receiver := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	retried := newDelivery(receiver.URL, 1)
+	exhausted := newDelivery(receiver.URL, 2)
+	repository := newFakeDeliveryRepository(retried, exhausted)
+
+	err := newTestDispatcher(repository, receiver).deliverDue(context.Background())
+	require.NoError(t, err)
+
+	assert.Empty(t, repository.delivered)
+	assert.Equal(t, failedDelivery{statusCode: http.StatusInternalServerError, retryAfter: 20 * time.Second}, repository.failed[retried.ID])
+	assert.True(t, repository.failed[exhausted.ID].dead)
+}
+
+func TestDeliverDueRefusesPrivateAddress(t *testing.T) {
+	received := 0
+	receiver := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	delivery := newDelivery(receiver.URL, 0)
+	repository := newFakeDeliveryRepository(delivery)
+
+	err := NewDispatcher(repository, Config{MaxAttempts: 3, Timeout: time.Second}).deliverDue(context.Background())
+	require.NoError(t, err)
+
+	assert.Zero(t, received)
+	assert.Empty(t, repository.delivered)
+	assert.Equal(t, failedDelivery{statusCode: 0, retryAfter: minRetryDelay}, repository.failed[delivery.ID])
+}
+
+func TestDeliverDueRefusesPlainHTTP(t *testing.T) {
+	received := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	delivery := newDelivery(receiver.URL, 0)
+	repository := newFakeDeliveryRepository(delivery)
+
+	err := newTestDispatcher(repository, receiver).deliverDue(context.Background())
+	require.NoError(t, err)
+
+	assert.Zero(t, received)
+	assert.Contains(t, repository.failed, delivery.ID)
+}
+
+func TestRetryDelayIsCapped(t *testing.T) {
+	assert.Equal(t, minRetryDelay, retryDelay(1))
+	assert.Equal(t, maxRetryDelay, retryDelay(100))
+}
@@ -0,0 +1,23 @@
+package dispatcher
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/webhook"
+)
+
+// Публикатор событий outbox, который вместо отправки создает доставки по подходящим подпискам
+// Сама отправка выполняется Dispatcher, поэтому недоступность получателей не задерживает relay
+type Scheduler struct {
+	repository webhook.DeliveryRepository
+}
+
+func NewScheduler(repository webhook.DeliveryRepository) *Scheduler {
+	return &Scheduler{
+		repository: repository,
+	}
+}
+
+func (s *Scheduler) Publish(ctx context.Context, event *models.Event) error {
+	return s.repository.ScheduleDeliveries(ctx, event)
+}
@@ -0,0 +1,8 @@
+package webhook
+
+import "errors"
+
+// Список возможных ошибок бизнес-логики подписок на события
+var ErrWebhookNotFound = errors.New("Webhook not found")
+var ErrDeliveryNotFound = errors.New("Webhook delivery not found")
+var ErrURLNotAllowed = errors.New("Webhook URL must use https and point to a public address")
@@ -0,0 +1,30 @@
+package webhook
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Интерфейс репозитория подписок
+// Подписку можно оформить только на кошельки её владельца
+type Repository interface {
+	CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+	GetSubscriptions(ctx context.Context, ownerId uuid.UUID) ([]*models.WebhookSubscription, error)
+	GetSubscription(ctx context.Context, ownerId uuid.UUID, subscriptionId uuid.UUID) (*models.WebhookSubscription, error)
+	GetDeliveries(ctx context.Context, subscriptionId uuid.UUID, limit int) ([]*models.WebhookDelivery, error)
+	ResetDelivery(ctx context.Context, subscriptionId uuid.UUID, deliveryId uuid.UUID) (*models.WebhookDelivery, error)
+}
+
+// Интерфейс репозитория доставок, который используют фоновые процессы
+//
+// ScheduleDeliveries создает по доставке на каждую подходящую под событие подписку, повторный вызов для того же события ничего не меняет.
+// ClaimDueDeliveries откладывает выбранные доставки на lease, чтобы их не взял другой экземпляр приложения
+type DeliveryRepository interface {
+	ScheduleDeliveries(ctx context.Context, event *models.Event) error
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, deliveryId uuid.UUID, statusCode int) error
+	MarkFailed(ctx context.Context, deliveryId uuid.UUID, statusCode int, lastError string, retryAfter time.Duration, dead bool) error
+}
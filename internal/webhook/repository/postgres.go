@@ -0,0 +1,362 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/wallet"
+	"infotecstechtask/internal/webhook"
+	"infotecstechtask/pkg/database"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+const subscriptionColumns = `id, owner_id, url, secret, event_types, wallet_ids, created_at`
+
+const deliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts, last_status_code, last_error, created_at, delivered_at`
+
+// Реализация репозитория подписок и доставок
+type WebhookRepository struct {
+	db *database.Client
+}
+
+func NewWebhookRepository(db *database.Client) *WebhookRepository {
+	return &WebhookRepository{
+		db: db,
+	}
+}
+
+// Реализация метода для создания подписки
+// Если хотя бы один из кошельков не принадлежит владельцу подписки, возвращается ошибка и подписка не создается
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	walletIds := toStrings(subscription.WalletIDs)
+
+	return r.db.ExecuteTx(ctx, func(tx pgx.Tx) error {
+		var owned int
+		err := tx.QueryRow(
+			ctx,
+			`SELECT COUNT(*) FROM wallets WHERE id = ANY($1) AND owner_id = $2`,
+			walletIds,
+			subscription.OwnerID,
+		).Scan(&owned)
+		if err != nil {
+			return fmt.Errorf("failed to check wallets: %w", err)
+		}
+		if owned != countUnique(walletIds) {
+			return wallet.ErrWalletNotOwned
+		}
+
+		eventTypes := make([]string, 0, len(subscription.EventTypes))
+		for _, eventType := range subscription.EventTypes {
+			eventTypes = append(eventTypes, string(eventType))
+		}
+
+		_, err = tx.Exec(
+			ctx,
+			`INSERT INTO webhook_subscriptions (`+subscriptionColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			subscription.ID,
+			subscription.OwnerID,
+			subscription.URL,
+			subscription.Secret,
+			eventTypes,
+			walletIds,
+			subscription.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create webhook subscription: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// Возвращает подписки владельца, отсортированные от новых к старым
+func (r *WebhookRepository) GetSubscriptions(ctx context.Context, ownerId uuid.UUID) ([]*models.WebhookSubscription, error) {
+	sql := `SELECT ` + subscriptionColumns + `
+            FROM webhook_subscriptions
+            WHERE owner_id = $1
+            ORDER BY created_at DESC`
+
+	rows, err := r.db.Query(ctx, sql, ownerId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subscriptions := []*models.WebhookSubscription{}
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// Возвращает подписку, если она принадлежит владельцу, иначе ошибку ErrWebhookNotFound
+func (r *WebhookRepository) GetSubscription(ctx context.Context, ownerId uuid.UUID, subscriptionId uuid.UUID) (*models.WebhookSubscription, error) {
+	row := r.db.QueryRow(
+		ctx,
+		`SELECT `+subscriptionColumns+` FROM webhook_subscriptions WHERE id = $1 AND owner_id = $2`,
+		subscriptionId,
+		ownerId,
+	)
+
+	subscription, err := scanSubscription(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, webhook.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// Возвращает последние доставки по подписке, отсортированные от новых к старым
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, subscriptionId uuid.UUID, limit int) ([]*models.WebhookDelivery, error) {
+	sql := `SELECT ` + deliveryColumns + `
+            FROM webhook_deliveries
+            WHERE subscription_id = $1
+            ORDER BY created_at DESC, event_id DESC
+            LIMIT $2`
+
+	rows, err := r.db.Query(ctx, sql, subscriptionId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]*models.WebhookDelivery, 0, limit)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// Реализация метода для ручной повторной доставки
+// Доставка в любом статусе возвращается в очередь с обнуленным счетчиком попыток
+func (r *WebhookRepository) ResetDelivery(ctx context.Context, subscriptionId uuid.UUID, deliveryId uuid.UUID) (*models.WebhookDelivery, error) {
+	row := r.db.QueryRow(
+		ctx,
+		`UPDATE webhook_deliveries
+         SET status = $3, attempts = 0, next_attempt_at = CURRENT_TIMESTAMP, last_error = ''
+         WHERE id = $1 AND subscription_id = $2
+         RETURNING `+deliveryColumns,
+		deliveryId,
+		subscriptionId,
+		models.WebhookDeliveryPending,
+	)
+
+	delivery, err := scanDelivery(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, webhook.ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// Реализация метода для создания доставок по событию
+// Уникальность пары подписка-событие делает повторную обработку одного события безопасной
+func (r *WebhookRepository) ScheduleDeliveries(ctx context.Context, event *models.Event) error {
+	return r.db.Exec(
+		ctx,
+		`INSERT INTO webhook_deliveries (id, subscription_id, event_id, event_type, payload, created_at)
+         SELECT gen_random_uuid()::VARCHAR, id, $1, $2::VARCHAR, $3, $4
+         FROM webhook_subscriptions
+         WHERE $5::VARCHAR = ANY(wallet_ids) AND $2::VARCHAR = ANY(event_types)
+         ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+		event.ID,
+		event.Type,
+		string(event.Payload),
+		event.CreatedAt,
+		event.WalletID,
+	)
+}
+
+// Реализация метода для выборки доставок, которые пора отправить
+// Строки блокируются с SKIP LOCKED, поэтому параллельные экземпляры приложения получают разные доставки
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error) {
+	sql := `WITH due AS (
+                SELECT id FROM webhook_deliveries
+                WHERE status = $1 AND next_attempt_at <= CURRENT_TIMESTAMP
+                ORDER BY next_attempt_at
+                LIMIT $2
+                FOR UPDATE SKIP LOCKED
+            )
+            UPDATE webhook_deliveries d
+            SET next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $3)
+            FROM due, webhook_subscriptions s
+            WHERE d.id = due.id AND s.id = d.subscription_id
+            RETURNING d.id, d.subscription_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+                      d.last_status_code, d.last_error, d.created_at, d.delivered_at, s.url, s.secret`
+
+	rows, err := r.db.Query(ctx, sql, models.WebhookDeliveryPending, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make([]*models.WebhookDelivery, 0, limit)
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		var payload string
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.SubscriptionID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.LastStatusCode,
+			&delivery.LastError,
+			&delivery.CreatedAt,
+			&delivery.DeliveredAt,
+			&delivery.URL,
+			&delivery.Secret,
+		)
+		if err != nil {
+			return nil, err
+		}
+		delivery.Payload = []byte(payload)
+		deliveries = append(deliveries, &delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, deliveryId uuid.UUID, statusCode int) error {
+	return r.db.Exec(
+		ctx,
+		`UPDATE webhook_deliveries
+         SET status = $2, attempts = attempts + 1, last_status_code = $3, last_error = '', delivered_at = CURRENT_TIMESTAMP
+         WHERE id = $1`,
+		deliveryId,
+		models.WebhookDeliveryDelivered,
+		statusCode,
+	)
+}
+
+// Откладывает следующую попытку на retryAfter, либо переводит доставку в статус dead, если попытки исчерпаны
+func (r *WebhookRepository) MarkFailed(ctx context.Context, deliveryId uuid.UUID, statusCode int, lastError string, retryAfter time.Duration, dead bool) error {
+	status := models.WebhookDeliveryPending
+	if dead {
+		status = models.WebhookDeliveryDead
+	}
+
+	return r.db.Exec(
+		ctx,
+		`UPDATE webhook_deliveries
+         SET status = $2, attempts = attempts + 1, last_status_code = $3, last_error = $4,
+             next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $5)
+         WHERE id = $1`,
+		deliveryId,
+		status,
+		statusCode,
+		lastError,
+		retryAfter.Seconds(),
+	)
+}
+
+// Функция для сборки подписки из строки результата запроса
+func scanSubscription(row pgx.Row) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	var eventTypes, walletIds []string
+	err := row.Scan(
+		&subscription.ID,
+		&subscription.OwnerID,
+		&subscription.URL,
+		&subscription.Secret,
+		&eventTypes,
+		&walletIds,
+		&subscription.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.EventTypes = make([]models.EventType, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		subscription.EventTypes = append(subscription.EventTypes, models.EventType(eventType))
+	}
+
+	subscription.WalletIDs = make([]uuid.UUID, 0, len(walletIds))
+	for _, walletId := range walletIds {
+		id, err := uuid.Parse(walletId)
+		if err != nil {
+			return nil, fmt.Errorf("invalid wallet id in subscription: %w", err)
+		}
+		subscription.WalletIDs = append(subscription.WalletIDs, id)
+	}
+
+	return &subscription, nil
+}
+
+// Функция для сборки доставки из строки результата запроса
+func scanDelivery(row pgx.Row) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	var payload string
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.SubscriptionID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.LastStatusCode,
+		&delivery.LastError,
+		&delivery.CreatedAt,
+		&delivery.DeliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	delivery.Payload = []byte(payload)
+
+	return &delivery, nil
+}
+
+func toStrings(ids []uuid.UUID) []string {
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, id.String())
+	}
+
+	return result
+}
+
+func countUnique(values []string) int {
+	unique := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		unique[value] = struct{}{}
+	}
+
+	return len(unique)
+}
@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/wallet"
+	"infotecstechtask/internal/webhook"
+	"infotecstechtask/pkg/database"
+	"infotecstechtask/test/testutils"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	owner         = uuid.MustParse("5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e01")
+	ownedWallet   = uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10")
+	foreignWallet = uuid.MustParse("b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a13")
+)
+
+type WebhookRepositoryTestSuite struct {
+	suite.Suite
+	pgContainer *testutils.PGTestContainer
+	repo        *WebhookRepository
+	fixtures    *testutils.FixtureManager
+	ctx         context.Context
+}
+
+func (suite *WebhookRepositoryTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+
+	container, err := testutils.StartPGContainer(suite.ctx)
+	if err != nil {
+		log.Fatalf("Failed to start test container: %v", err)
+	}
+	suite.pgContainer = container
+
+	suite.repo = NewWebhookRepository(database.NewClientWithPool(container.Pool))
+	suite.fixtures = testutils.NewFixtureManager(container.Pool)
+}
+
+func (suite *WebhookRepositoryTestSuite) TearDownSuite() {
+	if suite.pgContainer != nil {
+		if err := suite.pgContainer.Close(suite.ctx); err != nil {
+			log.Printf("Failed to close test container: %v", err)
+		}
+	}
+}
+
+func (suite *WebhookRepositoryTestSuite) BeforeTest(_, _ string) {
+	_, err := suite.pgContainer.Pool.Exec(suite.ctx, "TRUNCATE TABLE wallets, webhook_subscriptions, webhook_deliveries CASCADE")
+	if err != nil {
+		log.Printf("TRUNCATE error: %v", err)
+	}
+
+	assert.NoError(suite.T(), err)
+
+	err = suite.fixtures.ApplySQLFixture(suite.ctx, "wallets/wallets.sql")
+	suite.Require().NoError(err)
+	err = suite.fixtures.ApplySQLFixture(suite.ctx, "wallets/wallet_owners.sql")
+	suite.Require().NoError(err)
+}
+
+func TestWebhookRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookRepositoryTestSuite))
+}
+
+func (suite *WebhookRepositoryTestSuite) TestCreateSubscriptionForForeignWallet() {
+	subscription := suite.newSubscription(ownedWallet, foreignWallet)
+
+	err := suite.repo.CreateSubscription(suite.ctx, subscription)
+	suite.Assert().ErrorIs(err, wallet.ErrWalletNotOwned)
+
+	subscriptions, err := suite.repo.GetSubscriptions(suite.ctx, owner)
+	suite.Require().NoError(err)
+	suite.Assert().Empty(subscriptions)
+}
+
+func (suite *WebhookRepositoryTestSuite) TestScheduleDeliveriesForMatchingSubscriptions() {
+	subscription := suite.newSubscription(ownedWallet)
+	suite.Require().NoError(suite.repo.CreateSubscription(suite.ctx, subscription))
+
+	actual, err := suite.repo.GetSubscription(suite.ctx, owner, subscription.ID)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(subscription.WalletIDs, actual.WalletIDs)
+	suite.Assert().Equal(subscription.EventTypes, actual.EventTypes)
+
+	matching := &models.Event{ID: 1, WalletID: ownedWallet, Type: models.EventWalletBalanceChanged, Payload: json.RawMessage(`{}`), CreatedAt: time.Now()}
+	otherType := &models.Event{ID: 2, WalletID: ownedWallet, Type: models.EventTransactionFailed, Payload: json.RawMessage(`{}`), CreatedAt: time.Now()}
+	otherWallet := &models.Event{ID: 3, WalletID: foreignWallet, Type: models.EventWalletBalanceChanged, Payload: json.RawMessage(`{}`), CreatedAt: time.Now()}
+
+	for _, event := range []*models.Event{matching, matching, otherType, otherWallet} {
+		suite.Require().NoError(suite.repo.ScheduleDeliveries(suite.ctx, event))
+	}
+
+	deliveries, err := suite.repo.GetDeliveries(suite.ctx, subscription.ID, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(deliveries, 1)
+	suite.Assert().Equal(matching.ID, deliveries[0].EventID)
+
+	claimed, err := suite.repo.ClaimDueDeliveries(suite.ctx, 10, time.Minute)
+	suite.Require().NoError(err)
+	suite.Require().Len(claimed, 1)
+	suite.Assert().Equal(subscription.URL, claimed[0].URL)
+	suite.Assert().Equal(subscription.Secret, claimed[0].Secret)
+
+	claimed, err = suite.repo.ClaimDueDeliveries(suite.ctx, 10, time.Minute)
+	suite.Require().NoError(err)
+	suite.Assert().Empty(claimed)
+}
+
+func (suite *WebhookRepositoryTestSuite) TestDeadDeliveryCanBeRedelivered() {
+	subscription := suite.newSubscription(ownedWallet)
+	suite.Require().NoError(suite.repo.CreateSubscription(suite.ctx, subscription))
+
+	event := &models.Event{ID: 1, WalletID: ownedWallet, Type: models.EventWalletBalanceChanged, Payload: json.RawMessage(`{}`), CreatedAt: time.Now()}
+	suite.Require().NoError(suite.repo.ScheduleDeliveries(suite.ctx, event))
+
+	deliveries, err := suite.repo.GetDeliveries(suite.ctx, subscription.ID, 10)
+	suite.Require().NoError(err)
+	suite.Require().Len(deliveries, 1)
+
+	err = suite.repo.MarkFailed(suite.ctx, deliveries[0].ID, 500, "webhook responded with status 500", 0, true)
+	suite.Require().NoError(err)
+
+	claimed, err := suite.repo.ClaimDueDeliveries(suite.ctx, 10, time.Minute)
+	suite.Require().NoError(err)
+	suite.Assert().Empty(claimed)
+
+	reset, err := suite.repo.ResetDelivery(suite.ctx, subscription.ID, deliveries[0].ID)
+	suite.Require().NoError(err)
+	suite.Assert().Equal(models.WebhookDeliveryPending, reset.Status)
+	suite.Assert().Equal(0, reset.Attempts)
+	suite.Assert().Equal(500, reset.LastStatusCode)
+
+	claimed, err = suite.repo.ClaimDueDeliveries(suite.ctx, 10, time.Minute)
+	suite.Require().NoError(err)
+	suite.Assert().Len(claimed, 1)
+
+	_, err = suite.repo.ResetDelivery(suite.ctx, uuid.New(), deliveries[0].ID)
+	suite.Assert().ErrorIs(err, webhook.ErrDeliveryNotFound)
+}
+
+func (suite *WebhookRepositoryTestSuite) newSubscription(walletIds ...uuid.UUID) *models.WebhookSubscription {
+	return &models.WebhookSubscription{
+		ID:         uuid.New(),
+		OwnerID:    owner,
+		URL:        "https://merchant.example.com/hooks",
+		Secret:     "secret",
+		EventTypes: []models.EventType{models.EventWalletBalanceChanged},
+		WalletIDs:  walletIds,
+		CreatedAt:  time.Now(),
+	}
+}
@@ -0,0 +1,17 @@
+package webhook
+
+import (
+	"context"
+	"infotecstechtask/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Интерфейс сервиса
+// Все методы работают только с подписками вызывающей стороны
+type Service interface {
+	CreateWebhook(ctx context.Context, ownerId uuid.UUID, request *models.CreateWebhookRequest) (*models.WebhookResponse, error)
+	GetWebhooks(ctx context.Context, ownerId uuid.UUID) ([]*models.WebhookResponse, error)
+	GetDeliveries(ctx context.Context, ownerId uuid.UUID, webhookId uuid.UUID) ([]*models.WebhookDeliveryResponse, error)
+	Redeliver(ctx context.Context, ownerId uuid.UUID, webhookId uuid.UUID, deliveryId uuid.UUID) (*models.WebhookDeliveryResponse, error)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"infotecstechtask/internal/models"
+	"infotecstechtask/internal/webhook"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Количество последних доставок, которые возвращаются в истории
+const deliveryHistoryLimit = 100
+
+// Реализация сервиса
+// Ответственна за создание подписок, проверку владельца и маппинг моделей
+type WebhookService struct {
+	webhookRepository webhook.Repository
+}
+
+func NewWebhookService(webhookRepository webhook.Repository) *WebhookService {
+	return &WebhookService{
+		webhookRepository: webhookRepository,
+	}
+}
+
+// Реализация метода для создания подписки
+// Адрес должен использовать https и указывать на публичный хост, секрет генерируется на сервере и возвращается только в ответе на создание
+func (s WebhookService) CreateWebhook(ctx context.Context, ownerId uuid.UUID, request *models.CreateWebhookRequest) (*models.WebhookResponse, error) {
+	if err := webhook.ValidateURL(ctx, request.URL); err != nil {
+		return nil, err
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	subscription := models.ToWebhookSubscription(request, uuid.New(), ownerId, secret, time.Now().UTC())
+	if err := s.webhookRepository.CreateSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	response := models.ToWebhookResponse(subscription)
+	response.Secret = subscription.Secret
+
+	return response, nil
+}
+
+func (s WebhookService) GetWebhooks(ctx context.Context, ownerId uuid.UUID) ([]*models.WebhookResponse, error) {
+	subscriptions, err := s.webhookRepository.GetSubscriptions(ctx, ownerId)
+
+	return models.ToWebhookResponses(subscriptions), err
+}
+
+func (s WebhookService) GetDeliveries(ctx context.Context, ownerId uuid.UUID, webhookId uuid.UUID) ([]*models.WebhookDeliveryResponse, error) {
+	if _, err := s.webhookRepository.GetSubscription(ctx, ownerId, webhookId); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.webhookRepository.GetDeliveries(ctx, webhookId, deliveryHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.ToWebhookDeliveryResponses(deliveries), nil
+}
+
+func (s WebhookService) Redeliver(ctx context.Context, ownerId uuid.UUID, webhookId uuid.UUID, deliveryId uuid.UUID) (*models.WebhookDeliveryResponse, error) {
+	if _, err := s.webhookRepository.GetSubscription(ctx, ownerId, webhookId); err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.webhookRepository.ResetDelivery(ctx, webhookId, deliveryId)
+	if err != nil {
+		return nil, err
+	}
+
+	return models.ToWebhookDeliveryResponse(delivery), nil
+}
@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Заголовок с подписью доставки
+// Формат значения: t=<unix время отправки>,v1=<hex HMAC-SHA256 от "<t>.<тело запроса>">
+const SignatureHeader = "X-Webhook-Signature"
+
+// Функция для генерации секрета подписки
+func GenerateSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(secret), nil
+}
+
+// Функция для подписи тела запроса
+// Время отправки входит в подпись, что позволяет получателю отбрасывать старые запросы
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	unix := timestamp.Unix()
+	return fmt.Sprintf("t=%d,v1=%s", unix, computeSignature(secret, unix, body))
+}
+
+// Функция для проверки подписи на стороне получателя
+// Подпись считается недействительной, если она создана раньше, чем tolerance назад
+func Verify(secret string, header string, body []byte, tolerance time.Duration) bool {
+	var unix int64
+	var signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			unix, _ = strconv.ParseInt(value, 10, 64)
+		case "v1":
+			signature = value
+		}
+	}
+
+	if unix == 0 || signature == "" || time.Since(time.Unix(unix, 0)) > tolerance {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(computeSignature(secret, unix, body)))
+}
+
+func computeSignature(secret string, unix int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", unix)
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
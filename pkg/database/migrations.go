@@ -0,0 +1,319 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Версия схемы БД, с которой работает приложение, совпадает с номером последней миграции в init/migrations
+// При добавлении миграции значение нужно увеличить, это проверяется тестом
+const SchemaVersion = 13
+
+// Ключ advisory блокировки, под которой выполняются миграции, чтобы несколько экземпляров не применяли их одновременно
+const migrationLockKey int64 = 7_263_000
+
+// Таблица версий в формате golang-migrate, поэтому схемы, созданные внешней утилитой migrate, продолжают обновляться
+const createSchemaMigrations = `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Ошибка, которая возвращается, если миграции ни разу не применялись
+var ErrNoMigrations = errors.New("no migrations applied")
+
+// Ошибка, которая возвращается, если последняя миграция завершилась ошибкой
+// Схему нужно исправить вручную и отметить версию командой migrate force
+var ErrDirtySchema = errors.New("schema is dirty")
+
+// Миграция схемы, Down может быть пустым, если откат не требует изменений
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Состояние схемы относительно известных приложению миграций
+type MigrationStatus struct {
+	Version int64   `json:"version"`
+	Dirty   bool    `json:"dirty"`
+	Latest  int64   `json:"latest"`
+	Pending []int64 `json:"pending"`
+}
+
+// Функция возвращает текущую версию схемы из таблицы schema_migrations, которую ведет golang-migrate
+// dirty означает, что последняя миграция завершилась ошибкой и схема может быть в промежуточном состоянии
+func (db *Client) MigrationVersion(ctx context.Context) (version int64, dirty bool, err error) {
+	err = db.pool.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, ErrNoMigrations
+	}
+
+	return version, dirty, err
+}
+
+// Функция читает миграции из корня fsys, для каждой версии обязателен файл .up.sql
+// Файлы с другими именами пропускаются
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	hasUp := make(map[int64]bool)
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %s", entry.Name())
+		}
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has different names: %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+			hasUp[version] = true
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for version, migration := range byVersion {
+		if !hasUp[version] {
+			return nil, fmt.Errorf("migration %d_%s has no up file", version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Структура применяет и откатывает миграции
+// Все операции выполняются на одном соединении под advisory блокировкой migrationLockKey:
+// если миграции уже выполняет другой экземпляр, вызов ждет его завершения
+type Migrator struct {
+	db         *Client
+	migrations []Migration
+}
+
+func NewMigrator(db *Client, fsys fs.FS) (*Migrator, error) {
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// Функция применяет все миграции новее текущей версии и возвращает их версии
+func (m *Migrator) Up(ctx context.Context) ([]int64, error) {
+	var applied []int64
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		version, err := cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if migration.Version <= version {
+				continue
+			}
+			if err := runMigration(ctx, conn, migration.Version, migration.Version, migration.Up); err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			slog.InfoContext(ctx, "migration applied", "version", migration.Version, "name", migration.Name)
+			applied = append(applied, migration.Version)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Функция откатывает steps последних миграций и возвращает версии откаченных миграций
+func (m *Migrator) Down(ctx context.Context, steps int) ([]int64, error) {
+	var reverted []int64
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		version, err := cleanVersion(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		index := m.indexOf(version)
+		if version != 0 && index < 0 {
+			return fmt.Errorf("schema version %d is unknown to this build", version)
+		}
+
+		for ; index >= 0 && len(reverted) < steps; index-- {
+			migration := m.migrations[index]
+			var previous int64
+			if index > 0 {
+				previous = m.migrations[index-1].Version
+			}
+			if err := runMigration(ctx, conn, migration.Version, previous, migration.Down); err != nil {
+				return fmt.Errorf("rollback of migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			slog.InfoContext(ctx, "migration reverted", "version", migration.Version, "name", migration.Name)
+			reverted = append(reverted, migration.Version)
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Функция записывает версию схемы и снимает признак dirty, сами миграции при этом не выполняются
+// Версия 0 означает, что ни одна миграция не применена
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != 0 && m.indexOf(version) < 0 {
+		return fmt.Errorf("migration %d does not exist", version)
+	}
+
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		return setVersion(ctx, conn, version, false)
+	})
+}
+
+// Функция возвращает текущую версию схемы и список неприменённых миграций
+func (m *Migrator) Status(ctx context.Context) (*MigrationStatus, error) {
+	status := &MigrationStatus{
+		Pending: []int64{},
+	}
+	if len(m.migrations) > 0 {
+		status.Latest = m.migrations[len(m.migrations)-1].Version
+	}
+
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		var err error
+		status.Version, status.Dirty, err = readVersion(ctx, conn)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version > status.Version {
+			status.Pending = append(status.Pending, migration.Version)
+		}
+	}
+
+	return status, nil
+}
+
+func (m *Migrator) indexOf(version int64) int {
+	for i, migration := range m.migrations {
+		if migration.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.db.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		unlockCtx := context.WithoutCancel(ctx)
+		if _, err := conn.Exec(unlockCtx, `SELECT pg_advisory_unlock($1)`, migrationLockKey); err != nil {
+			slog.ErrorContext(ctx, "failed to release migration lock", "error", err)
+			conn.Conn().Close(unlockCtx)
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, createSchemaMigrations); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// Функция возвращает текущую версию, если схема не в состоянии dirty
+func cleanVersion(ctx context.Context, conn *pgxpool.Conn) (int64, error) {
+	version, dirty, err := readVersion(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+	if dirty {
+		return 0, fmt.Errorf("%w at version %d: fix the schema manually and run migrate force", ErrDirtySchema, version)
+	}
+	return version, nil
+}
+
+func readVersion(ctx context.Context, conn *pgxpool.Conn) (version int64, dirty bool, err error) {
+	err = conn.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	return version, dirty, err
+}
+
+// Функция выполняет миграцию так же, как golang-migrate: сначала записывает целевую версию с признаком dirty,
+// затем выполняет SQL и снимает признак. Если SQL завершился ошибкой, схема остается в состоянии dirty
+func runMigration(ctx context.Context, conn *pgxpool.Conn, version int64, target int64, sql string) error {
+	dirtyVersion := target
+	if target == 0 {
+		dirtyVersion = version
+	}
+	if err := setVersion(ctx, conn, dirtyVersion, true); err != nil {
+		return err
+	}
+	// Без аргументов запрос выполняется простым протоколом, поэтому все выражения файла выполняются в одной неявной транзакции
+	if _, err := conn.Exec(ctx, sql); err != nil {
+		return err
+	}
+	return setVersion(ctx, conn, target, false)
+}
+
+func setVersion(ctx context.Context, conn *pgxpool.Conn, version int64, dirty bool) error {
+	return conn.BeginFunc(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations`); err != nil {
+			return err
+		}
+		if version == 0 {
+			return nil
+		}
+		_, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)`, version, dirty)
+		return err
+	})
+}
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"fmt"
+	"infotecstechtask/init/migrations"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaVersionMatchesLatestMigration(t *testing.T) {
+	loaded, err := LoadMigrations(migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, loaded)
+
+	assert.Equal(t, loaded[len(loaded)-1].Version, int64(SchemaVersion))
+}
+
+func TestEveryMigrationHasDownFile(t *testing.T) {
+	loaded, err := LoadMigrations(migrations.FS)
+	require.NoError(t, err)
+	for _, migration := range loaded {
+		_, err := migrations.FS.Open(fmt.Sprintf("%06d_%s.down.sql", migration.Version, migration.Name))
+		assert.NoError(t, err, "migration %d_%s has no down file", migration.Version, migration.Name)
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	loaded, err := LoadMigrations(fstest.MapFS{
+		"000002_add_index.up.sql":      {Data: []byte("CREATE INDEX")},
+		"000001_create_table.up.sql":   {Data: []byte("CREATE TABLE")},
+		"000001_create_table.down.sql": {Data: []byte("DROP TABLE")},
+		"migrations.go":                {Data: []byte("package migrations")},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []Migration{
+		{Version: 1, Name: "create_table", Up: "CREATE TABLE", Down: "DROP TABLE"},
+		{Version: 2, Name: "add_index", Up: "CREATE INDEX"},
+	}, loaded)
+}
+
+func TestLoadMigrationsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+	}{
+		{name: "down without up", files: fstest.MapFS{"000001_create_table.down.sql": {}}},
+		{name: "different names", files: fstest.MapFS{"000001_create_table.up.sql": {}, "000001_drop_table.down.sql": {}}},
+		{name: "zero version", files: fstest.MapFS{"000000_create_table.up.sql": {}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadMigrations(tt.files)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -4,16 +4,44 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
 
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
+// Максимальное количество попыток выполнить транзакцию при конфликтах сериализации
+const maxTxAttempts = 5
+
+// Коды ошибок Postgres, при которых транзакцию можно безопасно повторить целиком
+const (
+	serializationFailureCode = "40001"
+	deadlockDetectedCode     = "40P01"
+)
+
 // Обвязка для postgres клиента
 // Используется pgx connection pool
+// Запросы только на чтение (ReadQuery, ReadQueryRow) могут выполняться на репликах, остальные - на основной БД
 type Client struct {
 	pool *pgxpool.Pool
+
+	replicas      []*replica
+	maxReplicaLag time.Duration
+	nextReplica   atomic.Uint64
+	stopMonitor   context.CancelFunc
+
+	serializationRetries atomic.Uint64
+	deadlockRetries      atomic.Uint64
+}
+
+// Количество повторов транзакций с момента запуска, по причинам
+type RetryStats struct {
+	SerializationFailures uint64
+	Deadlocks             uint64
 }
 
 func NewClientWithPool(pool *pgxpool.Pool) *Client {
@@ -22,28 +50,97 @@ func NewClientWithPool(pool *pgxpool.Pool) *Client {
 	}
 }
 
+// Задержки между попытками подключения при старте
+const (
+	initialConnectBackoff = 100 * time.Millisecond
+	maxConnectBackoff     = 5 * time.Second
+)
+
+// Функция создает пул соединений к основной БД и пулы к репликам из config.ReplicaURLs
+// Пока БД недоступна, попытки подключения повторяются с экспоненциальной задержкой в пределах config.ConnectTimeout,
+// если он не задан - до отмены ctx. Ошибки в параметрах подключения не повторяются
 func NewClient(ctx context.Context, config Config) (*Client, error) {
-	dsn := fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode,
-	)
+	poolConfig, err := ParseConfig(config)
+	if err != nil {
+		return nil, err
+	}
 
-	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if config.ConnectTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.ConnectTimeout)
+		defer cancel()
+	}
+
+	backoff := initialConnectBackoff
+	for attempt := 1; ; attempt++ {
+		pool, err := pgxpool.ConnectConfig(ctx, poolConfig)
+		if err == nil {
+			db := &Client{
+				pool:          pool,
+				maxReplicaLag: config.MaxReplicaLag,
+			}
+			if err := db.connectReplicas(config); err != nil {
+				db.Close()
+				return nil, fmt.Errorf("failed to configure replica: %w", err)
+			}
+			return db, nil
+		}
+
+		delay := backoff + rand.N(backoff/2)
+		slog.WarnContext(ctx, "failed to connect to database", "attempt", attempt, "retry_in", delay, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to connect to database after %d attempts: %w", attempt, err)
+		case <-time.After(delay):
+		}
+		backoff = min(backoff*2, maxConnectBackoff)
+	}
+}
+
+// Функция собирает параметры пула из конфига
+func ParseConfig(config Config) (*pgxpool.Config, error) {
+	connString, err := config.ConnString()
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse connection string: %w", err)
+		return nil, err
 	}
 
-	pool, err := pgxpool.ConnectConfig(ctx, poolConfig)
+	poolConfig, err := pgxpool.ParseConfig(connString)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, fmt.Errorf("failed to parse connection string: %w", err)
 	}
+	applyPoolConfig(poolConfig, config.Pool)
+	poolConfig.ConnConfig.Logger = queryTracer{}
+	poolConfig.ConnConfig.LogLevel = pgx.LogLevelInfo
 
-	return &Client{
-		pool: pool,
-	}, nil
+	return poolConfig, nil
+}
+
+func applyPoolConfig(poolConfig *pgxpool.Config, config PoolConfig) {
+	if config.MaxConns > 0 {
+		poolConfig.MaxConns = config.MaxConns
+	}
+	if config.MinConns > 0 {
+		poolConfig.MinConns = config.MinConns
+	}
+	if config.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = config.MaxConnLifetime
+	}
+	if config.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = config.MaxConnIdleTime
+	}
+	if config.HealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = config.HealthCheckPeriod
+	}
 }
 
 func (db *Client) Close() {
+	if db.stopMonitor != nil {
+		db.stopMonitor()
+	}
+	for _, r := range db.replicas {
+		r.pool.Close()
+	}
 	db.pool.Close()
 }
 
@@ -51,6 +148,19 @@ func (db *Client) Ping(ctx context.Context) error {
 	return db.pool.Ping(ctx)
 }
 
+// Статистика пула соединений
+func (db *Client) Stat() *pgxpool.Stat {
+	return db.pool.Stat()
+}
+
+// Статистика повторов транзакций в ExecuteTx
+func (db *Client) RetryStats() RetryStats {
+	return RetryStats{
+		SerializationFailures: db.serializationRetries.Load(),
+		Deadlocks:             db.deadlockRetries.Load(),
+	}
+}
+
 // Обвязка для pgx функции Exec
 func (db *Client) Exec(ctx context.Context, sql string, args ...interface{}) error {
 	_, err := db.pool.Exec(ctx, sql, args...)
@@ -68,7 +178,30 @@ func (db *Client) QueryRow(ctx context.Context, sql string, args ...interface{})
 }
 
 // Обвязка для pgx функции BeginTx
+// Транзакция выполняется с уровнем изоляции Serializable
+// При конфликте сериализации или дедлоке fn выполняется заново в новой транзакции,
+// поэтому fn не должна иметь побочных эффектов вне БД
 func (db *Client) ExecuteTx(ctx context.Context, fn func(pgx.Tx) error) error {
+	var err error
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		err = db.executeTxOnce(ctx, fn)
+		if !isRetryable(err) || attempt == maxTxAttempts {
+			return err
+		}
+		db.countRetry(err)
+
+		backoff := time.Duration(attempt*attempt)*5*time.Millisecond + rand.N(5*time.Millisecond)
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(backoff):
+		}
+	}
+
+	return err
+}
+
+func (db *Client) executeTxOnce(ctx context.Context, fn func(pgx.Tx) error) error {
 	tx, err := db.pool.BeginTx(ctx, pgx.TxOptions{
 		IsoLevel: pgx.Serializable,
 	})
@@ -80,7 +213,7 @@ func (db *Client) ExecuteTx(ctx context.Context, fn func(pgx.Tx) error) error {
 		if p := recover(); p != nil {
 			rollbackErr := tx.Rollback(ctx)
 			if rollbackErr != nil {
-				log.Printf("rollback error during panic: %v", rollbackErr)
+				slog.ErrorContext(ctx, "rollback error during panic", "error", rollbackErr)
 			}
 			panic(p)
 		}
@@ -95,3 +228,83 @@ func (db *Client) ExecuteTx(ctx context.Context, fn func(pgx.Tx) error) error {
 
 	return tx.Commit(ctx)
 }
+
+func (db *Client) countRetry(err error) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == deadlockDetectedCode {
+		db.deadlockRetries.Add(1)
+		return
+	}
+	db.serializationRetries.Add(1)
+}
+
+// Функция проверяет, что ошибка вызвана конфликтом сериализации или дедлоком
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code == serializationFailureCode || pgErr.Code == deadlockDetectedCode
+	}
+
+	return false
+}
+
+// Функция выполняет fn, удерживая сессионную advisory блокировку с ключом key
+// Если блокировку уже удерживает другой процесс, fn не вызывается и возвращается false
+// Блокировка снимается после завершения fn, если снять её не удалось, соединение закрывается
+func (db *Client) WithAdvisoryLock(ctx context.Context, key int64, fn func(ctx context.Context) error) (bool, error) {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Release()
+
+	var acquired bool
+	err = conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&acquired)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire advisory lock: %w", err)
+	}
+	if !acquired {
+		return false, nil
+	}
+
+	defer func() {
+		unlockCtx := context.WithoutCancel(ctx)
+		if _, err := conn.Exec(unlockCtx, `SELECT pg_advisory_unlock($1)`, key); err != nil {
+			slog.ErrorContext(ctx, "failed to release advisory lock", "key", key, "error", err)
+			conn.Conn().Close(unlockCtx)
+		}
+	}()
+
+	return true, fn(ctx)
+}
+
+// Функция подписывается на канал уведомлений Postgres и вызывает fn для каждого полученного уведомления
+// Для подписки используется отдельное соединение из пула, которое удерживается до отмены контекста или ошибки
+// Функция возвращает управление только при отмене контекста или потере соединения
+func (db *Client) Listen(ctx context.Context, channel string, fn func(payload string)) error {
+	conn, err := db.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, `LISTEN `+pgx.Identifier{channel}.Sanitize())
+	if err != nil {
+		return fmt.Errorf("failed to listen channel %s: %w", channel, err)
+	}
+	defer func() {
+		unlistenCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second)
+		defer cancel()
+		if _, err := conn.Exec(unlistenCtx, `UNLISTEN *`); err != nil {
+			conn.Conn().Close(unlistenCtx)
+		}
+	}()
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		fn(notification.Payload)
+	}
+}
@@ -0,0 +1,66 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"infotecstechtask/pkg/database"
+	"infotecstechtask/test/testutils"
+	"log"
+	"testing"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/suite"
+)
+
+type ClientTestSuite struct {
+	suite.Suite
+	pgContainer *testutils.PGTestContainer
+	client      *database.Client
+	ctx         context.Context
+}
+
+func (suite *ClientTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+
+	container, err := testutils.StartPGContainer(suite.ctx)
+	if err != nil {
+		log.Fatalf("Failed to start test container: %v", err)
+	}
+	suite.pgContainer = container
+
+	suite.client = database.NewClientWithPool(container.Pool)
+}
+
+func (suite *ClientTestSuite) TearDownSuite() {
+	if suite.pgContainer != nil {
+		if err := suite.pgContainer.Close(suite.ctx); err != nil {
+			log.Printf("Failed to close test container: %v", err)
+		}
+	}
+}
+
+func TestClientTestSuite(t *testing.T) {
+	suite.Run(t, new(ClientTestSuite))
+}
+
+func (suite *ClientTestSuite) TestReadTxIsReadOnlyRepeatableRead() {
+	var isolation, readOnly string
+	err := suite.client.ReadTx(suite.ctx, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(suite.ctx, "SHOW transaction_isolation").Scan(&isolation); err != nil {
+			return err
+		}
+		return tx.QueryRow(suite.ctx, "SHOW transaction_read_only").Scan(&readOnly)
+	})
+	suite.Require().NoError(err)
+	suite.Assert().Equal("repeatable read", isolation)
+	suite.Assert().Equal("on", readOnly)
+
+	err = suite.client.ReadTx(suite.ctx, func(tx pgx.Tx) error {
+		_, err := tx.Exec(suite.ctx, "CREATE TABLE read_tx_test (id INTEGER)")
+		return err
+	})
+	var pgErr *pgconn.PgError
+	suite.Require().True(errors.As(err, &pgErr))
+	suite.Assert().Equal("25006", pgErr.Code)
+}
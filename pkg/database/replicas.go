@@ -0,0 +1,254 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Интервал проверки доступности и отставания реплик
+const replicaCheckInterval = 2 * time.Second
+
+// Отставание реплики: если реплика применила все полученные WAL записи, она не отстает,
+// иначе отставание считается от времени последней примененной транзакции
+const replicaLagQuery = `SELECT COALESCE(
+    CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+         ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()) END, 0)::FLOAT8`
+
+type primaryKey struct{}
+
+// Функция помечает контекст: чтения в нем выполняются только на основной БД
+// Используется, когда клиенту нужно увидеть результат своей записи (read-your-writes)
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// Функция проверяет, что чтения в контексте должны выполняться на основной БД
+func PrimaryRequired(ctx context.Context) bool {
+	required, _ := ctx.Value(primaryKey{}).(bool)
+	return required
+}
+
+// Реплика для чтения
+// Состояние обновляет фоновая проверка, а также запрос, завершившийся ошибкой соединения
+type replica struct {
+	host    string
+	pool    *pgxpool.Pool
+	healthy atomic.Bool
+	lag     atomic.Int64
+}
+
+// Состояние реплики для метрик
+type ReplicaStat struct {
+	Host    string
+	Healthy bool
+	Lag     time.Duration
+}
+
+// Функция подключается к репликам
+// Пул реплики создается без установки соединения, поэтому недоступная при старте реплика не мешает запуску:
+// до первой успешной проверки чтения идут на основную БД
+func (db *Client) connectReplicas(config Config) error {
+	for _, replicaURL := range config.ReplicaURLs {
+		replicaConfig := config
+		replicaConfig.URL = replicaURL
+
+		poolConfig, err := ParseConfig(replicaConfig)
+		if err != nil {
+			return err
+		}
+		poolConfig.LazyConnect = true
+
+		pool, err := pgxpool.ConnectConfig(context.Background(), poolConfig)
+		if err != nil {
+			return err
+		}
+		host := net.JoinHostPort(poolConfig.ConnConfig.Host, strconv.Itoa(int(poolConfig.ConnConfig.Port)))
+		db.replicas = append(db.replicas, &replica{host: host, pool: pool})
+	}
+
+	if len(db.replicas) > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		db.stopMonitor = cancel
+		go db.monitorReplicas(ctx)
+	}
+
+	return nil
+}
+
+func (db *Client) monitorReplicas(ctx context.Context) {
+	ticker := time.NewTicker(replicaCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, r := range db.replicas {
+			db.checkReplica(ctx, r)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (db *Client) checkReplica(ctx context.Context, r *replica) {
+	ctx, cancel := context.WithTimeout(ctx, replicaCheckInterval)
+	defer cancel()
+
+	var lagSeconds float64
+	err := r.pool.QueryRow(ctx, replicaLagQuery).Scan(&lagSeconds)
+	if err != nil {
+		if r.healthy.Swap(false) {
+			slog.WarnContext(ctx, "replica is unavailable, reads go to primary", "host", r.host, "error", err)
+		}
+		return
+	}
+
+	r.lag.Store(int64(lagSeconds * float64(time.Second)))
+	if !r.healthy.Swap(true) {
+		slog.InfoContext(ctx, "replica is available", "host", r.host)
+	}
+}
+
+// Функция выбирает реплику для чтения по кругу среди доступных реплик, отстающих не больше чем на maxReplicaLag
+// Возвращает nil, если чтение должно идти на основную БД
+func (db *Client) reader(ctx context.Context) *replica {
+	if len(db.replicas) == 0 || PrimaryRequired(ctx) {
+		return nil
+	}
+
+	// Выбор идет среди подходящих реплик, иначе реплика, следующая за недоступной, получала бы двойную нагрузку
+	available := make([]*replica, 0, len(db.replicas))
+	for _, r := range db.replicas {
+		if r.healthy.Load() && time.Duration(r.lag.Load()) <= db.maxReplicaLag {
+			available = append(available, r)
+		}
+	}
+	if len(available) == 0 {
+		return nil
+	}
+
+	return available[db.nextReplica.Add(1)%uint64(len(available))]
+}
+
+// Функция решает, нужно ли повторить чтение на основной БД после ошибки на реплике
+// Ошибки Postgres (синтаксис, отсутствие строк) повторять бессмысленно, ошибки соединения выводят реплику из ротации
+func (db *Client) fallback(ctx context.Context, r *replica, err error) bool {
+	var pgErr *pgconn.PgError
+	if ctx.Err() != nil || errors.Is(err, pgx.ErrNoRows) || errors.As(err, &pgErr) {
+		return false
+	}
+
+	if r.healthy.Swap(false) {
+		slog.WarnContext(ctx, "replica query failed, reads go to primary", "host", r.host, "error", err)
+	}
+	return true
+}
+
+// Обвязка для pgx функции Query для запросов только на чтение
+// Запрос выполняется на реплике, если она доступна и не отстает, иначе на основной БД
+func (db *Client) ReadQuery(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	r := db.reader(ctx)
+	if r == nil {
+		return db.pool.Query(ctx, sql, args...)
+	}
+
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil && db.fallback(ctx, r, err) {
+		return db.pool.Query(ctx, sql, args...)
+	}
+	return rows, err
+}
+
+// Обвязка для pgx функции QueryRow для запросов только на чтение, правила выбора БД те же, что у ReadQuery
+func (db *Client) ReadQueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	r := db.reader(ctx)
+	if r == nil {
+		return db.pool.QueryRow(ctx, sql, args...)
+	}
+
+	return &replicaRow{
+		db:      db,
+		replica: r,
+		ctx:     ctx,
+		sql:     sql,
+		args:    args,
+	}
+}
+
+// Обвязка для pgx функции BeginTx для нескольких запросов только на чтение, которые должны видеть одни и те же данные
+// Транзакция выполняется на реплике по правилам ReadQuery в режиме только чтения с уровнем Repeatable Read:
+// Serializable на реплике недоступен, а снимка данных достаточно, конфликтов сериализации у чтений нет, поэтому повторов нет.
+// fn должна возвращать только ошибки запросов: остальные ошибки считаются ошибками соединения с репликой
+func (db *Client) ReadTx(ctx context.Context, fn func(pgx.Tx) error) error {
+	r := db.reader(ctx)
+	if r == nil {
+		return readTx(ctx, db.pool, fn)
+	}
+
+	err := readTx(ctx, r.pool, fn)
+	if err != nil && db.fallback(ctx, r, err) {
+		return readTx(ctx, db.pool, fn)
+	}
+	return err
+}
+
+func readTx(ctx context.Context, pool *pgxpool.Pool, fn func(pgx.Tx) error) error {
+	tx, err := pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		return err
+	}
+	// Транзакция только читает, поэтому после ошибки или паники в fn ее достаточно откатить
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// pgx возвращает ошибку QueryRow только при Scan, поэтому повтор на основной БД выполняется там же
+type replicaRow struct {
+	db      *Client
+	replica *replica
+	ctx     context.Context
+	sql     string
+	args    []interface{}
+}
+
+func (r *replicaRow) Scan(dest ...interface{}) error {
+	err := r.replica.pool.QueryRow(r.ctx, r.sql, r.args...).Scan(dest...)
+	if err != nil && r.db.fallback(r.ctx, r.replica, err) {
+		return r.db.pool.QueryRow(r.ctx, r.sql, r.args...).Scan(dest...)
+	}
+	return err
+}
+
+// Состояние реплик
+func (db *Client) ReplicaStats() []ReplicaStat {
+	stats := make([]ReplicaStat, 0, len(db.replicas))
+	for _, r := range db.replicas {
+		stats = append(stats, ReplicaStat{
+			Host:    r.host,
+			Healthy: r.healthy.Load(),
+			Lag:     time.Duration(r.lag.Load()),
+		})
+	}
+	return stats
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newReplica(host string, healthy bool, lag time.Duration) *replica {
+	r := &replica{host: host}
+	r.healthy.Store(healthy)
+	r.lag.Store(int64(lag))
+	return r
+}
+
+func TestReaderSelection(t *testing.T) {
+	db := &Client{
+		maxReplicaLag: time.Second,
+		replicas: []*replica{
+			newReplica("a", true, 0),
+			newReplica("unhealthy", false, 0),
+			newReplica("lagging", true, 5*time.Second),
+			newReplica("b", true, 500*time.Millisecond),
+		},
+	}
+
+	used := make(map[string]int)
+	for range 10 {
+		used[db.reader(context.Background()).host]++
+	}
+	assert.Equal(t, map[string]int{"a": 5, "b": 5}, used)
+
+	assert.Nil(t, db.reader(WithPrimary(context.Background())))
+
+	db.replicas[0].healthy.Store(false)
+	db.replicas[3].lag.Store(int64(2 * time.Second))
+	assert.Nil(t, db.reader(context.Background()), "reads go to primary when no replica qualifies")
+
+	assert.Nil(t, (&Client{}).reader(context.Background()), "reads go to primary without replicas")
+}
+
+func TestReplicaFallback(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name            string
+		ctx             context.Context
+		err             error
+		expectedRetry   bool
+		expectedHealthy bool
+	}{
+		{name: "connection error", ctx: context.Background(), err: errors.New("connection refused"), expectedRetry: true},
+		{name: "postgres error", ctx: context.Background(), err: &pgconn.PgError{Code: "42P01"}, expectedHealthy: true},
+		{name: "no rows", ctx: context.Background(), err: pgx.ErrNoRows, expectedHealthy: true},
+		{name: "canceled request", ctx: canceled, err: context.Canceled, expectedHealthy: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newReplica("a", true, 0)
+			db := &Client{replicas: []*replica{r}}
+
+			assert.Equal(t, tt.expectedRetry, db.fallback(tt.ctx, r, tt.err))
+			assert.Equal(t, tt.expectedHealthy, r.healthy.Load())
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "infotecstechtask/pkg/database"
+
+// Трейсер SQL запросов
+//
+// В pgx v4 нет хуков на начало запроса, поэтому используется логгер: pgx вызывает его после выполнения запроса
+// с текстом и длительностью, и спан создается задним числом. Спаны создаются только внутри уже записываемого трейса
+type queryTracer struct{}
+
+func (queryTracer) Log(ctx context.Context, level pgx.LogLevel, msg string, data map[string]interface{}) {
+	if !trace.SpanFromContext(ctx).IsRecording() {
+		return
+	}
+
+	sql, ok := data["sql"].(string)
+	if !ok {
+		return
+	}
+
+	end := time.Now()
+	start := end
+	if duration, ok := data["time"].(time.Duration); ok {
+		start = end.Add(-duration)
+	}
+
+	_, span := otel.Tracer(tracerName).Start(ctx, "db."+msg,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.statement", sql),
+		),
+	)
+	if level == pgx.LogLevelError {
+		if err, ok := data["err"].(error); ok {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	span.End(trace.WithTimestamp(end))
+}
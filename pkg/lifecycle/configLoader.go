@@ -0,0 +1,11 @@
+package lifecycle
+
+import "time"
+
+// Структура, хранящая в себе параметры остановки приложения
+// Значения собираются пакетом internal/config
+//
+// ShutdownTimeout - общее время на завершение запросов, остановку фоновых процессов и освобождение ресурсов
+type Config struct {
+	ShutdownTimeout time.Duration
+}
@@ -0,0 +1,239 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Коды завершения процесса
+const (
+	ExitOK    = 0
+	ExitError = 1
+)
+
+// Сервер, которым управляет менеджер, реализуется *http.Server
+// После Shutdown или Close метод ListenAndServe должен вернуть nil или http.ErrServerClosed,
+// Shutdown дожидается завершения текущих запросов, Close прерывает их
+type Server interface {
+	ListenAndServe() error
+	Shutdown(ctx context.Context) error
+	Close() error
+}
+
+type server struct {
+	name   string
+	server Server
+}
+
+type worker struct {
+	name   string
+	run    func(ctx context.Context)
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type hook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// Менеджер жизненного цикла приложения
+//
+// Run запускает фоновые процессы и http серверы и ждет SIGINT/SIGTERM или падения одного из серверов.
+// Остановка выполняется по шагам: хуки BeforeShutdown (например, переключение готовности), остановка серверов
+// с ожиданием завершения запросов, остановка фоновых процессов в порядке, обратном регистрации, и хуки OnShutdown
+// (закрытие пула соединений и т.п.) также в обратном порядке. На все шаги остановки отводится Config.ShutdownTimeout
+type Manager struct {
+	config Config
+
+	servers        []server
+	workers        []*worker
+	beforeShutdown []hook
+	onShutdown     []hook
+
+	// Контекст, от которого наследуются контексты запросов, отменяется, если запросы не завершились за отведенное время
+	serveCtx    context.Context
+	cancelServe context.CancelFunc
+}
+
+func New(config Config) *Manager {
+	serveCtx, cancelServe := context.WithCancel(context.Background())
+
+	return &Manager{
+		config:      config,
+		serveCtx:    serveCtx,
+		cancelServe: cancelServe,
+	}
+}
+
+// Функция добавляет сервер
+// Контексты запросов http сервера будут отменены при принудительной остановке, остальные серверы прерывают запросы в Close
+func (m *Manager) AddServer(name string, srv Server) {
+	if httpServer, ok := srv.(*http.Server); ok {
+		httpServer.BaseContext = func(net.Listener) context.Context {
+			return m.serveCtx
+		}
+	}
+	m.servers = append(m.servers, server{name: name, server: srv})
+}
+
+// Адрес сервера для логов
+func serverAddr(srv Server) string {
+	switch srv := srv.(type) {
+	case *http.Server:
+		return srv.Addr
+	case interface{ Addr() string }:
+		return srv.Addr()
+	}
+	return ""
+}
+
+// Функция добавляет фоновый процесс, run должен завершаться при отмене контекста
+func (m *Manager) AddWorker(name string, run func(ctx context.Context)) {
+	m.workers = append(m.workers, &worker{name: name, run: run})
+}
+
+// Функция добавляет хук, который выполняется сразу после получения сигнала, до остановки серверов
+func (m *Manager) BeforeShutdown(name string, fn func(ctx context.Context) error) {
+	m.beforeShutdown = append(m.beforeShutdown, hook{name: name, fn: fn})
+}
+
+// Функция добавляет хук, который выполняется после остановки серверов и фоновых процессов
+func (m *Manager) OnShutdown(name string, fn func(ctx context.Context) error) {
+	m.onShutdown = append(m.onShutdown, hook{name: name, fn: fn})
+}
+
+// Функция запускает приложение и возвращает код завершения процесса
+// Код ExitError возвращается, если сервер упал или какой-то из шагов остановки завершился ошибкой
+func (m *Manager) Run(ctx context.Context) int {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	exitCode := ExitOK
+
+	for _, w := range m.workers {
+		workerCtx, cancel := context.WithCancel(context.Background())
+		w.cancel = cancel
+		w.done = make(chan struct{})
+		go func() {
+			defer close(w.done)
+			w.run(workerCtx)
+		}()
+	}
+
+	serverErrors := make(chan error, len(m.servers))
+	for _, s := range m.servers {
+		go func() {
+			slog.Info("Starting server", "server", s.name, "addr", serverAddr(s.server))
+			if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.Error("Server failed", "server", s.name, "error", err)
+				serverErrors <- err
+			}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+		slog.Info("Shutdown signal received")
+	case <-serverErrors:
+		exitCode = ExitError
+	}
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), m.config.ShutdownTimeout)
+	defer cancel()
+
+	if !m.runHooks(shutdownCtx, m.beforeShutdown, false) {
+		exitCode = ExitError
+	}
+	if !m.stopServers(shutdownCtx) {
+		exitCode = ExitError
+	}
+	if !m.stopWorkers(shutdownCtx) {
+		exitCode = ExitError
+	}
+	if !m.runHooks(shutdownCtx, m.onShutdown, true) {
+		exitCode = ExitError
+	}
+
+	slog.Info("Shutdown complete", "exit_code", exitCode)
+	return exitCode
+}
+
+// Серверы останавливаются параллельно, запросы, не завершившиеся к дедлайну, отменяются, а соединения закрываются
+func (m *Manager) stopServers(ctx context.Context) bool {
+	var (
+		wg sync.WaitGroup
+		ok = true
+		mu sync.Mutex
+	)
+
+	for _, s := range m.servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := s.server.Shutdown(ctx); err != nil {
+				slog.Error("Server did not drain in time, closing connections", "server", s.name, "error", err)
+				m.cancelServe()
+				_ = s.server.Close()
+
+				mu.Lock()
+				ok = false
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	m.cancelServe()
+
+	return ok
+}
+
+func (m *Manager) stopWorkers(ctx context.Context) bool {
+	ok := true
+
+	for i := len(m.workers) - 1; i >= 0; i-- {
+		w := m.workers[i]
+		w.cancel()
+
+		select {
+		case <-w.done:
+			slog.Info("Worker stopped", "worker", w.name)
+		case <-ctx.Done():
+			slog.Error("Worker did not stop in time", "worker", w.name)
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+func (m *Manager) runHooks(ctx context.Context, hooks []hook, reverse bool) bool {
+	ok := true
+
+	for i := range hooks {
+		h := hooks[i]
+		if reverse {
+			h = hooks[len(hooks)-1-i]
+		}
+
+		start := time.Now()
+		if err := h.fn(ctx); err != nil {
+			slog.Error("Shutdown step failed", "step", h.name, "error", err)
+			ok = false
+			continue
+		}
+		slog.Info("Shutdown step completed", "step", h.name, "duration", time.Since(start))
+	}
+
+	return ok
+}
@@ -0,0 +1,117 @@
+package lifecycle
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	mu    sync.Mutex
+	steps []string
+}
+
+func (r *recorder) add(step string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.steps = append(r.steps, step)
+}
+
+func (r *recorder) worker(name string) func(ctx context.Context) {
+	return func(ctx context.Context) {
+		<-ctx.Done()
+		r.add("worker " + name)
+	}
+}
+
+func (r *recorder) hook(name string) func(ctx context.Context) error {
+	return func(context.Context) error {
+		r.add(name)
+		return nil
+	}
+}
+
+func TestRunStopsInOrder(t *testing.T) {
+	rec := &recorder{}
+	manager := New(Config{ShutdownTimeout: time.Second})
+
+	srv := &http.Server{Addr: "127.0.0.1:0", Handler: http.NotFoundHandler()}
+	manager.AddServer("http", srv)
+	manager.AddWorker("first", rec.worker("first"))
+	manager.AddWorker("second", rec.worker("second"))
+	manager.BeforeShutdown("readiness", rec.hook("readiness"))
+	manager.OnShutdown("database", rec.hook("database"))
+	manager.OnShutdown("tracing", rec.hook("tracing"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	assert.Equal(t, ExitOK, manager.Run(ctx))
+	assert.Equal(t, []string{"readiness", "worker second", "worker first", "tracing", "database"}, rec.steps)
+	assert.ErrorIs(t, srv.ListenAndServe(), http.ErrServerClosed)
+}
+
+// Сервер без http, например gRPC: ListenAndServe блокируется до Shutdown и возвращает nil
+type testServer struct {
+	stopped  chan struct{}
+	shutdown bool
+}
+
+func (s *testServer) ListenAndServe() error {
+	<-s.stopped
+	return nil
+}
+
+func (s *testServer) Shutdown(context.Context) error {
+	s.shutdown = true
+	close(s.stopped)
+	return nil
+}
+
+func (s *testServer) Close() error {
+	return nil
+}
+
+func TestRunStopsCustomServer(t *testing.T) {
+	srv := &testServer{stopped: make(chan struct{})}
+	manager := New(Config{ShutdownTimeout: time.Second})
+	manager.AddServer("grpc", srv)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	assert.Equal(t, ExitOK, manager.Run(ctx))
+	assert.True(t, srv.shutdown)
+}
+
+func TestRunFailsWhenServerCannotStart(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	manager := New(Config{ShutdownTimeout: time.Second})
+	manager.AddServer("http", &http.Server{Addr: listener.Addr().String()})
+
+	assert.Equal(t, ExitError, manager.Run(context.Background()))
+}
+
+func TestRunFailsWhenWorkerDoesNotStop(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	manager := New(Config{ShutdownTimeout: 50 * time.Millisecond})
+	manager.AddWorker("stuck", func(context.Context) {
+		<-release
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Equal(t, ExitError, manager.Run(ctx))
+}
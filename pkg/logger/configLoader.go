@@ -0,0 +1,18 @@
+package logger
+
+import "log/slog"
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Список поддерживаемых форматов
+var Formats = []string{FormatJSON, FormatText}
+
+// Структура, хранящая в себе параметры логирования
+// Значения собираются пакетом internal/config
+type Config struct {
+	Level  slog.Level
+	Format string
+}
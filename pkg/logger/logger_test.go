@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupAddsRequestIDFromContext(t *testing.T) {
+	defaultLogger := slog.Default()
+	defer slog.SetDefault(defaultLogger)
+
+	var buf bytes.Buffer
+	logger := Setup(&buf, Config{Level: slog.LevelInfo, Format: FormatJSON})
+
+	ctx := WithRequestID(context.Background(), "req-42")
+	logger.With("component", "test").DebugContext(ctx, "skipped")
+	logger.With("component", "test").InfoContext(ctx, "written")
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "written", record["msg"])
+	assert.Equal(t, "req-42", record["request_id"])
+	assert.Equal(t, "test", record["component"])
+}
@@ -0,0 +1,4 @@
+INSERT INTO adjustments (id, wallet_id, direction, amount, reason_code, comment, status, created_by, created_at) VALUES
+('7c9e6679-7425-40de-944b-e07fc1f90ae7', 'b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10', 'credit', 2550, 'correction', 'Duplicate charge', 'pending', '5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e01', '2025-08-04'),
+('9b2d1e3f-5a6c-4d7e-8f90-1a2b3c4d5e6f', 'b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11', 'debit', 20000, 'chargeback', '', 'pending', '5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e01', '2025-08-03'),
+('2f1e0d9c-8b7a-4c6d-9e5f-4a3b2c1d0e9f', 'b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12', 'debit', 500, 'fee', '', 'pending', '5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e01', '2025-08-02');
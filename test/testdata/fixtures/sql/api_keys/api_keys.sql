@@ -0,0 +1,3 @@
+INSERT INTO api_keys (key_hash, owner_id, role) VALUES
+('45aad74a22b04b0bedeb467aa28e2c6a90a595c68618b2b557081adec152c1f2', '5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e01', 'client'),
+('daed94772d0c7ff44878246e66f4d26f087dd12cf0b11c1d5c04628e433f9495', '5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e03', 'admin');
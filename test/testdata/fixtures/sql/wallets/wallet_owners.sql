@@ -0,0 +1,2 @@
+UPDATE wallets SET owner_id = '5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e01' WHERE id IN ('b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a10', 'b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11');
+UPDATE wallets SET owner_id = '5f0c2a8e-3b1d-4c3e-9a57-2b9f3c1d7e02' WHERE id = 'b0eebc99-9c0b-4ef8-bb6d-6bb9bd380a13';
@@ -3,9 +3,8 @@ package testutils
 import (
 	"context"
 	"fmt"
-	"io/ioutil"
-	"log"
-	"path/filepath"
+	"infotecstechtask/init/migrations"
+	"infotecstechtask/pkg/database"
 	"time"
 
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -21,7 +20,7 @@ type PGTestContainer struct {
 	Pool      *pgxpool.Pool
 }
 
-func StartPGContainer(ctx context.Context, migrationsPath string) (*PGTestContainer, error) {
+func StartPGContainer(ctx context.Context) (*PGTestContainer, error) {
 	container, err := postgres.Run(ctx,
 		"postgres:17-alpine",
 		postgres.WithDatabase("test"),
@@ -45,7 +44,12 @@ func StartPGContainer(ctx context.Context, migrationsPath string) (*PGTestContai
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
 
-	if err := applyMigrations(ctx, pool, migrationsPath); err != nil {
+	// Миграции применяются тем же кодом, что и командой migrate up
+	migrator, err := database.NewMigrator(database.NewClientWithPool(pool), migrations.FS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
 		return nil, fmt.Errorf("failed to apply migrations: %w", err)
 	}
 
@@ -65,33 +69,3 @@ func (pg *PGTestContainer) Close(ctx context.Context) error {
 	
 	return nil
 }
-
-func applyMigrations(ctx context.Context, pool *pgxpool.Pool, path string) error {
-	log.Printf("Applying migrations from: %s", path)
-
-	files, err := ioutil.ReadDir(path)
-	if err != nil {
-		return fmt.Errorf("failed to read migrations directory: %w", err)
-	}
-
-	for _, file := range files {
-		if filepath.Ext(file.Name()) != ".sql" {
-			continue
-		}
-
-		fullPath := filepath.Join(path, file.Name())
-		content, err := ioutil.ReadFile(fullPath)
-		if err != nil {
-			return fmt.Errorf("failed to read migration file %s: %w", file.Name(), err)
-		}
-
-		if _, err := pool.Exec(ctx, string(content)); err != nil {
-			log.Printf("Migration content:\n%s", string(content))
-			return fmt.Errorf("failed to execute migration %s: %w", file.Name(), err)
-		}
-
-		log.Printf("Applied migration: %s", file.Name())
-	}
-
-	return nil
-}
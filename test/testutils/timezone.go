@@ -0,0 +1,18 @@
+package testutils
+
+import (
+	"testing"
+	"time"
+)
+
+// Функция переключает локальный часовой пояс на UTC+3 до конца теста
+// Так проверяется, что время записывается в колонки TIMESTAMP в UTC независимо от пояса сервера
+//
+// Используется только для тестирования
+func SetNonUTCLocal(t testing.TB) {
+	local := time.Local
+	time.Local = time.FixedZone("UTC+3", 3*60*60)
+	t.Cleanup(func() {
+		time.Local = local
+	})
+}